@@ -1,17 +1,45 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/feproldo/effective-mobile/docs"
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/billing"
 	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	auditHandler "github.com/feproldo/effective-mobile/internal/handlers/audit"
+	budgetHandler "github.com/feproldo/effective-mobile/internal/handlers/budgets"
+	catalogHandler "github.com/feproldo/effective-mobile/internal/handlers/catalog"
+	categoryHandler "github.com/feproldo/effective-mobile/internal/handlers/categories"
+	eventHandler "github.com/feproldo/effective-mobile/internal/handlers/events"
+	notificationHandler "github.com/feproldo/effective-mobile/internal/handlers/notifications"
+	rateHandler "github.com/feproldo/effective-mobile/internal/handlers/rates"
 	subscriptionHandler "github.com/feproldo/effective-mobile/internal/handlers/subscriptions"
+	tagHandler "github.com/feproldo/effective-mobile/internal/handlers/tags"
+	tenantHandler "github.com/feproldo/effective-mobile/internal/handlers/tenants"
+	webhookHandler "github.com/feproldo/effective-mobile/internal/handlers/webhooks"
 	"github.com/feproldo/effective-mobile/internal/middlewares"
+	"github.com/feproldo/effective-mobile/internal/notify"
+	"github.com/feproldo/effective-mobile/internal/ratelimit"
+	auditService "github.com/feproldo/effective-mobile/internal/services/audit"
+	budgetService "github.com/feproldo/effective-mobile/internal/services/budgets"
+	catalogService "github.com/feproldo/effective-mobile/internal/services/catalog"
+	categoryService "github.com/feproldo/effective-mobile/internal/services/categories"
+	eventService "github.com/feproldo/effective-mobile/internal/services/events"
+	notificationService "github.com/feproldo/effective-mobile/internal/services/notifications"
+	rateService "github.com/feproldo/effective-mobile/internal/services/rates"
 	subscriptionService "github.com/feproldo/effective-mobile/internal/services/subscriptions"
+	tagService "github.com/feproldo/effective-mobile/internal/services/tags"
+	tenantService "github.com/feproldo/effective-mobile/internal/services/tenants"
+	webhookService "github.com/feproldo/effective-mobile/internal/services/webhooks"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog"
@@ -21,6 +49,11 @@ import (
 
 // @title		Subscriptions service
 // @version	1.0
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				JWT в формате "Bearer <token>"
 func main() {
 	godotenv.Load()
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
@@ -37,32 +70,365 @@ func main() {
 
 	queries := db.New(conn)
 
-	subsService := subscriptionService.NewService(queries)
+	datePrecision := getEnv("DATE_PRECISION", billing.PrecisionMonth)
+	if !billing.ValidPrecision(datePrecision) {
+		log.Error().Err(billing.ErrInvalidPrecision).Msg("DATE_PRECISION configuration error")
+		return
+	}
+
+	reminderDays, err := strconv.Atoi(getEnv("REMINDER_DAYS", "3"))
+	if err != nil || reminderDays < 0 {
+		log.Error().Err(err).Msg("REMINDER_DAYS configuration error")
+		return
+	}
+
+	subsService := subscriptionService.NewService(conn, queries, subscriptionService.Config{
+		DatePrecision: datePrecision,
+		ReminderDays:  reminderDays,
+	})
 	subsHandler := subscriptionHandler.NewHandler(subsService)
 
+	tenantsService := tenantService.NewService(conn, queries)
+	tenantsHandler := tenantHandler.NewHandler(tenantsService)
+
+	auditsService := auditService.NewService(conn, queries)
+	auditsHandler := auditHandler.NewHandler(auditsService)
+
+	ratesService := rateService.NewService(conn, queries)
+	ratesHandler := rateHandler.NewHandler(ratesService)
+
+	catalogsService := catalogService.NewService(conn, queries)
+	catalogsHandler := catalogHandler.NewHandler(catalogsService)
+
+	categoriesService := categoryService.NewService(queries)
+	categoriesHandler := categoryHandler.NewHandler(categoriesService)
+
+	tagsService := tagService.NewService(conn, queries)
+	tagsHandler := tagHandler.NewHandler(tagsService)
+
+	budgetsService := budgetService.NewService(conn, queries)
+	budgetsHandler := budgetHandler.NewHandler(budgetsService)
+
+	webhooksService := webhookService.NewService(conn, queries)
+	webhooksHandler := webhookHandler.NewHandler(webhooksService)
+
+	eventsBuffer, err := strconv.Atoi(getEnv("EVENTS_BUFFER_SIZE", "1000"))
+	if err != nil || eventsBuffer < 0 {
+		log.Error().Err(err).Msg("EVENTS_BUFFER_SIZE configuration error")
+		return
+	}
+
+	eventsService := eventService.NewService(conn, queries, eventService.Config{BufferSize: eventsBuffer})
+	eventsHandler := eventHandler.NewHandler(eventsService)
+	go eventsService.Run(context.Background(), os.Getenv("DATABASE_URL"))
+
+	notificationsService := notificationService.NewService(conn, queries, notificationService.Config{ReminderDays: reminderDays})
+	notificationsHandler := notificationHandler.NewHandler(notificationsService)
+
 	router := chi.NewRouter()
 
+	readLimit, err := ratelimit.ParseLimit(getEnv("RATE_LIMIT_READ", "300/1m"))
+	if err != nil {
+		log.Error().Err(err).Msg("RATE_LIMIT_READ configuration error")
+		return
+	}
+	writeLimit, err := ratelimit.ParseLimit(getEnv("RATE_LIMIT_WRITE", "60/1m"))
+	if err != nil {
+		log.Error().Err(err).Msg("RATE_LIMIT_WRITE configuration error")
+		return
+	}
+
+	ipLimit, err := ratelimit.ParseLimit(getEnv("RATE_LIMIT_IP", "600/1m"))
+	if err != nil {
+		log.Error().Err(err).Msg("RATE_LIMIT_IP configuration error")
+		return
+	}
+
+	// Лимиты групп маршрутов: RATE_LIMIT_<ГРУППА>_READ и RATE_LIMIT_<ГРУППА>_WRITE, по умолчанию - общие
+	groupLimits := map[string]middlewares.RateLimits{}
+	maxPer := max(readLimit.Per, writeLimit.Per, ipLimit.Per)
+	for _, group := range rateLimitGroups {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(group, "-", "_"))
+		limits := middlewares.RateLimits{Read: readLimit, Write: writeLimit}
+		if value := os.Getenv(prefix + "_READ"); value != "" {
+			if limits.Read, err = ratelimit.ParseLimit(value); err != nil {
+				log.Error().Err(err).Msg(prefix + "_READ configuration error")
+				return
+			}
+		}
+		if value := os.Getenv(prefix + "_WRITE"); value != "" {
+			if limits.Write, err = ratelimit.ParseLimit(value); err != nil {
+				log.Error().Err(err).Msg(prefix + "_WRITE configuration error")
+				return
+			}
+		}
+		groupLimits[group] = limits
+		maxPer = max(maxPer, limits.Read.Per, limits.Write.Per)
+	}
+
+	limiterStore := ratelimit.NewMemoryStore()
+	go limiterStore.Cleanup(context.Background(), time.Minute, maxPer)
+
+	// Лимит групп ставится после аутентификации, чтобы клиент определялся по проверенному токену
+	rateLimit := func(group string) func(http.Handler) http.Handler {
+		return middlewares.RateLimit(limiterStore, group, groupLimits[group])
+	}
+
+	router.Use(middleware.RequestID)
 	router.Use(middlewares.ZeroLogLogger)
 
-	router.Get("/swagger/*", httpSwagger.Handler(
+	router.With(rateLimit("swagger")).Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 	))
 
+	authConfig := auth.Config{
+		HMACSecret: os.Getenv("JWT_HS256_SECRET"),
+		JWKS:       os.Getenv("JWT_JWKS"),
+		Issuer:     os.Getenv("JWT_ISSUER"),
+		Audience:   os.Getenv("JWT_AUDIENCE"),
+		AdminRole:  os.Getenv("JWT_ADMIN_ROLE"),
+	}
+
+	var verifier *auth.Verifier
+	if authConfig.Enabled() {
+		verifier, err = auth.NewVerifier(authConfig)
+		if err != nil {
+			log.Error().Err(err).Msg("JWT configuration error")
+			return
+		}
+	} else {
+		log.Warn().Msg("JWT authentication is disabled")
+	}
+
+	authenticate := func(next http.Handler) http.Handler { return next }
+	if verifier != nil {
+		// Запросы с одного IP ограничиваются до проверки токена, в том числе с неверными и просроченными токенами
+		limitIP, verify := middlewares.RateLimitIP(limiterStore, ipLimit), middlewares.Authenticate(verifier)
+		authenticate = func(next http.Handler) http.Handler { return limitIP(verify(next)) }
+	}
+
+	router.Route("/tenants", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("tenants"), middlewares.RequireAdmin)
+
+		r.Get("/", tenantsHandler.List)
+		r.Post("/", tenantsHandler.Create)
+	})
+
+	router.Route("/audit", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("audit"), middlewares.RequireAdmin, middlewares.Tenant(tenantsService.Exists))
+
+		r.Get("/", auditsHandler.List)
+	})
+
+	router.Route("/exchange-rates", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("exchange-rates"))
+
+		r.Get("/", ratesHandler.List)
+		r.With(middlewares.RequireAdmin).Post("/", ratesHandler.Save)
+	})
+
+	router.Route("/services", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("services"), middlewares.Tenant(tenantsService.Exists))
+
+		r.Get("/", catalogsHandler.List)
+		r.Get("/{id}", catalogsHandler.Get)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireAdmin)
+
+			r.Post("/", catalogsHandler.Create)
+			r.Put("/{id}", catalogsHandler.Update)
+			r.Delete("/{id}", catalogsHandler.Delete)
+			r.Post("/link", catalogsHandler.Link)
+		})
+	})
+
+	router.Route("/categories", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("categories"))
+
+		r.Get("/", categoriesHandler.List)
+	})
+
+	router.Route("/tags", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("tags"), middlewares.Tenant(tenantsService.Exists))
+
+		r.Get("/", tagsHandler.List)
+		r.With(middlewares.RequireAdmin).Post("/rename", tagsHandler.Rename)
+		r.With(middlewares.RequireAdmin).Post("/merge", tagsHandler.Merge)
+	})
+
 	router.Route("/subscriptions", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("subscriptions"), middlewares.Tenant(tenantsService.Exists))
+
 		r.Get("/", subsHandler.List)
 		r.Get("/{id}", subsHandler.Get)
+		r.Get("/{id}/history", subsHandler.History)
+		r.Get("/{id}/prices", subsHandler.Prices)
 
 		r.Get("/user/{user_id}", subsHandler.GetByUserId)
 
 		r.Post("/", subsHandler.Create)
+		r.Post("/import", subsHandler.Import)
 
 		r.Delete("/{id}", subsHandler.Delete)
+		r.Post("/{id}/restore", subsHandler.Restore)
+		r.Post("/{id}/pause", subsHandler.Pause)
+		r.Post("/{id}/resume", subsHandler.Resume)
+		r.Post("/{id}/cancel", subsHandler.Cancel)
+		r.Post("/{id}/reactivate", subsHandler.Reactivate)
 
 		r.Put("/{id}", subsHandler.Update)
 
 		r.Get("/sum", subsHandler.Sum)
+		r.Get("/report", subsHandler.Report)
+		r.Get("/settlement", subsHandler.Settlement)
+		r.Get("/forecast", subsHandler.Forecast)
+		r.Get("/trials/ending", subsHandler.TrialsEnding)
+		r.Get("/upcoming", subsHandler.Upcoming)
+		r.Get("/events", eventsHandler.Stream)
+
+		r.With(middlewares.RequireAdmin).Post("/price-changes", subsHandler.ChangePrice)
+	})
+
+	router.Route("/webhooks", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("webhooks"), middlewares.RequireAdmin, middlewares.Tenant(tenantsService.Exists))
+
+		r.Get("/", webhooksHandler.List)
+		r.Post("/", webhooksHandler.Create)
+		r.Get("/{id}", webhooksHandler.Get)
+		r.Put("/{id}", webhooksHandler.Update)
+		r.Delete("/{id}", webhooksHandler.Delete)
+		r.Get("/{id}/deliveries", webhooksHandler.Deliveries)
+		r.Get("/{id}/deliveries/{delivery_id}", webhooksHandler.Delivery)
+		r.Post("/{id}/deliveries/{delivery_id}/replay", webhooksHandler.Replay)
+	})
+
+	router.Route("/notifications", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("notifications"))
+
+		r.Get("/preview", notificationsHandler.Preview)
+	})
+
+	// Календарные приложения не передают заголовки, поэтому календарь доступен по секретному токену из URL
+	router.With(rateLimit("calendar")).Get("/users/{user_id}/calendar.ics", subsHandler.Calendar)
+
+	router.Route("/users/{user_id}", func(r chi.Router) {
+		r.Use(authenticate, rateLimit("users"), middlewares.Tenant(tenantsService.Exists))
+
+		r.Get("/budgets", budgetsHandler.List)
+		r.Post("/budgets", budgetsHandler.Create)
+		r.Put("/budgets/{id}", budgetsHandler.Update)
+		r.Delete("/budgets/{id}", budgetsHandler.Delete)
+
+		r.Get("/budget-status", subsHandler.BudgetStatus)
+
+		r.Post("/statement-analysis", subsHandler.AnalyzeStatement)
+		r.Post("/statement-analysis/confirm", subsHandler.ConfirmStatement)
+
+		r.Post("/calendar-token", subsHandler.CreateCalendarToken)
+		r.Delete("/calendar-token", subsHandler.RevokeCalendarToken)
+
+		r.Get("/notification-settings", notificationsHandler.Settings)
+		r.Put("/notification-settings", notificationsHandler.Save)
 	})
 
+	retention, err := time.ParseDuration(getEnv("SOFT_DELETE_RETENTION", "720h"))
+	if err != nil {
+		log.Error().Err(err).Msg("SOFT_DELETE_RETENTION configuration error")
+		return
+	}
+	purgeInterval, err := time.ParseDuration(getEnv("PURGE_INTERVAL", "1h"))
+	if err != nil {
+		log.Error().Err(err).Msg("PURGE_INTERVAL configuration error")
+		return
+	}
+	if retention > 0 {
+		go subsService.RunPurge(context.Background(), retention, purgeInterval)
+	}
+	eventRetention, err := time.ParseDuration(getEnv("WEBHOOK_EVENT_RETENTION", "720h"))
+	if err != nil {
+		log.Error().Err(err).Msg("WEBHOOK_EVENT_RETENTION configuration error")
+		return
+	}
+	if eventRetention > 0 {
+		go webhooksService.RunPurge(context.Background(), eventRetention, purgeInterval)
+	}
+
+	reminderInterval, err := time.ParseDuration(getEnv("REMINDER_INTERVAL", "1h"))
+	if err != nil {
+		log.Error().Err(err).Msg("REMINDER_INTERVAL configuration error")
+		return
+	}
+	reminderAttempts, err := strconv.Atoi(getEnv("REMINDER_MAX_ATTEMPTS", "5"))
+	if err != nil || reminderAttempts < 1 {
+		log.Error().Err(err).Msg("REMINDER_MAX_ATTEMPTS configuration error")
+		return
+	}
+	reminderRetryDelay, err := time.ParseDuration(getEnv("REMINDER_RETRY_DELAY", "1m"))
+	if err != nil {
+		log.Error().Err(err).Msg("REMINDER_RETRY_DELAY configuration error")
+		return
+	}
+	notifier := notify.Multi{notify.Log{}}
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		smtpTimeout, err := time.ParseDuration(getEnv("SMTP_TIMEOUT", "30s"))
+		if err != nil {
+			log.Error().Err(err).Msg("SMTP_TIMEOUT configuration error")
+			return
+		}
+		mailer, err := notify.NewSMTP(notify.SMTPConfig{
+			Host:     smtpHost,
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     getEnv("SMTP_FROM", "noreply@localhost"),
+			TLS:      getEnv("SMTP_TLS", notify.TLSStartTLS),
+			Timeout:  smtpTimeout,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("SMTP configuration error")
+			return
+		}
+		notifier = append(notifier, mailer)
+	}
+	if reminderInterval > 0 {
+		go subsService.RunReminders(context.Background(), subscriptionService.ReminderConfig{
+			Interval:    reminderInterval,
+			Days:        reminderDays,
+			MaxAttempts: reminderAttempts,
+			RetryDelay:  reminderRetryDelay,
+		}, notifier)
+	}
+
+	webhookInterval, err := time.ParseDuration(getEnv("WEBHOOK_INTERVAL", "5s"))
+	if err != nil {
+		log.Error().Err(err).Msg("WEBHOOK_INTERVAL configuration error")
+		return
+	}
+	webhookAttempts, err := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	if err != nil || webhookAttempts < 1 {
+		log.Error().Err(err).Msg("WEBHOOK_MAX_ATTEMPTS configuration error")
+		return
+	}
+	webhookRetryDelay, err := time.ParseDuration(getEnv("WEBHOOK_RETRY_DELAY", "30s"))
+	if err != nil {
+		log.Error().Err(err).Msg("WEBHOOK_RETRY_DELAY configuration error")
+		return
+	}
+	webhookTimeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		log.Error().Err(err).Msg("WEBHOOK_TIMEOUT configuration error")
+		return
+	}
+	if webhookInterval > 0 {
+		go webhooksService.RunDeliveries(context.Background(), webhookService.Config{
+			Interval:    webhookInterval,
+			MaxAttempts: webhookAttempts,
+			RetryDelay:  webhookRetryDelay,
+			Timeout:     webhookTimeout,
+		})
+	}
+
 	port := os.Getenv("PORT")
 
 	log.Info().Msg("Service started on 0.0.0.0:" + port)
@@ -70,3 +436,16 @@ func main() {
 
 	http.ListenAndServe("0.0.0.0:"+port, router)
 }
+
+// Группы маршрутов со своими лимитами частоты запросов
+var rateLimitGroups = []string{
+	"swagger", "tenants", "audit", "exchange-rates", "services", "categories", "tags",
+	"subscriptions", "webhooks", "notifications", "users", "calendar",
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
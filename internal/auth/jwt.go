@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+type Config struct {
+	HMACSecret string
+	// Путь к файлу или URL с JWKS для RS256
+	JWKS      string
+	Issuer    string
+	Audience  string
+	AdminRole string
+}
+
+func (c Config) Enabled() bool {
+	return c.HMACSecret != "" || c.JWKS != ""
+}
+
+type Verifier struct {
+	cfg    Config
+	parser *jwt.Parser
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+
+	// Не даёт перечитывать JWKS одновременно. Проверка токенов известными ключами его не ждёт
+	refreshing  sync.Mutex
+	lastRefresh time.Time
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if cfg.AdminRole == "" {
+		cfg.AdminRole = "admin"
+	}
+
+	methods := []string{}
+	if cfg.HMACSecret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKS != "" {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v := &Verifier{
+		cfg:    cfg,
+		parser: jwt.NewParser(opts...),
+		keys:   map[string]*rsa.PublicKey{},
+	}
+
+	if cfg.JWKS != "" {
+		if err := v.refreshKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Role     string   `json:"role"`
+	Roles    []string `json:"roles"`
+	TenantID string   `json:"tenant_id"`
+}
+
+func (v *Verifier) Verify(raw string) (*Principal, error) {
+	var c claims
+	_, err := v.parser.ParseWithClaims(raw, &c, v.keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sub is not a valid user_id: %w", ErrUnauthorized, err)
+	}
+
+	return &Principal{
+		Subject:  c.Subject,
+		UserID:   userID,
+		Admin:    c.Role == v.cfg.AdminRole || slices.Contains(c.Roles, v.cfg.AdminRole),
+		TenantID: c.TenantID,
+	}, nil
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return []byte(v.cfg.HMACSecret), nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key := v.key(kid); key != nil {
+			return key, nil
+		}
+		// Ключи могли ротироваться - перечитываем JWKS, но не чаще раза в минуту
+		if err := v.refreshKeys(); err != nil {
+			return nil, err
+		}
+		if key := v.key(kid); key != nil {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+func (v *Verifier) key(kid string) *rsa.PublicKey {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key
+		}
+	}
+	return v.keys[kid]
+}
+
+type jwks struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// refreshKeys перечитывает JWKS без блокировки ключей и подменяет набор ключей целиком
+func (v *Verifier) refreshKeys() error {
+	v.refreshing.Lock()
+	defer v.refreshing.Unlock()
+
+	if time.Since(v.lastRefresh) < time.Minute {
+		return nil
+	}
+	v.lastRefresh = time.Now()
+
+	data, err := readJWKS(v.cfg.JWKS)
+	if err != nil {
+		return fmt.Errorf("can't load JWKS: %w", err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("can't decode JWKS: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return fmt.Errorf("JWKS key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return fmt.Errorf("JWKS key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.mu.Unlock()
+	return nil
+}
+
+func readJWKS(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
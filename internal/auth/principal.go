@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+// Principal - вызывающая сторона, полученная из JWT
+type Principal struct {
+	Subject string
+	UserID  uuid.UUID
+	Admin   bool
+	// Пустой, если в токене нет claim tenant_id
+	TenantID string
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
+
+// Scoped возвращает пользователя, которым ограничена выборка.
+// Если аутентификация выключена или вызывающий - админ, ограничения нет.
+func Scoped(ctx context.Context) (uuid.UUID, bool) {
+	p, ok := FromContext(ctx)
+	if !ok || p.Admin {
+		return uuid.Nil, false
+	}
+	return p.UserID, true
+}
@@ -0,0 +1,90 @@
+package billing
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	Weekly    = "weekly"
+	Monthly   = "monthly"
+	Quarterly = "quarterly"
+	Yearly    = "yearly"
+	Custom    = "custom"
+)
+
+// Точность дат при подсчёте стоимости: целые месяцы или дни с пропорциональным расчётом
+const (
+	PrecisionMonth = "month"
+	PrecisionDay   = "day"
+)
+
+var ErrInvalidPrecision = errors.New("date_precision must be month or day")
+
+var ErrInvalidPeriod = errors.New("billing_period must be one of weekly, monthly, quarterly, yearly, custom")
+
+// Period - периодичность списаний: раз в Weeks недель или раз в Months месяцев
+type Period struct {
+	Kind   string
+	Weeks  int
+	Months int
+}
+
+func ValidPrecision(precision string) bool {
+	return precision == PrecisionMonth || precision == PrecisionDay
+}
+
+// ParsePeriod возвращает периодичность. months используется только для custom.
+func ParsePeriod(kind string, months int) (Period, error) {
+	switch kind {
+	case Weekly:
+		return Period{Kind: kind, Weeks: 1}, nil
+	case Monthly, "":
+		return Period{Kind: Monthly, Months: 1}, nil
+	case Quarterly:
+		return Period{Kind: kind, Months: 3}, nil
+	case Yearly:
+		return Period{Kind: kind, Months: 12}, nil
+	case Custom:
+		if months < 1 {
+			return Period{}, fmt.Errorf("%w: billing_months must be positive for custom period", ErrInvalidPeriod)
+		}
+		return Period{Kind: kind, Months: months}, nil
+	}
+	return Period{}, ErrInvalidPeriod
+}
+
+// Nth возвращает дату n-го списания, считая от anchor.
+// Если в месяце нет дня anchor (31 число), списание переносится на последний день месяца.
+func (p Period) Nth(anchor time.Time, n int) time.Time {
+	if p.Weeks > 0 {
+		return anchor.AddDate(0, 0, 7*p.Weeks*n)
+	}
+	return AddMonths(anchor, p.Months*n)
+}
+
+// MonthlyEquivalent приводит цену за период к цене за месяц
+func (p Period) MonthlyEquivalent(price float64) float64 {
+	if p.Weeks > 0 {
+		return price * 52 / 12 / float64(p.Weeks)
+	}
+	return price / float64(p.Months)
+}
+
+// AddMonths прибавляет месяцы, не перескакивая в следующий месяц для коротких месяцев
+func AddMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(t.Day(), lastDay), 0, 0, 0, 0, t.Location())
+}
+
+// MonthStart возвращает первый день месяца
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// MonthEnd возвращает последний день месяца
+func MonthEnd(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location())
+}
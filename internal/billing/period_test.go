@@ -0,0 +1,114 @@
+package billing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		kind    string
+		months  int
+		want    Period
+		wantErr bool
+	}{
+		{kind: Weekly, want: Period{Kind: Weekly, Weeks: 1}},
+		{kind: Monthly, want: Period{Kind: Monthly, Months: 1}},
+		{kind: "", want: Period{Kind: Monthly, Months: 1}},
+		{kind: Quarterly, want: Period{Kind: Quarterly, Months: 3}},
+		{kind: Yearly, months: 5, want: Period{Kind: Yearly, Months: 12}},
+		{kind: Custom, months: 2, want: Period{Kind: Custom, Months: 2}},
+		{kind: Custom, months: 0, wantErr: true},
+		{kind: "daily", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePeriod(tt.kind, tt.months)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidPeriod) {
+				t.Errorf("ParsePeriod(%q, %d) error = %v, want ErrInvalidPeriod", tt.kind, tt.months, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePeriod(%q, %d) = %+v, %v, want %+v", tt.kind, tt.months, got, err, tt.want)
+		}
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		from   time.Time
+		months int
+		want   time.Time
+	}{
+		{date(2025, 1, 15), 1, date(2025, 2, 15)},
+		{date(2025, 1, 31), 1, date(2025, 2, 28)},
+		{date(2024, 1, 31), 1, date(2024, 2, 29)},
+		{date(2025, 1, 31), 2, date(2025, 3, 31)},
+		{date(2025, 3, 31), 1, date(2025, 4, 30)},
+		{date(2025, 11, 30), 3, date(2026, 2, 28)},
+		{date(2024, 2, 29), 12, date(2025, 2, 28)},
+		{date(2025, 3, 31), -1, date(2025, 2, 28)},
+	}
+
+	for _, tt := range tests {
+		if got := AddMonths(tt.from, tt.months); !got.Equal(tt.want) {
+			t.Errorf("AddMonths(%s, %d) = %s, want %s", tt.from.Format(time.DateOnly), tt.months, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestPeriodNth(t *testing.T) {
+	monthly, _ := ParsePeriod(Monthly, 0)
+	quarterly, _ := ParsePeriod(Quarterly, 0)
+	weekly, _ := ParsePeriod(Weekly, 0)
+
+	tests := []struct {
+		name   string
+		period Period
+		anchor time.Time
+		n      int
+		want   time.Time
+	}{
+		// Перенос на конец короткого месяца не сдвигает следующие списания
+		{"monthly end of february", monthly, date(2025, 1, 31), 1, date(2025, 2, 28)},
+		{"monthly after february", monthly, date(2025, 1, 31), 2, date(2025, 3, 31)},
+		{"quarterly", quarterly, date(2025, 11, 30), 1, date(2026, 2, 28)},
+		{"weekly", weekly, date(2025, 12, 29), 1, date(2026, 1, 5)},
+		{"first", monthly, date(2025, 7, 15), 0, date(2025, 7, 15)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.period.Nth(tt.anchor, tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s: Nth = %s, want %s", tt.name, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestMonthlyEquivalent(t *testing.T) {
+	tests := []struct {
+		kind   string
+		months int
+		price  float64
+		want   float64
+	}{
+		{Monthly, 0, 400, 400},
+		{Quarterly, 0, 900, 300},
+		{Yearly, 0, 1200, 100},
+		{Custom, 2, 500, 250},
+		{Weekly, 0, 120, 520},
+	}
+
+	for _, tt := range tests {
+		period, _ := ParsePeriod(tt.kind, tt.months)
+		if got := period.MonthlyEquivalent(tt.price); got != tt.want {
+			t.Errorf("%s: MonthlyEquivalent(%v) = %v, want %v", tt.kind, tt.price, got, tt.want)
+		}
+	}
+}
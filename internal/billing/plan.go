@@ -0,0 +1,233 @@
+package billing
+
+import (
+	"time"
+)
+
+// Plan - условия оплаты подписки
+type Plan struct {
+	Start time.Time
+	// Последний день действия подписки включительно, nil - бессрочная
+	End    *time.Time
+	Anchor time.Time
+	Period Period
+	// Обычная цена, действует вне ценовых фаз, если нет истории цен
+	Price    int
+	Currency string
+	// История обычной цены по возрастанию From
+	Prices []PricePoint
+	// Ценовые фазы (пробный период, промо-цена) в хронологическом порядке
+	Phases []Phase
+	// Паузы, в которые списаний нет
+	Pauses []Pause
+}
+
+// Pause - период без списаний
+type Pause struct {
+	Start time.Time
+	// Первый день после паузы, nil - пауза не завершена
+	End *time.Time
+}
+
+// Paused сообщает, приходится ли дата date на паузу
+func (p Plan) Paused(date time.Time) bool {
+	for _, pause := range p.Pauses {
+		if !date.Before(pause.Start) && (pause.End == nil || date.Before(*pause.End)) {
+			return true
+		}
+	}
+	return false
+}
+
+// pausedDays возвращает количество дней паузы в [from, to] включительно
+func (p Plan) pausedDays(from time.Time, to time.Time) int {
+	total := 0
+	for _, pause := range p.Pauses {
+		start, end := pause.Start, to
+		if pause.End != nil && pause.End.AddDate(0, 0, -1).Before(end) {
+			end = pause.End.AddDate(0, 0, -1)
+		}
+		if from.After(start) {
+			start = from
+		}
+		if !end.Before(start) {
+			total += days(start, end)
+		}
+	}
+	return total
+}
+
+const (
+	PhaseTrial = "trial"
+	PhasePromo = "promo"
+)
+
+// Phase - период, в который действует особая цена
+type Phase struct {
+	Kind  string
+	Price int
+	Start time.Time
+	// Последний день фазы включительно
+	End time.Time
+}
+
+// PricePoint - обычная цена, действующая с From до следующего изменения
+type PricePoint struct {
+	From  time.Time
+	Price int
+}
+
+// PriceAt возвращает цену списания в дату date с учётом ценовых фаз и истории цен.
+// До первой записи истории действует её цена.
+func (p Plan) PriceAt(date time.Time) int {
+	for _, phase := range p.Phases {
+		if !date.Before(phase.Start) && !date.After(phase.End) {
+			return phase.Price
+		}
+	}
+
+	if len(p.Prices) == 0 {
+		return p.Price
+	}
+
+	price := p.Prices[0].Price
+	for _, point := range p.Prices[1:] {
+		if date.Before(point.From) {
+			break
+		}
+		price = point.Price
+	}
+	return price
+}
+
+type Charge struct {
+	Date     time.Time
+	Amount   float64
+	Currency string
+}
+
+// Charges возвращает списания, попадающие в [from, to] включительно
+func (p Plan) Charges(from time.Time, to time.Time) []Charge {
+	if p.End != nil && p.End.Before(to) {
+		to = *p.End
+	}
+
+	charges := []Charge{}
+	for n := p.firstIndex(from); ; n++ {
+		date := p.Period.Nth(p.Anchor, n)
+		if date.After(to) {
+			break
+		}
+		if date.Before(from) || date.Before(p.Start) || p.Paused(date) {
+			continue
+		}
+		charges = append(charges, Charge{
+			Date:     date,
+			Amount:   float64(p.PriceAt(date)),
+			Currency: p.Currency,
+		})
+	}
+	return charges
+}
+
+// NextCharge возвращает первое списание не раньше after, пропуская паузы
+func (p Plan) NextCharge(after time.Time) (time.Time, bool) {
+	for n := p.firstIndex(after); ; n++ {
+		date := p.Period.Nth(p.Anchor, n)
+		if p.End != nil && date.After(*p.End) {
+			return time.Time{}, false
+		}
+		if date.Before(after) || date.Before(p.Start) {
+			continue
+		}
+		if !p.Paused(date) {
+			return date, true
+		}
+		// Незавершённая пауза - следующих списаний не будет
+		if p.pausedForever(date) {
+			return time.Time{}, false
+		}
+	}
+}
+
+func (p Plan) pausedForever(date time.Time) bool {
+	for _, pause := range p.Pauses {
+		if pause.End == nil && !date.Before(pause.Start) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Plan) MonthlyEquivalent() float64 {
+	return p.Period.MonthlyEquivalent(float64(p.Price))
+}
+
+// firstIndex возвращает номер списания, с которого имеет смысл начинать перебор,
+// чтобы не проходить все списания от anchor до from
+func (p Plan) firstIndex(from time.Time) int {
+	if !from.After(p.Anchor) {
+		return 0
+	}
+
+	var n int
+	if p.Period.Weeks > 0 {
+		n = int(from.Sub(p.Anchor).Hours()/24) / (7 * p.Period.Weeks)
+	} else {
+		months := (from.Year()-p.Anchor.Year())*12 + int(from.Month()-p.Anchor.Month())
+		n = months / p.Period.Months
+	}
+	return max(n-1, 0)
+}
+
+// ProratedCharges возвращает стоимость каждого периода оплаты, пропорциональную числу дней
+// вне пауз, в которые подписка действовала внутри [from, to]. Дата списания - начало периода оплаты.
+func (p Plan) ProratedCharges(from time.Time, to time.Time) []Charge {
+	if p.Start.After(from) {
+		from = p.Start
+	}
+	if p.End != nil && p.End.Before(to) {
+		to = *p.End
+	}
+
+	charges := []Charge{}
+	if to.Before(from) {
+		return charges
+	}
+
+	for n := p.firstIndex(from); ; n++ {
+		periodStart := p.Period.Nth(p.Anchor, n)
+		if periodStart.After(to) {
+			break
+		}
+		periodEnd := p.Period.Nth(p.Anchor, n+1).AddDate(0, 0, -1)
+
+		overlapStart, overlapEnd := periodStart, periodEnd
+		if from.After(overlapStart) {
+			overlapStart = from
+		}
+		if to.Before(overlapEnd) {
+			overlapEnd = to
+		}
+		if overlapEnd.Before(overlapStart) {
+			continue
+		}
+
+		active := days(overlapStart, overlapEnd) - p.pausedDays(overlapStart, overlapEnd)
+		if active <= 0 {
+			continue
+		}
+
+		charges = append(charges, Charge{
+			Date:     periodStart,
+			Amount:   float64(p.PriceAt(periodStart)) * float64(active) / float64(days(periodStart, periodEnd)),
+			Currency: p.Currency,
+		})
+	}
+	return charges
+}
+
+// days возвращает количество дней в [from, to] включительно
+func days(from time.Time, to time.Time) int {
+	return int(to.Sub(from).Hours()/24) + 1
+}
@@ -0,0 +1,237 @@
+package billing
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func dates(charges []Charge) []string {
+	result := []string{}
+	for _, charge := range charges {
+		result = append(result, charge.Date.Format(time.DateOnly))
+	}
+	return result
+}
+
+func equal(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestPlanCharges(t *testing.T) {
+	monthly, _ := ParsePeriod(Monthly, 0)
+	yearly, _ := ParsePeriod(Yearly, 0)
+
+	tests := []struct {
+		name     string
+		plan     Plan
+		from, to time.Time
+		want     []string
+	}{
+		{
+			name: "month end anchor",
+			plan: Plan{Start: date(2025, 1, 31), Anchor: date(2025, 1, 31), Period: monthly, Price: 100},
+			from: date(2025, 1, 1), to: date(2025, 4, 30),
+			want: []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"},
+		},
+		{
+			name: "anchor after start",
+			plan: Plan{Start: date(2025, 1, 1), Anchor: date(2025, 1, 15), Period: monthly, Price: 100},
+			from: date(2025, 1, 1), to: date(2025, 3, 14),
+			want: []string{"2025-01-15", "2025-02-15"},
+		},
+		{
+			name: "end date is inclusive",
+			plan: Plan{Start: date(2025, 1, 10), Anchor: date(2025, 1, 10), End: ptr(date(2025, 3, 10)), Period: monthly, Price: 100},
+			from: date(2025, 1, 1), to: date(2025, 12, 31),
+			want: []string{"2025-01-10", "2025-02-10", "2025-03-10"},
+		},
+		{
+			name: "paused charges are skipped",
+			plan: Plan{
+				Start: date(2025, 1, 5), Anchor: date(2025, 1, 5), Period: monthly, Price: 100,
+				Pauses: []Pause{{Start: date(2025, 2, 1), End: ptr(date(2025, 3, 6))}},
+			},
+			from: date(2025, 1, 1), to: date(2025, 4, 30),
+			want: []string{"2025-01-05", "2025-04-05"},
+		},
+		{
+			name: "leap day yearly",
+			plan: Plan{Start: date(2024, 2, 29), Anchor: date(2024, 2, 29), Period: yearly, Price: 100},
+			from: date(2024, 1, 1), to: date(2028, 12, 31),
+			want: []string{"2024-02-29", "2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"},
+		},
+		{
+			name: "window far from anchor",
+			plan: Plan{Start: date(2015, 6, 30), Anchor: date(2015, 6, 30), Period: monthly, Price: 100},
+			from: date(2025, 2, 1), to: date(2025, 3, 31),
+			want: []string{"2025-02-28", "2025-03-30"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := dates(tt.plan.Charges(tt.from, tt.to)); !equal(got, tt.want) {
+			t.Errorf("%s: Charges = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlanNextCharge(t *testing.T) {
+	monthly, _ := ParsePeriod(Monthly, 0)
+
+	tests := []struct {
+		name   string
+		plan   Plan
+		after  time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name:  "same day",
+			plan:  Plan{Start: date(2025, 1, 15), Anchor: date(2025, 1, 15), Period: monthly},
+			after: date(2025, 3, 15), want: date(2025, 3, 15), wantOk: true,
+		},
+		{
+			name:  "next month",
+			plan:  Plan{Start: date(2025, 1, 15), Anchor: date(2025, 1, 15), Period: monthly},
+			after: date(2025, 3, 16), want: date(2025, 4, 15), wantOk: true,
+		},
+		{
+			name:  "after end",
+			plan:  Plan{Start: date(2025, 1, 15), Anchor: date(2025, 1, 15), End: ptr(date(2025, 4, 14)), Period: monthly},
+			after: date(2025, 3, 16),
+		},
+		{
+			name: "after finished pause",
+			plan: Plan{
+				Start: date(2025, 1, 15), Anchor: date(2025, 1, 15), Period: monthly,
+				Pauses: []Pause{{Start: date(2025, 3, 1), End: ptr(date(2025, 5, 1))}},
+			},
+			after: date(2025, 3, 1), want: date(2025, 5, 15), wantOk: true,
+		},
+		{
+			name: "unfinished pause",
+			plan: Plan{
+				Start: date(2025, 1, 15), Anchor: date(2025, 1, 15), Period: monthly,
+				Pauses: []Pause{{Start: date(2025, 3, 1)}},
+			},
+			after: date(2025, 3, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.plan.NextCharge(tt.after)
+		if ok != tt.wantOk || ok && !got.Equal(tt.want) {
+			t.Errorf("%s: NextCharge = %s, %v, want %s, %v", tt.name, got.Format(time.DateOnly), ok, tt.want.Format(time.DateOnly), tt.wantOk)
+		}
+	}
+}
+
+func TestPlanPriceAt(t *testing.T) {
+	plan := Plan{
+		Price: 500,
+		Prices: []PricePoint{
+			{From: date(2025, 1, 1), Price: 400},
+			{From: date(2025, 6, 1), Price: 500},
+		},
+		Phases: []Phase{
+			{Kind: PhaseTrial, Price: 0, Start: date(2025, 1, 1), End: date(2025, 1, 31)},
+			{Kind: PhasePromo, Price: 200, Start: date(2025, 2, 1), End: date(2025, 3, 31)},
+		},
+	}
+
+	tests := []struct {
+		date time.Time
+		want int
+	}{
+		{date(2024, 12, 1), 400},
+		{date(2025, 1, 31), 0},
+		{date(2025, 2, 1), 200},
+		{date(2025, 3, 31), 200},
+		{date(2025, 4, 1), 400},
+		{date(2025, 6, 1), 500},
+	}
+
+	for _, tt := range tests {
+		if got := plan.PriceAt(tt.date); got != tt.want {
+			t.Errorf("PriceAt(%s) = %d, want %d", tt.date.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
+
+func TestPlanProratedCharges(t *testing.T) {
+	monthly, _ := ParsePeriod(Monthly, 0)
+	quarterly, _ := ParsePeriod(Quarterly, 0)
+
+	tests := []struct {
+		name     string
+		plan     Plan
+		from, to time.Time
+		want     []float64
+	}{
+		{
+			name: "whole periods",
+			plan: Plan{Start: date(2025, 1, 1), Anchor: date(2025, 1, 1), Period: monthly, Price: 310},
+			from: date(2025, 1, 1), to: date(2025, 2, 28),
+			want: []float64{310, 310},
+		},
+		{
+			name: "window cuts the period",
+			plan: Plan{Start: date(2025, 1, 1), Anchor: date(2025, 1, 1), Period: monthly, Price: 310},
+			from: date(2025, 1, 11), to: date(2025, 1, 20),
+			want: []float64{100},
+		},
+		{
+			name: "start in the middle of the period",
+			plan: Plan{Start: date(2025, 1, 22), Anchor: date(2025, 1, 1), Period: monthly, Price: 310},
+			from: date(2025, 1, 1), to: date(2025, 1, 31),
+			want: []float64{100},
+		},
+		{
+			name: "end in the middle of the period",
+			plan: Plan{Start: date(2025, 1, 1), Anchor: date(2025, 1, 1), End: ptr(date(2025, 2, 14)), Period: monthly, Price: 280},
+			from: date(2025, 1, 1), to: date(2025, 12, 31),
+			want: []float64{280, 140},
+		},
+		{
+			name: "pause days are not paid",
+			plan: Plan{
+				Start: date(2025, 1, 1), Anchor: date(2025, 1, 1), Period: monthly, Price: 310,
+				Pauses: []Pause{{Start: date(2025, 1, 11), End: ptr(date(2025, 1, 21))}},
+			},
+			from: date(2025, 1, 1), to: date(2025, 1, 31),
+			want: []float64{210},
+		},
+		{
+			name: "quarter cut by window",
+			plan: Plan{Start: date(2025, 1, 1), Anchor: date(2025, 1, 1), Period: quarterly, Price: 900},
+			from: date(2025, 2, 1), to: date(2025, 2, 28),
+			want: []float64{280},
+		},
+	}
+
+	for _, tt := range tests {
+		charges := tt.plan.ProratedCharges(tt.from, tt.to)
+		if len(charges) != len(tt.want) {
+			t.Errorf("%s: ProratedCharges = %v, want %v", tt.name, charges, tt.want)
+			continue
+		}
+		for i, charge := range charges {
+			if math.Abs(charge.Amount-tt.want[i]) > 1e-9 {
+				t.Errorf("%s: charge %d = %v, want %v", tt.name, i, charge.Amount, tt.want[i])
+			}
+		}
+	}
+}
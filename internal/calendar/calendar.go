@@ -0,0 +1,192 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+)
+
+const ContentType = "text/calendar; charset=utf-8"
+
+// Длина строки iCalendar в октетах без CRLF, длинные строки переносятся (RFC 5545, 3.1)
+const lineLength = 75
+
+const dateLayout = "20060102"
+
+// Event - повторяющееся событие на весь день
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	// Дата первого события
+	Start time.Time
+	// RRULE без префикса, см. Rule
+	Rule string
+	// Даты, исключённые из повторений
+	Except []time.Time
+	// Напоминание относительно начала дня события, nil - без напоминания
+	Alarm *time.Duration
+}
+
+// Writer пишет календарь в формате iCalendar (RFC 5545)
+type Writer struct {
+	w     *bufio.Writer
+	stamp string
+}
+
+// New начинает календарь с названием name
+func New(w io.Writer, name string) *Writer {
+	writer := &Writer{
+		w:     bufio.NewWriter(w),
+		stamp: time.Now().UTC().Format("20060102T150405Z"),
+	}
+
+	writer.line("BEGIN:VCALENDAR")
+	writer.line("VERSION:2.0")
+	writer.line("PRODID:-//effective-mobile//subscriptions//EN")
+	writer.line("CALSCALE:GREGORIAN")
+	writer.line("METHOD:PUBLISH")
+	writer.line("X-WR-CALNAME:" + escape(name))
+	// Как часто календарным приложениям обновлять подписку
+	writer.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writer.line("X-PUBLISHED-TTL:PT1H")
+	return writer
+}
+
+func (w *Writer) Event(event Event) error {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + escape(event.UID))
+	w.line("DTSTAMP:" + w.stamp)
+	w.line("DTSTART;VALUE=DATE:" + event.Start.Format(dateLayout))
+	w.line("SUMMARY:" + escape(event.Summary))
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escape(event.Description))
+	}
+	if len(event.Categories) > 0 {
+		categories := make([]string, len(event.Categories))
+		for i, category := range event.Categories {
+			categories[i] = escape(category)
+		}
+		w.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if event.Rule != "" {
+		w.line("RRULE:" + event.Rule)
+	}
+	if len(event.Except) > 0 {
+		dates := make([]string, len(event.Except))
+		for i, date := range event.Except {
+			dates[i] = date.Format(dateLayout)
+		}
+		w.line("EXDATE;VALUE=DATE:" + strings.Join(dates, ","))
+	}
+	w.line("TRANSP:TRANSPARENT")
+	if event.Alarm != nil {
+		w.line("BEGIN:VALARM")
+		w.line("ACTION:DISPLAY")
+		w.line("DESCRIPTION:" + escape(event.Summary))
+		w.line("TRIGGER:" + duration(*event.Alarm))
+		w.line("END:VALARM")
+	}
+	w.line("END:VEVENT")
+	return w.w.Flush()
+}
+
+func (w *Writer) Close() error {
+	w.line("END:VCALENDAR")
+	return w.w.Flush()
+}
+
+// Rule возвращает RRULE списаний с периодичностью period, начиная с anchor, до until включительно.
+// Списание 29-31 числа переносится на последний день короткого месяца, как в billing.AddMonths.
+func Rule(period billing.Period, anchor time.Time, until *time.Time) string {
+	var parts []string
+	switch {
+	case period.Weeks > 0:
+		parts = append(parts, "FREQ=WEEKLY")
+		if period.Weeks > 1 {
+			parts = append(parts, "INTERVAL="+strconv.Itoa(period.Weeks))
+		}
+	case period.Months%12 == 0:
+		parts = append(parts, "FREQ=YEARLY")
+		if period.Months > 12 {
+			parts = append(parts, "INTERVAL="+strconv.Itoa(period.Months/12))
+		}
+		if anchor.Day() > 28 {
+			parts = append(parts, "BYMONTH="+strconv.Itoa(int(anchor.Month())))
+		}
+	default:
+		parts = append(parts, "FREQ=MONTHLY")
+		if period.Months > 1 {
+			parts = append(parts, "INTERVAL="+strconv.Itoa(period.Months))
+		}
+	}
+
+	if period.Weeks == 0 && anchor.Day() > 28 {
+		days := []string{}
+		for day := 28; day <= anchor.Day(); day++ {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","), "BYSETPOS=-1")
+	}
+
+	if until != nil {
+		parts = append(parts, "UNTIL="+until.Format(dateLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// line пишет строку содержимого, перенося её по lineLength октетов без разрыва символов UTF-8
+func (w *Writer) line(value string) {
+	limit := lineLength
+	for len(value) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		w.w.WriteString(value[:cut])
+		w.w.WriteString("\r\n ")
+		value = value[cut:]
+		// Пробел в начале строки переноса тоже считается
+		limit = lineLength - 1
+	}
+	w.w.WriteString(value)
+	w.w.WriteString("\r\n")
+}
+
+// escape экранирует значение типа TEXT (RFC 5545, 3.3.11)
+func escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// duration форматирует смещение как DURATION (RFC 5545, 3.3.6) с точностью до минуты
+func duration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours == 0 && minutes == 0:
+		return "PT0S"
+	case minutes == 0:
+		return fmt.Sprintf("%sPT%dH", sign, hours)
+	case hours == 0:
+		return fmt.Sprintf("%sPT%dM", sign, minutes)
+	}
+	return fmt.Sprintf("%sPT%dH%dM", sign, hours, minutes)
+}
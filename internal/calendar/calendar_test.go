@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRule(t *testing.T) {
+	until := date(2026, 8, 31)
+
+	tests := []struct {
+		name   string
+		kind   string
+		months int
+		anchor time.Time
+		until  *time.Time
+		want   string
+	}{
+		{"monthly", billing.Monthly, 0, date(2025, 7, 15), nil, "FREQ=MONTHLY"},
+		{"monthly until", billing.Monthly, 0, date(2025, 7, 15), &until, "FREQ=MONTHLY;UNTIL=20260831"},
+		{"weekly", billing.Weekly, 0, date(2025, 7, 31), nil, "FREQ=WEEKLY"},
+		{"quarterly", billing.Quarterly, 0, date(2025, 7, 15), nil, "FREQ=MONTHLY;INTERVAL=3"},
+		{"custom", billing.Custom, 24, date(2025, 7, 15), nil, "FREQ=YEARLY;INTERVAL=2"},
+		{"yearly", billing.Yearly, 0, date(2025, 7, 15), nil, "FREQ=YEARLY"},
+		// Списание 29-31 числа переносится на последний день короткого месяца
+		{"month end", billing.Monthly, 0, date(2025, 1, 31), nil, "FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1"},
+		{"30th bimonthly", billing.Custom, 2, date(2025, 8, 30), &until, "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=28,29,30;BYSETPOS=-1;UNTIL=20260831"},
+		{"leap day", billing.Yearly, 0, date(2024, 2, 29), nil, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=28,29;BYSETPOS=-1"},
+	}
+
+	for _, tt := range tests {
+		period, err := billing.ParsePeriod(tt.kind, tt.months)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := Rule(period, tt.anchor, tt.until); got != tt.want {
+			t.Errorf("%s: Rule = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Netflix: 399 RUB", "Netflix: 399 RUB"},
+		{`a;b,c\d`, `a\;b\,c\\d`},
+		{"line1\nline2\r\nline3", `line1\nline2\nline3`},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.value); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{9 * time.Hour, "PT9H"},
+		{-63 * time.Hour, "-PT63H"},
+		{-30 * time.Minute, "-PT30M"},
+		{90 * time.Minute, "PT1H30M"},
+	}
+
+	for _, tt := range tests {
+		if got := duration(tt.d); got != tt.want {
+			t.Errorf("duration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"short", "SUMMARY:Netflix"},
+		{"exact", "SUMMARY:" + strings.Repeat("a", lineLength-len("SUMMARY:"))},
+		{"ascii", "SUMMARY:" + strings.Repeat("a", 200)},
+		{"utf-8", "SUMMARY:" + strings.Repeat("Подписка ", 30)},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := &Writer{w: bufio.NewWriter(&buf)}
+		w.line(tt.value)
+		w.w.Flush()
+
+		out := buf.String()
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%s: line does not end with CRLF", tt.name)
+		}
+		lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		for i, line := range lines {
+			if len(line) > lineLength {
+				t.Errorf("%s: line %d is %d octets", tt.name, i, len(line))
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("%s: line %d splits a character", tt.name, i)
+			}
+			if i > 0 && !strings.HasPrefix(line, " ") {
+				t.Errorf("%s: continuation line %d does not start with a space", tt.name, i)
+			}
+		}
+		if unfolded := strings.ReplaceAll(out, "\r\n ", ""); unfolded != tt.value+"\r\n" {
+			t.Errorf("%s: unfolded %q, want %q", tt.name, unfolded, tt.value)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	alarm := -63 * time.Hour
+	w := New(&buf, "Subscriptions")
+	err := w.Event(Event{
+		UID:         "subscription-1@default",
+		Summary:     "Netflix: 399 RUB",
+		Description: "Price from 2026-01-01: 499 RUB",
+		Categories:  []string{"video"},
+		Start:       date(2025, 10, 31),
+		Rule:        "FREQ=MONTHLY",
+		Except:      []time.Time{date(2025, 11, 30), date(2025, 12, 31)},
+		Alarm:       &alarm,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VEVENT\r\nUID:subscription-1@default\r\nDTSTAMP:",
+		"DTSTART;VALUE=DATE:20251031\r\n",
+		"SUMMARY:Netflix: 399 RUB\r\n",
+		"CATEGORIES:video\r\n",
+		"RRULE:FREQ=MONTHLY\r\n",
+		"EXDATE;VALUE=DATE:20251130,20251231\r\n",
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Netflix: 399 RUB\r\nTRIGGER:-PT63H\r\nEND:VALARM\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/sqlc-dev/pqtype"
+)
+
+const auditList = `-- name: AuditList :many
+SELECT id, tenant_id, subscription_id, actor, request_id, action, before, after, created_at FROM subscription_audit
+WHERE tenant_id = $1
+  AND ($2::text IS NULL OR actor = $2)
+  AND ($3::text IS NULL OR action = $3)
+  AND ($4::timestamptz IS NULL OR created_at >= $4)
+  AND ($5::timestamptz IS NULL OR created_at < $5)
+ORDER BY id DESC
+LIMIT $7 OFFSET $6
+`
+
+type AuditListParams struct {
+	TenantID  string         `json:"tenant_id"`
+	Actor     sql.NullString `json:"actor"`
+	Action    sql.NullString `json:"action"`
+	FromTime  sql.NullTime   `json:"from_time"`
+	ToTime    sql.NullTime   `json:"to_time"`
+	RowOffset int32          `json:"row_offset"`
+	RowLimit  int32          `json:"row_limit"`
+}
+
+func (q *Queries) AuditList(ctx context.Context, arg AuditListParams) ([]SubscriptionAudit, error) {
+	rows, err := q.db.QueryContext(ctx, auditList,
+		arg.TenantID,
+		arg.Actor,
+		arg.Action,
+		arg.FromTime,
+		arg.ToTime,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionAudit
+	for rows.Next() {
+		var i SubscriptionAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.SubscriptionID,
+			&i.Actor,
+			&i.RequestID,
+			&i.Action,
+			&i.Before,
+			&i.After,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createAuditRecord = `-- name: CreateAuditRecord :exec
+INSERT INTO subscription_audit (tenant_id, subscription_id, actor, request_id, action, before, after) VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateAuditRecordParams struct {
+	TenantID       string                `json:"tenant_id"`
+	SubscriptionID int32                 `json:"subscription_id"`
+	Actor          string                `json:"actor"`
+	RequestID      string                `json:"request_id"`
+	Action         string                `json:"action"`
+	Before         pqtype.NullRawMessage `json:"before"`
+	After          pqtype.NullRawMessage `json:"after"`
+}
+
+func (q *Queries) CreateAuditRecord(ctx context.Context, arg CreateAuditRecordParams) error {
+	_, err := q.db.ExecContext(ctx, createAuditRecord,
+		arg.TenantID,
+		arg.SubscriptionID,
+		arg.Actor,
+		arg.RequestID,
+		arg.Action,
+		arg.Before,
+		arg.After,
+	)
+	return err
+}
+
+const createAuditRecords = `-- name: CreateAuditRecords :exec
+INSERT INTO subscription_audit (tenant_id, subscription_id, actor, request_id, action, after)
+SELECT $1::varchar, ($2::int[])[i], $3::varchar, $4::varchar, $5::varchar, ($6::text[])[i]::jsonb
+FROM generate_series(1, cardinality($2::int[])) AS i
+`
+
+type CreateAuditRecordsParams struct {
+	TenantID        string   `json:"tenant_id"`
+	SubscriptionIds []int32  `json:"subscription_ids"`
+	Actor           string   `json:"actor"`
+	RequestID       string   `json:"request_id"`
+	Action          string   `json:"action"`
+	Afters          []string `json:"afters"`
+}
+
+// Записи аудита одного действия по нескольким подпискам, afters - JSON подписок после действия
+func (q *Queries) CreateAuditRecords(ctx context.Context, arg CreateAuditRecordsParams) error {
+	_, err := q.db.ExecContext(ctx, createAuditRecords,
+		arg.TenantID,
+		pq.Array(arg.SubscriptionIds),
+		arg.Actor,
+		arg.RequestID,
+		arg.Action,
+		pq.Array(arg.Afters),
+	)
+	return err
+}
+
+const subscriptionHistory = `-- name: SubscriptionHistory :many
+SELECT id, tenant_id, subscription_id, actor, request_id, action, before, after, created_at FROM subscription_audit WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY id
+`
+
+type SubscriptionHistoryParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) SubscriptionHistory(ctx context.Context, arg SubscriptionHistoryParams) ([]SubscriptionAudit, error) {
+	rows, err := q.db.QueryContext(ctx, subscriptionHistory, arg.SubscriptionID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionAudit
+	for rows.Next() {
+		var i SubscriptionAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.SubscriptionID,
+			&i.Actor,
+			&i.RequestID,
+			&i.Action,
+			&i.Before,
+			&i.After,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
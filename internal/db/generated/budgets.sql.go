@@ -0,0 +1,194 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: budgets.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createBudget = `-- name: CreateBudget :one
+INSERT INTO budgets (tenant_id, user_id, category, service_id, monthly_limit, currency, strict) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, tenant_id, user_id, category, service_id, monthly_limit, currency, strict, created_at
+`
+
+type CreateBudgetParams struct {
+	TenantID     string         `json:"tenant_id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	Category     sql.NullString `json:"category"`
+	ServiceID    sql.NullInt32  `json:"service_id"`
+	MonthlyLimit int32          `json:"monthly_limit"`
+	Currency     string         `json:"currency"`
+	Strict       bool           `json:"strict"`
+}
+
+func (q *Queries) CreateBudget(ctx context.Context, arg CreateBudgetParams) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, createBudget,
+		arg.TenantID,
+		arg.UserID,
+		arg.Category,
+		arg.ServiceID,
+		arg.MonthlyLimit,
+		arg.Currency,
+		arg.Strict,
+	)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.UserID,
+		&i.Category,
+		&i.ServiceID,
+		&i.MonthlyLimit,
+		&i.Currency,
+		&i.Strict,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createBudgetAlert = `-- name: CreateBudgetAlert :one
+INSERT INTO budget_alerts (tenant_id, budget_id, user_id, subscription_id, spend, monthly_limit, currency) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, tenant_id, budget_id, user_id, subscription_id, spend, monthly_limit, currency, created_at
+`
+
+type CreateBudgetAlertParams struct {
+	TenantID       string        `json:"tenant_id"`
+	BudgetID       int32         `json:"budget_id"`
+	UserID         uuid.UUID     `json:"user_id"`
+	SubscriptionID sql.NullInt32 `json:"subscription_id"`
+	Spend          float64       `json:"spend"`
+	MonthlyLimit   int32         `json:"monthly_limit"`
+	Currency       string        `json:"currency"`
+}
+
+func (q *Queries) CreateBudgetAlert(ctx context.Context, arg CreateBudgetAlertParams) (BudgetAlert, error) {
+	row := q.db.QueryRowContext(ctx, createBudgetAlert,
+		arg.TenantID,
+		arg.BudgetID,
+		arg.UserID,
+		arg.SubscriptionID,
+		arg.Spend,
+		arg.MonthlyLimit,
+		arg.Currency,
+	)
+	var i BudgetAlert
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.BudgetID,
+		&i.UserID,
+		&i.SubscriptionID,
+		&i.Spend,
+		&i.MonthlyLimit,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteBudget = `-- name: DeleteBudget :execrows
+DELETE FROM budgets WHERE id = $1 AND tenant_id = $2 AND user_id = $3
+`
+
+type DeleteBudgetParams struct {
+	ID       int32     `json:"id"`
+	TenantID string    `json:"tenant_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteBudget(ctx context.Context, arg DeleteBudgetParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBudget, arg.ID, arg.TenantID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateBudget = `-- name: UpdateBudget :one
+UPDATE budgets SET category = $4, service_id = $5, monthly_limit = $6, currency = $7, strict = $8
+WHERE id = $1 AND tenant_id = $2 AND user_id = $3
+RETURNING id, tenant_id, user_id, category, service_id, monthly_limit, currency, strict, created_at
+`
+
+type UpdateBudgetParams struct {
+	ID           int32          `json:"id"`
+	TenantID     string         `json:"tenant_id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	Category     sql.NullString `json:"category"`
+	ServiceID    sql.NullInt32  `json:"service_id"`
+	MonthlyLimit int32          `json:"monthly_limit"`
+	Currency     string         `json:"currency"`
+	Strict       bool           `json:"strict"`
+}
+
+func (q *Queries) UpdateBudget(ctx context.Context, arg UpdateBudgetParams) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, updateBudget,
+		arg.ID,
+		arg.TenantID,
+		arg.UserID,
+		arg.Category,
+		arg.ServiceID,
+		arg.MonthlyLimit,
+		arg.Currency,
+		arg.Strict,
+	)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.UserID,
+		&i.Category,
+		&i.ServiceID,
+		&i.MonthlyLimit,
+		&i.Currency,
+		&i.Strict,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const userBudgets = `-- name: UserBudgets :many
+SELECT id, tenant_id, user_id, category, service_id, monthly_limit, currency, strict, created_at FROM budgets WHERE tenant_id = $1 AND user_id = $2 ORDER BY id
+`
+
+type UserBudgetsParams struct {
+	TenantID string    `json:"tenant_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) UserBudgets(ctx context.Context, arg UserBudgetsParams) ([]Budget, error) {
+	rows, err := q.db.QueryContext(ctx, userBudgets, arg.TenantID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Budget
+	for rows.Next() {
+		var i Budget
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.UserID,
+			&i.Category,
+			&i.ServiceID,
+			&i.MonthlyLimit,
+			&i.Currency,
+			&i.Strict,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
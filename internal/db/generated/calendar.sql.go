@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: calendar.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const calendarTokenByHash = `-- name: CalendarTokenByHash :one
+SELECT tenant_id, user_id, token_hash, created_at FROM calendar_tokens WHERE token_hash = $1
+`
+
+func (q *Queries) CalendarTokenByHash(ctx context.Context, tokenHash string) (CalendarToken, error) {
+	row := q.db.QueryRowContext(ctx, calendarTokenByHash, tokenHash)
+	var i CalendarToken
+	err := row.Scan(
+		&i.TenantID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteCalendarToken = `-- name: DeleteCalendarToken :execrows
+DELETE FROM calendar_tokens WHERE tenant_id = $1 AND user_id = $2
+`
+
+type DeleteCalendarTokenParams struct {
+	TenantID string    `json:"tenant_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteCalendarToken(ctx context.Context, arg DeleteCalendarTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCalendarToken, arg.TenantID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const saveCalendarToken = `-- name: SaveCalendarToken :one
+INSERT INTO calendar_tokens (tenant_id, user_id, token_hash) VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()
+RETURNING tenant_id, user_id, token_hash, created_at
+`
+
+type SaveCalendarTokenParams struct {
+	TenantID  string    `json:"tenant_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+}
+
+func (q *Queries) SaveCalendarToken(ctx context.Context, arg SaveCalendarTokenParams) (CalendarToken, error) {
+	row := q.db.QueryRowContext(ctx, saveCalendarToken, arg.TenantID, arg.UserID, arg.TokenHash)
+	var i CalendarToken
+	err := row.Scan(
+		&i.TenantID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
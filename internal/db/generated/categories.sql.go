@@ -0,0 +1,48 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: categories.sql
+
+package db
+
+import (
+	"context"
+)
+
+const categoriesList = `-- name: CategoriesList :many
+SELECT slug, name, parent_slug FROM categories ORDER BY slug
+`
+
+func (q *Queries) CategoriesList(ctx context.Context) ([]Category, error) {
+	rows, err := q.db.QueryContext(ctx, categoriesList)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(&i.Slug, &i.Name, &i.ParentSlug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategory = `-- name: GetCategory :one
+SELECT slug, name, parent_slug FROM categories WHERE slug = $1
+`
+
+func (q *Queries) GetCategory(ctx context.Context, slug string) (Category, error) {
+	row := q.db.QueryRowContext(ctx, getCategory, slug)
+	var i Category
+	err := row.Scan(&i.Slug, &i.Name, &i.ParentSlug)
+	return i, err
+}
@@ -0,0 +1,99 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: exchange_rates.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const exchangeRatesForCurrencies = `-- name: ExchangeRatesForCurrencies :many
+SELECT currency, rate_date, rate, source FROM exchange_rates WHERE currency = ANY($1::text[]) ORDER BY currency, rate_date
+`
+
+func (q *Queries) ExchangeRatesForCurrencies(ctx context.Context, currencies []string) ([]ExchangeRate, error) {
+	rows, err := q.db.QueryContext(ctx, exchangeRatesForCurrencies, pq.Array(currencies))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRate
+	for rows.Next() {
+		var i ExchangeRate
+		if err := rows.Scan(
+			&i.Currency,
+			&i.RateDate,
+			&i.Rate,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exchangeRatesList = `-- name: ExchangeRatesList :many
+SELECT currency, rate_date, rate, source FROM exchange_rates WHERE ($1 = '' OR currency = $1::text) ORDER BY currency, rate_date
+`
+
+func (q *Queries) ExchangeRatesList(ctx context.Context, dollar_1 interface{}) ([]ExchangeRate, error) {
+	rows, err := q.db.QueryContext(ctx, exchangeRatesList, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRate
+	for rows.Next() {
+		var i ExchangeRate
+		if err := rows.Scan(
+			&i.Currency,
+			&i.RateDate,
+			&i.Rate,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (currency, rate_date, rate, source) VALUES ($1, $2, $3, $4)
+ON CONFLICT (currency, rate_date) DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+`
+
+type UpsertExchangeRateParams struct {
+	Currency string    `json:"currency"`
+	RateDate time.Time `json:"rate_date"`
+	Rate     string    `json:"rate"`
+	Source   string    `json:"source"`
+}
+
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) error {
+	_, err := q.db.ExecContext(ctx, upsertExchangeRate,
+		arg.Currency,
+		arg.RateDate,
+		arg.Rate,
+		arg.Source,
+	)
+	return err
+}
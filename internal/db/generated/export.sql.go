@@ -0,0 +1,98 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: export.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const declareSubscriptionsExport = `-- name: DeclareSubscriptionsExport :exec
+
+DECLARE subscriptions_export NO SCROLL CURSOR FOR
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions
+WHERE subscriptions.tenant_id = $1
+  AND (deleted_at IS NULL OR $2::bool)
+  AND ($3::uuid IS NULL OR user_id = $3)
+  AND ($4::uuid IS NULL OR user_id = $4 OR id IN (
+    SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = $4
+  ))
+  AND (cardinality($5::text[]) = 0 OR category = ANY($5::text[]))
+  AND (cardinality($6::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY($6::text[])
+  ) = cardinality($6::text[]))
+ORDER BY id
+`
+
+type DeclareSubscriptionsExportParams struct {
+	TenantID       string        `json:"tenant_id"`
+	IncludeDeleted bool          `json:"include_deleted"`
+	UserID         uuid.NullUUID `json:"user_id"`
+	ParticipantID  uuid.NullUUID `json:"participant_id"`
+	Categories     []string      `json:"categories"`
+	Tags           []string      `json:"tags"`
+}
+
+// Курсор живёт до конца транзакции, партии читаются FetchSubscriptionsExport
+func (q *Queries) DeclareSubscriptionsExport(ctx context.Context, arg DeclareSubscriptionsExportParams) error {
+	_, err := q.db.ExecContext(ctx, declareSubscriptionsExport,
+		arg.TenantID,
+		arg.IncludeDeleted,
+		arg.UserID,
+		arg.ParticipantID,
+		pq.Array(arg.Categories),
+		pq.Array(arg.Tags),
+	)
+	return err
+}
+
+const fetchSubscriptionsExport = `-- name: FetchSubscriptionsExport :many
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM fetch_subscriptions('subscriptions_export', $1::int)
+`
+
+func (q *Queries) FetchSubscriptionsExport(ctx context.Context, batchSize int32) ([]Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, fetchSubscriptionsExport, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServiceName,
+			&i.Price,
+			&i.UserID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
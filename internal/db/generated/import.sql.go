@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: import.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const importSubscriptions = `-- name: ImportSubscriptions :many
+INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date, tenant_id, currency, billing_period, billing_months, anchor_date, service_id, category, split_rule)
+SELECT
+  ($1::varchar[])[i],
+  ($2::int[])[i],
+  ($3::text[])[i]::uuid,
+  ($4::text[])[i]::date,
+  NULLIF(($5::text[])[i], '')::date,
+  $6::varchar,
+  ($7::text[])[i],
+  ($8::text[])[i],
+  NULLIF(($9::int[])[i], 0),
+  NULLIF(($10::text[])[i], '')::date,
+  NULLIF(($11::int[])[i], 0),
+  NULLIF(($12::text[])[i], ''),
+  ($13::text[])[i]
+FROM generate_series(1, cardinality($2::int[])) AS i
+ORDER BY i
+RETURNING id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule
+`
+
+type ImportSubscriptionsParams struct {
+	ServiceNames   []string `json:"service_names"`
+	Prices         []int32  `json:"prices"`
+	UserIds        []string `json:"user_ids"`
+	StartDates     []string `json:"start_dates"`
+	EndDates       []string `json:"end_dates"`
+	TenantID       string   `json:"tenant_id"`
+	Currencies     []string `json:"currencies"`
+	BillingPeriods []string `json:"billing_periods"`
+	BillingMonths  []int32  `json:"billing_months"`
+	AnchorDates    []string `json:"anchor_dates"`
+	ServiceIds     []int32  `json:"service_ids"`
+	Categories     []string `json:"categories"`
+	SplitRules     []string `json:"split_rules"`
+}
+
+// Добавляет подписки импорта одной командой: i-й элемент каждого массива - поле i-й подписки.
+// Пустые строки и нули - NULL, id выдаются в порядке подписок
+func (q *Queries) ImportSubscriptions(ctx context.Context, arg ImportSubscriptionsParams) ([]Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, importSubscriptions,
+		pq.Array(arg.ServiceNames),
+		pq.Array(arg.Prices),
+		pq.Array(arg.UserIds),
+		pq.Array(arg.StartDates),
+		pq.Array(arg.EndDates),
+		arg.TenantID,
+		pq.Array(arg.Currencies),
+		pq.Array(arg.BillingPeriods),
+		pq.Array(arg.BillingMonths),
+		pq.Array(arg.AnchorDates),
+		pq.Array(arg.ServiceIds),
+		pq.Array(arg.Categories),
+		pq.Array(arg.SplitRules),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServiceName,
+			&i.Price,
+			&i.UserID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseImportRow = `-- name: ReleaseImportRow :exec
+RELEASE SAVEPOINT import_row
+`
+
+func (q *Queries) ReleaseImportRow(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, releaseImportRow)
+	return err
+}
+
+const rollbackImportRow = `-- name: RollbackImportRow :exec
+ROLLBACK TO SAVEPOINT import_row
+`
+
+func (q *Queries) RollbackImportRow(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, rollbackImportRow)
+	return err
+}
+
+const saveImportedPrices = `-- name: SaveImportedPrices :exec
+INSERT INTO subscription_prices (subscription_id, tenant_id, effective_from, price)
+SELECT id, tenant_id, start_date, price FROM subscriptions WHERE id = ANY($1::int[])
+ON CONFLICT (subscription_id, effective_from) DO NOTHING
+`
+
+func (q *Queries) SaveImportedPrices(ctx context.Context, subscriptionIds []int32) error {
+	_, err := q.db.ExecContext(ctx, saveImportedPrices, pq.Array(subscriptionIds))
+	return err
+}
+
+const savepointImportRow = `-- name: SavepointImportRow :exec
+
+SAVEPOINT import_row
+`
+
+// Точки сохранения позволяют отменить одну строку импорта, не прерывая транзакцию
+func (q *Queries) SavepointImportRow(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, savepointImportRow)
+	return err
+}
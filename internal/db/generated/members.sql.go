@@ -0,0 +1,81 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: members.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addSubscriptionMember = `-- name: AddSubscriptionMember :exec
+INSERT INTO subscription_members (subscription_id, tenant_id, user_id, share) VALUES ($1, $2, $3, $4)
+`
+
+type AddSubscriptionMemberParams struct {
+	SubscriptionID int32           `json:"subscription_id"`
+	TenantID       string          `json:"tenant_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Share          sql.NullFloat64 `json:"share"`
+}
+
+func (q *Queries) AddSubscriptionMember(ctx context.Context, arg AddSubscriptionMemberParams) error {
+	_, err := q.db.ExecContext(ctx, addSubscriptionMember,
+		arg.SubscriptionID,
+		arg.TenantID,
+		arg.UserID,
+		arg.Share,
+	)
+	return err
+}
+
+const deleteSubscriptionMembers = `-- name: DeleteSubscriptionMembers :exec
+DELETE FROM subscription_members WHERE subscription_id = $1 AND tenant_id = $2
+`
+
+type DeleteSubscriptionMembersParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteSubscriptionMembers(ctx context.Context, arg DeleteSubscriptionMembersParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSubscriptionMembers, arg.SubscriptionID, arg.TenantID)
+	return err
+}
+
+const membersForSubscriptions = `-- name: MembersForSubscriptions :many
+SELECT subscription_id, tenant_id, user_id, share FROM subscription_members WHERE subscription_id = ANY($1::int[]) ORDER BY subscription_id, user_id
+`
+
+func (q *Queries) MembersForSubscriptions(ctx context.Context, subscriptionIds []int32) ([]SubscriptionMember, error) {
+	rows, err := q.db.QueryContext(ctx, membersForSubscriptions, pq.Array(subscriptionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionMember
+	for rows.Next() {
+		var i SubscriptionMember
+		if err := rows.Scan(
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.UserID,
+			&i.Share,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -6,16 +6,224 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
+type Budget struct {
+	ID           int32          `json:"id"`
+	TenantID     string         `json:"tenant_id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	Category     sql.NullString `json:"category"`
+	ServiceID    sql.NullInt32  `json:"service_id"`
+	MonthlyLimit int32          `json:"monthly_limit"`
+	Currency     string         `json:"currency"`
+	Strict       bool           `json:"strict"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type BudgetAlert struct {
+	ID             int32         `json:"id"`
+	TenantID       string        `json:"tenant_id"`
+	BudgetID       int32         `json:"budget_id"`
+	UserID         uuid.UUID     `json:"user_id"`
+	SubscriptionID sql.NullInt32 `json:"subscription_id"`
+	Spend          float64       `json:"spend"`
+	MonthlyLimit   int32         `json:"monthly_limit"`
+	Currency       string        `json:"currency"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type CalendarToken struct {
+	TenantID  string    `json:"tenant_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Category struct {
+	Slug       string         `json:"slug"`
+	Name       string         `json:"name"`
+	ParentSlug sql.NullString `json:"parent_slug"`
+}
+
+type ExchangeRate struct {
+	Currency string    `json:"currency"`
+	RateDate time.Time `json:"rate_date"`
+	Rate     string    `json:"rate"`
+	Source   string    `json:"source"`
+}
+
+type NotificationSetting struct {
+	TenantID          string         `json:"tenant_id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	ReminderDays      int32          `json:"reminder_days"`
+	Email             sql.NullString `json:"email"`
+	Locale            string         `json:"locale"`
+	EmailReminders    bool           `json:"email_reminders"`
+	EmailBudgetAlerts bool           `json:"email_budget_alerts"`
+}
+
+type ReminderJob struct {
+	ID             int32          `json:"id"`
+	TenantID       string         `json:"tenant_id"`
+	SubscriptionID int32          `json:"subscription_id"`
+	UserID         uuid.UUID      `json:"user_id"`
+	Kind           string         `json:"kind"`
+	EventDate      time.Time      `json:"event_date"`
+	ServiceName    string         `json:"service_name"`
+	Amount         int32          `json:"amount"`
+	Currency       string         `json:"currency"`
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	LastError      sql.NullString `json:"last_error"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	SentAt         sql.NullTime   `json:"sent_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	MonthlyLimit   sql.NullInt32  `json:"monthly_limit"`
+}
+
+type Service struct {
+	ID           int32          `json:"id"`
+	TenantID     string         `json:"tenant_id"`
+	Name         string         `json:"name"`
+	Aliases      []string       `json:"aliases"`
+	Category     sql.NullString `json:"category"`
+	DefaultPrice sql.NullInt32  `json:"default_price"`
+	Currency     string         `json:"currency"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
 type Subscription struct {
-	ID          int32        `json:"id"`
-	ServiceName string       `json:"service_name"`
-	Price       int32        `json:"price"`
-	UserID      uuid.UUID    `json:"user_id"`
-	StartDate   time.Time    `json:"start_date"`
-	EndDate     sql.NullTime `json:"end_date"`
+	ID                  int32          `json:"id"`
+	ServiceName         string         `json:"service_name"`
+	Price               int32          `json:"price"`
+	UserID              uuid.UUID      `json:"user_id"`
+	StartDate           time.Time      `json:"start_date"`
+	EndDate             sql.NullTime   `json:"end_date"`
+	TenantID            string         `json:"tenant_id"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	Currency            string         `json:"currency"`
+	BillingPeriod       string         `json:"billing_period"`
+	BillingMonths       sql.NullInt32  `json:"billing_months"`
+	AnchorDate          sql.NullTime   `json:"anchor_date"`
+	ServiceID           sql.NullInt32  `json:"service_id"`
+	Category            sql.NullString `json:"category"`
+	CancelledAt         sql.NullTime   `json:"cancelled_at"`
+	EndDateBeforeCancel sql.NullTime   `json:"end_date_before_cancel"`
+	SplitRule           string         `json:"split_rule"`
+}
+
+type SubscriptionAudit struct {
+	ID             int64                 `json:"id"`
+	TenantID       string                `json:"tenant_id"`
+	SubscriptionID int32                 `json:"subscription_id"`
+	Actor          string                `json:"actor"`
+	RequestID      string                `json:"request_id"`
+	Action         string                `json:"action"`
+	Before         pqtype.NullRawMessage `json:"before"`
+	After          pqtype.NullRawMessage `json:"after"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type SubscriptionMember struct {
+	SubscriptionID int32           `json:"subscription_id"`
+	TenantID       string          `json:"tenant_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Share          sql.NullFloat64 `json:"share"`
+}
+
+type SubscriptionPause struct {
+	ID             int32        `json:"id"`
+	SubscriptionID int32        `json:"subscription_id"`
+	TenantID       string       `json:"tenant_id"`
+	PausedFrom     time.Time    `json:"paused_from"`
+	ResumedAt      sql.NullTime `json:"resumed_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type SubscriptionPhase struct {
+	ID             int32         `json:"id"`
+	SubscriptionID int32         `json:"subscription_id"`
+	TenantID       string        `json:"tenant_id"`
+	Position       int32         `json:"position"`
+	Kind           string        `json:"kind"`
+	Price          int32         `json:"price"`
+	Months         sql.NullInt32 `json:"months"`
+	Days           sql.NullInt32 `json:"days"`
+	StartDate      time.Time     `json:"start_date"`
+	EndDate        time.Time     `json:"end_date"`
+}
+
+type SubscriptionPrice struct {
+	ID             int32     `json:"id"`
+	SubscriptionID int32     `json:"subscription_id"`
+	TenantID       string    `json:"tenant_id"`
+	EffectiveFrom  time.Time `json:"effective_from"`
+	Price          int32     `json:"price"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SubscriptionTag struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TagID          int32  `json:"tag_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+type Tag struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+}
+
+type Tenant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Webhook struct {
+	ID        int32     `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookAttempt struct {
+	ID          int64          `json:"id"`
+	TenantID    string         `json:"tenant_id"`
+	DeliveryID  int64          `json:"delivery_id"`
+	StatusCode  sql.NullInt32  `json:"status_code"`
+	Error       sql.NullString `json:"error"`
+	DurationMs  int32          `json:"duration_ms"`
+	AttemptedAt time.Time      `json:"attempted_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64          `json:"id"`
+	TenantID       string         `json:"tenant_id"`
+	WebhookID      int32          `json:"webhook_id"`
+	EventID        int64          `json:"event_id"`
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `json:"last_status_code"`
+	LastError      sql.NullString `json:"last_error"`
+	DeliveredAt    sql.NullTime   `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type WebhookEvent struct {
+	ID             int64           `json:"id"`
+	TenantID       string          `json:"tenant_id"`
+	EventType      string          `json:"event_type"`
+	SubscriptionID int32           `json:"subscription_id"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
 }
@@ -0,0 +1,155 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: pauses.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createPause = `-- name: CreatePause :one
+INSERT INTO subscription_pauses (subscription_id, tenant_id, paused_from, resumed_at) VALUES ($1, $2, $3, $4) RETURNING id, subscription_id, tenant_id, paused_from, resumed_at, created_at
+`
+
+type CreatePauseParams struct {
+	SubscriptionID int32        `json:"subscription_id"`
+	TenantID       string       `json:"tenant_id"`
+	PausedFrom     time.Time    `json:"paused_from"`
+	ResumedAt      sql.NullTime `json:"resumed_at"`
+}
+
+func (q *Queries) CreatePause(ctx context.Context, arg CreatePauseParams) (SubscriptionPause, error) {
+	row := q.db.QueryRowContext(ctx, createPause,
+		arg.SubscriptionID,
+		arg.TenantID,
+		arg.PausedFrom,
+		arg.ResumedAt,
+	)
+	var i SubscriptionPause
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.TenantID,
+		&i.PausedFrom,
+		&i.ResumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePause = `-- name: DeletePause :exec
+DELETE FROM subscription_pauses WHERE id = $1 AND tenant_id = $2
+`
+
+type DeletePauseParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) DeletePause(ctx context.Context, arg DeletePauseParams) error {
+	_, err := q.db.ExecContext(ctx, deletePause, arg.ID, arg.TenantID)
+	return err
+}
+
+const pausesForSubscriptions = `-- name: PausesForSubscriptions :many
+SELECT id, subscription_id, tenant_id, paused_from, resumed_at, created_at FROM subscription_pauses WHERE subscription_id = ANY($1::int[]) ORDER BY subscription_id, paused_from
+`
+
+func (q *Queries) PausesForSubscriptions(ctx context.Context, subscriptionIds []int32) ([]SubscriptionPause, error) {
+	rows, err := q.db.QueryContext(ctx, pausesForSubscriptions, pq.Array(subscriptionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPause
+	for rows.Next() {
+		var i SubscriptionPause
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.PausedFrom,
+			&i.ResumedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resumePause = `-- name: ResumePause :one
+UPDATE subscription_pauses SET resumed_at = $3 WHERE id = $1 AND tenant_id = $2 RETURNING id, subscription_id, tenant_id, paused_from, resumed_at, created_at
+`
+
+type ResumePauseParams struct {
+	ID        int32        `json:"id"`
+	TenantID  string       `json:"tenant_id"`
+	ResumedAt sql.NullTime `json:"resumed_at"`
+}
+
+func (q *Queries) ResumePause(ctx context.Context, arg ResumePauseParams) (SubscriptionPause, error) {
+	row := q.db.QueryRowContext(ctx, resumePause, arg.ID, arg.TenantID, arg.ResumedAt)
+	var i SubscriptionPause
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.TenantID,
+		&i.PausedFrom,
+		&i.ResumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const subscriptionPauses = `-- name: SubscriptionPauses :many
+SELECT id, subscription_id, tenant_id, paused_from, resumed_at, created_at FROM subscription_pauses WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY paused_from
+`
+
+type SubscriptionPausesParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) SubscriptionPauses(ctx context.Context, arg SubscriptionPausesParams) ([]SubscriptionPause, error) {
+	rows, err := q.db.QueryContext(ctx, subscriptionPauses, arg.SubscriptionID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPause
+	for rows.Next() {
+		var i SubscriptionPause
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.PausedFrom,
+			&i.ResumedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,212 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: phases.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const createPhase = `-- name: CreatePhase :exec
+INSERT INTO subscription_phases (subscription_id, tenant_id, position, kind, price, months, days, start_date, end_date) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type CreatePhaseParams struct {
+	SubscriptionID int32         `json:"subscription_id"`
+	TenantID       string        `json:"tenant_id"`
+	Position       int32         `json:"position"`
+	Kind           string        `json:"kind"`
+	Price          int32         `json:"price"`
+	Months         sql.NullInt32 `json:"months"`
+	Days           sql.NullInt32 `json:"days"`
+	StartDate      time.Time     `json:"start_date"`
+	EndDate        time.Time     `json:"end_date"`
+}
+
+func (q *Queries) CreatePhase(ctx context.Context, arg CreatePhaseParams) error {
+	_, err := q.db.ExecContext(ctx, createPhase,
+		arg.SubscriptionID,
+		arg.TenantID,
+		arg.Position,
+		arg.Kind,
+		arg.Price,
+		arg.Months,
+		arg.Days,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	return err
+}
+
+const deletePhases = `-- name: DeletePhases :exec
+DELETE FROM subscription_phases WHERE subscription_id = $1 AND tenant_id = $2
+`
+
+type DeletePhasesParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) DeletePhases(ctx context.Context, arg DeletePhasesParams) error {
+	_, err := q.db.ExecContext(ctx, deletePhases, arg.SubscriptionID, arg.TenantID)
+	return err
+}
+
+const phasesForSubscriptions = `-- name: PhasesForSubscriptions :many
+SELECT id, subscription_id, tenant_id, position, kind, price, months, days, start_date, end_date FROM subscription_phases WHERE subscription_id = ANY($1::int[]) ORDER BY subscription_id, position
+`
+
+func (q *Queries) PhasesForSubscriptions(ctx context.Context, subscriptionIds []int32) ([]SubscriptionPhase, error) {
+	rows, err := q.db.QueryContext(ctx, phasesForSubscriptions, pq.Array(subscriptionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPhase
+	for rows.Next() {
+		var i SubscriptionPhase
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.Position,
+			&i.Kind,
+			&i.Price,
+			&i.Months,
+			&i.Days,
+			&i.StartDate,
+			&i.EndDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const subscriptionPhases = `-- name: SubscriptionPhases :many
+SELECT id, subscription_id, tenant_id, position, kind, price, months, days, start_date, end_date FROM subscription_phases WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY position
+`
+
+type SubscriptionPhasesParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) SubscriptionPhases(ctx context.Context, arg SubscriptionPhasesParams) ([]SubscriptionPhase, error) {
+	rows, err := q.db.QueryContext(ctx, subscriptionPhases, arg.SubscriptionID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPhase
+	for rows.Next() {
+		var i SubscriptionPhase
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.Position,
+			&i.Kind,
+			&i.Price,
+			&i.Months,
+			&i.Days,
+			&i.StartDate,
+			&i.EndDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trialsEnding = `-- name: TrialsEnding :many
+SELECT subscriptions.id, subscriptions.service_name, subscriptions.price, subscriptions.user_id, subscriptions.start_date, subscriptions.end_date, subscriptions.tenant_id, subscriptions.deleted_at, subscriptions.currency, subscriptions.billing_period, subscriptions.billing_months, subscriptions.anchor_date, subscriptions.service_id, subscriptions.category, subscriptions.cancelled_at, subscriptions.end_date_before_cancel, subscriptions.split_rule, subscription_phases.end_date AS trial_end_date
+FROM subscription_phases
+JOIN subscriptions ON subscriptions.id = subscription_phases.subscription_id
+WHERE subscription_phases.tenant_id = $1
+  AND subscriptions.deleted_at IS NULL
+  AND subscription_phases.kind = 'trial'
+  AND subscription_phases.end_date BETWEEN $2::date AND $3::date
+  AND ($4::uuid IS NULL OR subscriptions.user_id = $4)
+ORDER BY subscription_phases.end_date, subscriptions.id
+`
+
+type TrialsEndingParams struct {
+	TenantID string        `json:"tenant_id"`
+	FromDate time.Time     `json:"from_date"`
+	ToDate   time.Time     `json:"to_date"`
+	UserID   uuid.NullUUID `json:"user_id"`
+}
+
+type TrialsEndingRow struct {
+	Subscription Subscription `json:"subscription"`
+	TrialEndDate time.Time    `json:"trial_end_date"`
+}
+
+func (q *Queries) TrialsEnding(ctx context.Context, arg TrialsEndingParams) ([]TrialsEndingRow, error) {
+	rows, err := q.db.QueryContext(ctx, trialsEnding,
+		arg.TenantID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrialsEndingRow
+	for rows.Next() {
+		var i TrialsEndingRow
+		if err := rows.Scan(
+			&i.Subscription.ID,
+			&i.Subscription.ServiceName,
+			&i.Subscription.Price,
+			&i.Subscription.UserID,
+			&i.Subscription.StartDate,
+			&i.Subscription.EndDate,
+			&i.Subscription.TenantID,
+			&i.Subscription.DeletedAt,
+			&i.Subscription.Currency,
+			&i.Subscription.BillingPeriod,
+			&i.Subscription.BillingMonths,
+			&i.Subscription.AnchorDate,
+			&i.Subscription.ServiceID,
+			&i.Subscription.Category,
+			&i.Subscription.CancelledAt,
+			&i.Subscription.EndDateBeforeCancel,
+			&i.Subscription.SplitRule,
+			&i.TrialEndDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,194 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: prices.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const activeServiceSubscriptions = `-- name: ActiveServiceSubscriptions :many
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions
+WHERE tenant_id = $1
+  AND lower(service_name) = lower($2)
+  AND deleted_at IS NULL
+  AND (end_date IS NULL OR end_date >= $3::date)
+ORDER BY id
+`
+
+type ActiveServiceSubscriptionsParams struct {
+	TenantID      string    `json:"tenant_id"`
+	ServiceName   string    `json:"service_name"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+func (q *Queries) ActiveServiceSubscriptions(ctx context.Context, arg ActiveServiceSubscriptionsParams) ([]Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, activeServiceSubscriptions, arg.TenantID, arg.ServiceName, arg.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServiceName,
+			&i.Price,
+			&i.UserID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePricesAfter = `-- name: DeletePricesAfter :exec
+DELETE FROM subscription_prices WHERE subscription_id = $1 AND tenant_id = $2 AND effective_from > $3
+`
+
+type DeletePricesAfterParams struct {
+	SubscriptionID int32     `json:"subscription_id"`
+	TenantID       string    `json:"tenant_id"`
+	EffectiveFrom  time.Time `json:"effective_from"`
+}
+
+func (q *Queries) DeletePricesAfter(ctx context.Context, arg DeletePricesAfterParams) error {
+	_, err := q.db.ExecContext(ctx, deletePricesAfter, arg.SubscriptionID, arg.TenantID, arg.EffectiveFrom)
+	return err
+}
+
+const pricesForSubscriptions = `-- name: PricesForSubscriptions :many
+SELECT id, subscription_id, tenant_id, effective_from, price, created_at FROM subscription_prices WHERE subscription_id = ANY($1::int[]) ORDER BY subscription_id, effective_from
+`
+
+func (q *Queries) PricesForSubscriptions(ctx context.Context, subscriptionIds []int32) ([]SubscriptionPrice, error) {
+	rows, err := q.db.QueryContext(ctx, pricesForSubscriptions, pq.Array(subscriptionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPrice
+	for rows.Next() {
+		var i SubscriptionPrice
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.EffectiveFrom,
+			&i.Price,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const savePrice = `-- name: SavePrice :exec
+INSERT INTO subscription_prices (subscription_id, tenant_id, effective_from, price) VALUES ($1, $2, $3, $4)
+ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = now()
+`
+
+type SavePriceParams struct {
+	SubscriptionID int32     `json:"subscription_id"`
+	TenantID       string    `json:"tenant_id"`
+	EffectiveFrom  time.Time `json:"effective_from"`
+	Price          int32     `json:"price"`
+}
+
+func (q *Queries) SavePrice(ctx context.Context, arg SavePriceParams) error {
+	_, err := q.db.ExecContext(ctx, savePrice,
+		arg.SubscriptionID,
+		arg.TenantID,
+		arg.EffectiveFrom,
+		arg.Price,
+	)
+	return err
+}
+
+const setSubscriptionPrice = `-- name: SetSubscriptionPrice :exec
+UPDATE subscriptions SET price = $3 WHERE id = $1 AND tenant_id = $2
+`
+
+type SetSubscriptionPriceParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+	Price    int32  `json:"price"`
+}
+
+func (q *Queries) SetSubscriptionPrice(ctx context.Context, arg SetSubscriptionPriceParams) error {
+	_, err := q.db.ExecContext(ctx, setSubscriptionPrice, arg.ID, arg.TenantID, arg.Price)
+	return err
+}
+
+const subscriptionPrices = `-- name: SubscriptionPrices :many
+SELECT id, subscription_id, tenant_id, effective_from, price, created_at FROM subscription_prices WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY effective_from
+`
+
+type SubscriptionPricesParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) SubscriptionPrices(ctx context.Context, arg SubscriptionPricesParams) ([]SubscriptionPrice, error) {
+	rows, err := q.db.QueryContext(ctx, subscriptionPrices, arg.SubscriptionID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPrice
+	for rows.Next() {
+		var i SubscriptionPrice
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.TenantID,
+			&i.EffectiveFrom,
+			&i.Price,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
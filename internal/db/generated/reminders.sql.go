@@ -0,0 +1,268 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: reminders.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const allNotificationSettings = `-- name: AllNotificationSettings :many
+SELECT tenant_id, user_id, reminder_days, email, locale, email_reminders, email_budget_alerts FROM notification_settings
+`
+
+func (q *Queries) AllNotificationSettings(ctx context.Context) ([]NotificationSetting, error) {
+	rows, err := q.db.QueryContext(ctx, allNotificationSettings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationSetting
+	for rows.Next() {
+		var i NotificationSetting
+		if err := rows.Scan(
+			&i.TenantID,
+			&i.UserID,
+			&i.ReminderDays,
+			&i.Email,
+			&i.Locale,
+			&i.EmailReminders,
+			&i.EmailBudgetAlerts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimReminders = `-- name: ClaimReminders :many
+UPDATE reminder_jobs
+SET attempts = attempts + 1, next_attempt_at = $1
+WHERE id IN (
+  SELECT id FROM reminder_jobs
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT $2::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tenant_id, subscription_id, user_id, kind, event_date, service_name, amount, currency, status, attempts, last_error, next_attempt_at, sent_at, created_at, monthly_limit
+`
+
+type ClaimRemindersParams struct {
+	LeaseUntil time.Time `json:"lease_until"`
+	Batch      int32     `json:"batch"`
+}
+
+func (q *Queries) ClaimReminders(ctx context.Context, arg ClaimRemindersParams) ([]ReminderJob, error) {
+	rows, err := q.db.QueryContext(ctx, claimReminders, arg.LeaseUntil, arg.Batch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReminderJob
+	for rows.Next() {
+		var i ReminderJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.SubscriptionID,
+			&i.UserID,
+			&i.Kind,
+			&i.EventDate,
+			&i.ServiceName,
+			&i.Amount,
+			&i.Currency,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.MonthlyLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const enqueueBudgetAlert = `-- name: EnqueueBudgetAlert :exec
+INSERT INTO reminder_jobs (tenant_id, subscription_id, user_id, kind, event_date, service_name, amount, currency, monthly_limit)
+VALUES ($1, $2, $3, 'budget_exceeded', $4, $5, $6, $7, $8)
+ON CONFLICT (subscription_id, user_id, kind, event_date) DO NOTHING
+`
+
+type EnqueueBudgetAlertParams struct {
+	TenantID       string        `json:"tenant_id"`
+	SubscriptionID int32         `json:"subscription_id"`
+	UserID         uuid.UUID     `json:"user_id"`
+	EventDate      time.Time     `json:"event_date"`
+	ServiceName    string        `json:"service_name"`
+	Amount         int32         `json:"amount"`
+	Currency       string        `json:"currency"`
+	MonthlyLimit   sql.NullInt32 `json:"monthly_limit"`
+}
+
+func (q *Queries) EnqueueBudgetAlert(ctx context.Context, arg EnqueueBudgetAlertParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueBudgetAlert,
+		arg.TenantID,
+		arg.SubscriptionID,
+		arg.UserID,
+		arg.EventDate,
+		arg.ServiceName,
+		arg.Amount,
+		arg.Currency,
+		arg.MonthlyLimit,
+	)
+	return err
+}
+
+const enqueueReminder = `-- name: EnqueueReminder :exec
+INSERT INTO reminder_jobs (tenant_id, subscription_id, user_id, kind, event_date, service_name, amount, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (subscription_id, user_id, kind, event_date) DO NOTHING
+`
+
+type EnqueueReminderParams struct {
+	TenantID       string    `json:"tenant_id"`
+	SubscriptionID int32     `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Kind           string    `json:"kind"`
+	EventDate      time.Time `json:"event_date"`
+	ServiceName    string    `json:"service_name"`
+	Amount         int32     `json:"amount"`
+	Currency       string    `json:"currency"`
+}
+
+func (q *Queries) EnqueueReminder(ctx context.Context, arg EnqueueReminderParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueReminder,
+		arg.TenantID,
+		arg.SubscriptionID,
+		arg.UserID,
+		arg.Kind,
+		arg.EventDate,
+		arg.ServiceName,
+		arg.Amount,
+		arg.Currency,
+	)
+	return err
+}
+
+const getNotificationSettings = `-- name: GetNotificationSettings :one
+SELECT tenant_id, user_id, reminder_days, email, locale, email_reminders, email_budget_alerts FROM notification_settings WHERE tenant_id = $1 AND user_id = $2
+`
+
+type GetNotificationSettingsParams struct {
+	TenantID string    `json:"tenant_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetNotificationSettings(ctx context.Context, arg GetNotificationSettingsParams) (NotificationSetting, error) {
+	row := q.db.QueryRowContext(ctx, getNotificationSettings, arg.TenantID, arg.UserID)
+	var i NotificationSetting
+	err := row.Scan(
+		&i.TenantID,
+		&i.UserID,
+		&i.ReminderDays,
+		&i.Email,
+		&i.Locale,
+		&i.EmailReminders,
+		&i.EmailBudgetAlerts,
+	)
+	return i, err
+}
+
+const markReminderFailed = `-- name: MarkReminderFailed :exec
+UPDATE reminder_jobs SET status = $1, last_error = $2, next_attempt_at = $3 WHERE id = $4
+`
+
+type MarkReminderFailedParams struct {
+	Status        string         `json:"status"`
+	LastError     sql.NullString `json:"last_error"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	ID            int32          `json:"id"`
+}
+
+func (q *Queries) MarkReminderFailed(ctx context.Context, arg MarkReminderFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markReminderFailed,
+		arg.Status,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const markReminderSent = `-- name: MarkReminderSent :exec
+UPDATE reminder_jobs SET status = 'sent', sent_at = now(), last_error = NULL WHERE id = $1
+`
+
+func (q *Queries) MarkReminderSent(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, markReminderSent, id)
+	return err
+}
+
+const saveNotificationSettings = `-- name: SaveNotificationSettings :one
+INSERT INTO notification_settings (tenant_id, user_id, reminder_days, email, locale, email_reminders, email_budget_alerts)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+  reminder_days = EXCLUDED.reminder_days,
+  email = EXCLUDED.email,
+  locale = EXCLUDED.locale,
+  email_reminders = EXCLUDED.email_reminders,
+  email_budget_alerts = EXCLUDED.email_budget_alerts
+RETURNING tenant_id, user_id, reminder_days, email, locale, email_reminders, email_budget_alerts
+`
+
+type SaveNotificationSettingsParams struct {
+	TenantID          string         `json:"tenant_id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	ReminderDays      int32          `json:"reminder_days"`
+	Email             sql.NullString `json:"email"`
+	Locale            string         `json:"locale"`
+	EmailReminders    bool           `json:"email_reminders"`
+	EmailBudgetAlerts bool           `json:"email_budget_alerts"`
+}
+
+func (q *Queries) SaveNotificationSettings(ctx context.Context, arg SaveNotificationSettingsParams) (NotificationSetting, error) {
+	row := q.db.QueryRowContext(ctx, saveNotificationSettings,
+		arg.TenantID,
+		arg.UserID,
+		arg.ReminderDays,
+		arg.Email,
+		arg.Locale,
+		arg.EmailReminders,
+		arg.EmailBudgetAlerts,
+	)
+	var i NotificationSetting
+	err := row.Scan(
+		&i.TenantID,
+		&i.UserID,
+		&i.ReminderDays,
+		&i.Email,
+		&i.Locale,
+		&i.EmailReminders,
+		&i.EmailBudgetAlerts,
+	)
+	return i, err
+}
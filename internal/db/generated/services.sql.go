@@ -0,0 +1,265 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: services.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const createService = `-- name: CreateService :one
+INSERT INTO services (tenant_id, name, aliases, category, default_price, currency) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, tenant_id, name, aliases, category, default_price, currency, created_at
+`
+
+type CreateServiceParams struct {
+	TenantID     string         `json:"tenant_id"`
+	Name         string         `json:"name"`
+	Aliases      []string       `json:"aliases"`
+	Category     sql.NullString `json:"category"`
+	DefaultPrice sql.NullInt32  `json:"default_price"`
+	Currency     string         `json:"currency"`
+}
+
+func (q *Queries) CreateService(ctx context.Context, arg CreateServiceParams) (Service, error) {
+	row := q.db.QueryRowContext(ctx, createService,
+		arg.TenantID,
+		arg.Name,
+		pq.Array(arg.Aliases),
+		arg.Category,
+		arg.DefaultPrice,
+		arg.Currency,
+	)
+	var i Service
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		pq.Array(&i.Aliases),
+		&i.Category,
+		&i.DefaultPrice,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteService = `-- name: DeleteService :execrows
+DELETE FROM services WHERE id = $1 AND tenant_id = $2
+`
+
+type DeleteServiceParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteService(ctx context.Context, arg DeleteServiceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteService, arg.ID, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getService = `-- name: GetService :one
+SELECT id, tenant_id, name, aliases, category, default_price, currency, created_at FROM services WHERE id = $1 AND tenant_id = $2
+`
+
+type GetServiceParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetService(ctx context.Context, arg GetServiceParams) (Service, error) {
+	row := q.db.QueryRowContext(ctx, getService, arg.ID, arg.TenantID)
+	var i Service
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		pq.Array(&i.Aliases),
+		&i.Category,
+		&i.DefaultPrice,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const linkServices = `-- name: LinkServices :execrows
+UPDATE subscriptions
+SET service_id = services.id, service_name = services.name
+FROM services
+WHERE subscriptions.tenant_id = $1
+  AND services.tenant_id = subscriptions.tenant_id
+  AND subscriptions.service_id IS NULL
+  AND (lower(services.name) = lower(subscriptions.service_name)
+    OR EXISTS (SELECT 1 FROM unnest(services.aliases) AS alias WHERE lower(alias) = lower(subscriptions.service_name)))
+`
+
+func (q *Queries) LinkServices(ctx context.Context, tenantID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, linkServices, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameServiceSubscriptions = `-- name: RenameServiceSubscriptions :exec
+UPDATE subscriptions SET service_name = $3 WHERE service_id = $1 AND tenant_id = $2
+`
+
+type RenameServiceSubscriptionsParams struct {
+	ServiceID   sql.NullInt32 `json:"service_id"`
+	TenantID    string        `json:"tenant_id"`
+	ServiceName string        `json:"service_name"`
+}
+
+func (q *Queries) RenameServiceSubscriptions(ctx context.Context, arg RenameServiceSubscriptionsParams) error {
+	_, err := q.db.ExecContext(ctx, renameServiceSubscriptions, arg.ServiceID, arg.TenantID, arg.ServiceName)
+	return err
+}
+
+const resolveService = `-- name: ResolveService :one
+SELECT id, tenant_id, name, aliases, category, default_price, currency, created_at FROM services
+WHERE tenant_id = $1
+  AND (lower(name) = lower($2::text)
+    OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE lower(alias) = lower($2::text)))
+ORDER BY lower(name) = lower($2::text) DESC, id
+LIMIT 1
+`
+
+type ResolveServiceParams struct {
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+}
+
+func (q *Queries) ResolveService(ctx context.Context, arg ResolveServiceParams) (Service, error) {
+	row := q.db.QueryRowContext(ctx, resolveService, arg.TenantID, arg.Name)
+	var i Service
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		pq.Array(&i.Aliases),
+		&i.Category,
+		&i.DefaultPrice,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const servicesList = `-- name: ServicesList :many
+SELECT id, tenant_id, name, aliases, category, default_price, currency, created_at FROM services WHERE tenant_id = $1 ORDER BY name
+`
+
+func (q *Queries) ServicesList(ctx context.Context, tenantID string) ([]Service, error) {
+	rows, err := q.db.QueryContext(ctx, servicesList, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Service
+	for rows.Next() {
+		var i Service
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.Name,
+			pq.Array(&i.Aliases),
+			&i.Category,
+			&i.DefaultPrice,
+			&i.Currency,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unmatchedServiceNames = `-- name: UnmatchedServiceNames :many
+SELECT service_name, count(*) AS subscriptions
+FROM subscriptions
+WHERE tenant_id = $1 AND service_id IS NULL AND deleted_at IS NULL
+GROUP BY service_name
+ORDER BY service_name
+`
+
+type UnmatchedServiceNamesRow struct {
+	ServiceName   string `json:"service_name"`
+	Subscriptions int64  `json:"subscriptions"`
+}
+
+func (q *Queries) UnmatchedServiceNames(ctx context.Context, tenantID string) ([]UnmatchedServiceNamesRow, error) {
+	rows, err := q.db.QueryContext(ctx, unmatchedServiceNames, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UnmatchedServiceNamesRow
+	for rows.Next() {
+		var i UnmatchedServiceNamesRow
+		if err := rows.Scan(&i.ServiceName, &i.Subscriptions); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateService = `-- name: UpdateService :one
+UPDATE services SET name = $3, aliases = $4, category = $5, default_price = $6, currency = $7 WHERE id = $1 AND tenant_id = $2 RETURNING id, tenant_id, name, aliases, category, default_price, currency, created_at
+`
+
+type UpdateServiceParams struct {
+	ID           int32          `json:"id"`
+	TenantID     string         `json:"tenant_id"`
+	Name         string         `json:"name"`
+	Aliases      []string       `json:"aliases"`
+	Category     sql.NullString `json:"category"`
+	DefaultPrice sql.NullInt32  `json:"default_price"`
+	Currency     string         `json:"currency"`
+}
+
+func (q *Queries) UpdateService(ctx context.Context, arg UpdateServiceParams) (Service, error) {
+	row := q.db.QueryRowContext(ctx, updateService,
+		arg.ID,
+		arg.TenantID,
+		arg.Name,
+		pq.Array(arg.Aliases),
+		arg.Category,
+		arg.DefaultPrice,
+		arg.Currency,
+	)
+	var i Service
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		pq.Array(&i.Aliases),
+		&i.Category,
+		&i.DefaultPrice,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -11,46 +11,207 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-const createSubscription = `-- name: CreateSubscription :exec
-INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date) VALUES ($1, $2, $3, $4, $5)
+const cancelSubscription = `-- name: CancelSubscription :one
+UPDATE subscriptions
+SET cancelled_at = COALESCE(cancelled_at, now()),
+    end_date_before_cancel = CASE WHEN cancelled_at IS NULL THEN end_date ELSE end_date_before_cancel END,
+    end_date = $1
+WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL
+RETURNING id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule
+`
+
+type CancelSubscriptionParams struct {
+	EndDate  sql.NullTime `json:"end_date"`
+	ID       int32        `json:"id"`
+	TenantID string       `json:"tenant_id"`
+}
+
+func (q *Queries) CancelSubscription(ctx context.Context, arg CancelSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRowContext(ctx, cancelSubscription, arg.EndDate, arg.ID, arg.TenantID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceName,
+		&i.Price,
+		&i.UserID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TenantID,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.BillingPeriod,
+		&i.BillingMonths,
+		&i.AnchorDate,
+		&i.ServiceID,
+		&i.Category,
+		&i.CancelledAt,
+		&i.EndDateBeforeCancel,
+		&i.SplitRule,
+	)
+	return i, err
+}
+
+const createSubscription = `-- name: CreateSubscription :one
+INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date, tenant_id, currency, billing_period, billing_months, anchor_date, service_id, category, split_rule) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule
 `
 
 type CreateSubscriptionParams struct {
-	ServiceName string       `json:"service_name"`
-	Price       int32        `json:"price"`
-	UserID      uuid.UUID    `json:"user_id"`
-	StartDate   time.Time    `json:"start_date"`
-	EndDate     sql.NullTime `json:"end_date"`
+	ServiceName   string         `json:"service_name"`
+	Price         int32          `json:"price"`
+	UserID        uuid.UUID      `json:"user_id"`
+	StartDate     time.Time      `json:"start_date"`
+	EndDate       sql.NullTime   `json:"end_date"`
+	TenantID      string         `json:"tenant_id"`
+	Currency      string         `json:"currency"`
+	BillingPeriod string         `json:"billing_period"`
+	BillingMonths sql.NullInt32  `json:"billing_months"`
+	AnchorDate    sql.NullTime   `json:"anchor_date"`
+	ServiceID     sql.NullInt32  `json:"service_id"`
+	Category      sql.NullString `json:"category"`
+	SplitRule     string         `json:"split_rule"`
 }
 
-func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) error {
-	_, err := q.db.ExecContext(ctx, createSubscription,
+func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRowContext(ctx, createSubscription,
 		arg.ServiceName,
 		arg.Price,
 		arg.UserID,
 		arg.StartDate,
 		arg.EndDate,
+		arg.TenantID,
+		arg.Currency,
+		arg.BillingPeriod,
+		arg.BillingMonths,
+		arg.AnchorDate,
+		arg.ServiceID,
+		arg.Category,
+		arg.SplitRule,
 	)
-	return err
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceName,
+		&i.Price,
+		&i.UserID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TenantID,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.BillingPeriod,
+		&i.BillingMonths,
+		&i.AnchorDate,
+		&i.ServiceID,
+		&i.Category,
+		&i.CancelledAt,
+		&i.EndDateBeforeCancel,
+		&i.SplitRule,
+	)
+	return i, err
 }
 
 const deleteSubscription = `-- name: DeleteSubscription :exec
-DELETE FROM subscriptions WHERE id = $1
+UPDATE subscriptions SET deleted_at = now() WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteSubscription(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteSubscription, id)
+type DeleteSubscriptionParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteSubscription(ctx context.Context, arg DeleteSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSubscription, arg.ID, arg.TenantID)
 	return err
 }
 
+const getAllTenantsSubscriptionsWithFilter = `-- name: GetAllTenantsSubscriptionsWithFilter :many
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions
+WHERE deleted_at IS NULL
+  AND ($1::uuid IS NULL OR subscriptions.user_id = $1 OR id IN (
+    SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = $1
+  ))
+  AND ($2::text = '' OR lower(service_name) = lower($2::text))
+  AND ($3::date IS NULL OR end_date IS NULL OR end_date >= $3)
+  AND ($4::date IS NULL OR start_date <= $4)
+  AND (cardinality($5::text[]) = 0 OR category = ANY($5::text[]))
+  AND (cardinality($6::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY($6::text[])
+  ) = cardinality($6::text[]))
+ORDER BY subscriptions.tenant_id
+`
+
+type GetAllTenantsSubscriptionsWithFilterParams struct {
+	UserID      uuid.NullUUID `json:"user_id"`
+	ServiceName string        `json:"service_name"`
+	FromDate    sql.NullTime  `json:"from_date"`
+	ToDate      sql.NullTime  `json:"to_date"`
+	Categories  []string      `json:"categories"`
+	Tags        []string      `json:"tags"`
+}
+
+func (q *Queries) GetAllTenantsSubscriptionsWithFilter(ctx context.Context, arg GetAllTenantsSubscriptionsWithFilterParams) ([]Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTenantsSubscriptionsWithFilter,
+		arg.UserID,
+		arg.ServiceName,
+		arg.FromDate,
+		arg.ToDate,
+		pq.Array(arg.Categories),
+		pq.Array(arg.Tags),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServiceName,
+			&i.Price,
+			&i.UserID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubscription = `-- name: GetSubscription :one
-SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions WHERE id = $1
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetSubscription(ctx context.Context, id int32) (Subscription, error) {
-	row := q.db.QueryRowContext(ctx, getSubscription, id)
+type GetSubscriptionParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetSubscription(ctx context.Context, arg GetSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRowContext(ctx, getSubscription, arg.ID, arg.TenantID)
 	var i Subscription
 	err := row.Scan(
 		&i.ID,
@@ -59,39 +220,87 @@ func (q *Queries) GetSubscription(ctx context.Context, id int32) (Subscription,
 		&i.UserID,
 		&i.StartDate,
 		&i.EndDate,
+		&i.TenantID,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.BillingPeriod,
+		&i.BillingMonths,
+		&i.AnchorDate,
+		&i.ServiceID,
+		&i.Category,
+		&i.CancelledAt,
+		&i.EndDateBeforeCancel,
+		&i.SplitRule,
 	)
 	return i, err
 }
 
 const getSubscriptionsWithFilter = `-- name: GetSubscriptionsWithFilter :many
-SELECT price FROM subscriptions WHERE ($1 = '' OR user_id = $1::uuid) AND ($2 = '' OR service_name = $2::text) AND ($3 = '' OR start_date >= TO_DATE($3, 'MM-YYYY')) AND ($4 = '' OR end_date <= TO_DATE($4, 'MM-YYYY'))
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions
+WHERE subscriptions.tenant_id = $1
+  AND deleted_at IS NULL
+  AND ($2::uuid IS NULL OR subscriptions.user_id = $2 OR id IN (
+    SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = $2
+  ))
+  AND ($3::text = '' OR lower(service_name) = lower($3::text))
+  AND ($4::date IS NULL OR end_date IS NULL OR end_date >= $4)
+  AND ($5::date IS NULL OR start_date <= $5)
+  AND (cardinality($6::text[]) = 0 OR category = ANY($6::text[]))
+  AND (cardinality($7::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY($7::text[])
+  ) = cardinality($7::text[]))
 `
 
 type GetSubscriptionsWithFilterParams struct {
-	Column1 interface{} `json:"column_1"`
-	Column2 interface{} `json:"column_2"`
-	Column3 interface{} `json:"column_3"`
-	Column4 interface{} `json:"column_4"`
+	TenantID    string        `json:"tenant_id"`
+	UserID      uuid.NullUUID `json:"user_id"`
+	ServiceName string        `json:"service_name"`
+	FromDate    sql.NullTime  `json:"from_date"`
+	ToDate      sql.NullTime  `json:"to_date"`
+	Categories  []string      `json:"categories"`
+	Tags        []string      `json:"tags"`
 }
 
-func (q *Queries) GetSubscriptionsWithFilter(ctx context.Context, arg GetSubscriptionsWithFilterParams) ([]int32, error) {
+func (q *Queries) GetSubscriptionsWithFilter(ctx context.Context, arg GetSubscriptionsWithFilterParams) ([]Subscription, error) {
 	rows, err := q.db.QueryContext(ctx, getSubscriptionsWithFilter,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
+		arg.TenantID,
+		arg.UserID,
+		arg.ServiceName,
+		arg.FromDate,
+		arg.ToDate,
+		pq.Array(arg.Categories),
+		pq.Array(arg.Tags),
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int32
+	var items []Subscription
 	for rows.Next() {
-		var price int32
-		if err := rows.Scan(&price); err != nil {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServiceName,
+			&i.Price,
+			&i.UserID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, price)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -102,12 +311,117 @@ func (q *Queries) GetSubscriptionsWithFilter(ctx context.Context, arg GetSubscri
 	return items, nil
 }
 
+const purgeDeletedSubscriptions = `-- name: PurgeDeletedSubscriptions :execrows
+DELETE FROM subscriptions WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedSubscriptions(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedSubscriptions, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reactivateSubscription = `-- name: ReactivateSubscription :one
+UPDATE subscriptions
+SET cancelled_at = NULL, end_date = end_date_before_cancel, end_date_before_cancel = NULL
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+RETURNING id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule
+`
+
+type ReactivateSubscriptionParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) ReactivateSubscription(ctx context.Context, arg ReactivateSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRowContext(ctx, reactivateSubscription, arg.ID, arg.TenantID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceName,
+		&i.Price,
+		&i.UserID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TenantID,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.BillingPeriod,
+		&i.BillingMonths,
+		&i.AnchorDate,
+		&i.ServiceID,
+		&i.Category,
+		&i.CancelledAt,
+		&i.EndDateBeforeCancel,
+		&i.SplitRule,
+	)
+	return i, err
+}
+
+const restoreSubscription = `-- name: RestoreSubscription :one
+UPDATE subscriptions SET deleted_at = NULL WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL RETURNING id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule
+`
+
+type RestoreSubscriptionParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) RestoreSubscription(ctx context.Context, arg RestoreSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRowContext(ctx, restoreSubscription, arg.ID, arg.TenantID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceName,
+		&i.Price,
+		&i.UserID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TenantID,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.BillingPeriod,
+		&i.BillingMonths,
+		&i.AnchorDate,
+		&i.ServiceID,
+		&i.Category,
+		&i.CancelledAt,
+		&i.EndDateBeforeCancel,
+		&i.SplitRule,
+	)
+	return i, err
+}
+
 const subscriptionsList = `-- name: SubscriptionsList :many
-SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions
+WHERE subscriptions.tenant_id = $1
+  AND (deleted_at IS NULL OR $2::bool)
+  AND ($3::uuid IS NULL OR user_id = $3)
+  AND (cardinality($4::text[]) = 0 OR category = ANY($4::text[]))
+  AND (cardinality($5::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY($5::text[])
+  ) = cardinality($5::text[]))
 `
 
-func (q *Queries) SubscriptionsList(ctx context.Context) ([]Subscription, error) {
-	rows, err := q.db.QueryContext(ctx, subscriptionsList)
+type SubscriptionsListParams struct {
+	TenantID       string        `json:"tenant_id"`
+	IncludeDeleted bool          `json:"include_deleted"`
+	UserID         uuid.NullUUID `json:"user_id"`
+	Categories     []string      `json:"categories"`
+	Tags           []string      `json:"tags"`
+}
+
+func (q *Queries) SubscriptionsList(ctx context.Context, arg SubscriptionsListParams) ([]Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, subscriptionsList,
+		arg.TenantID,
+		arg.IncludeDeleted,
+		arg.UserID,
+		pq.Array(arg.Categories),
+		pq.Array(arg.Tags),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +436,17 @@ func (q *Queries) SubscriptionsList(ctx context.Context) ([]Subscription, error)
 			&i.UserID,
 			&i.StartDate,
 			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
 		); err != nil {
 			return nil, err
 		}
@@ -136,37 +461,80 @@ func (q *Queries) SubscriptionsList(ctx context.Context) ([]Subscription, error)
 	return items, nil
 }
 
-const updateSubscription = `-- name: UpdateSubscription :exec
-UPDATE subscriptions SET service_name = $2, price = $3, user_id = $4, start_date = $5, end_date = $6 WHERE id = $1
+const updateSubscription = `-- name: UpdateSubscription :one
+UPDATE subscriptions SET service_name = $2, price = $3, user_id = $4, start_date = $5, end_date = $6, currency = $8, billing_period = $9, billing_months = $10, anchor_date = $11, service_id = $12, category = $13, split_rule = $14 WHERE id = $1 AND tenant_id = $7 AND deleted_at IS NULL RETURNING id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule
 `
 
 type UpdateSubscriptionParams struct {
-	ID          int32        `json:"id"`
-	ServiceName string       `json:"service_name"`
-	Price       int32        `json:"price"`
-	UserID      uuid.UUID    `json:"user_id"`
-	StartDate   time.Time    `json:"start_date"`
-	EndDate     sql.NullTime `json:"end_date"`
+	ID            int32          `json:"id"`
+	ServiceName   string         `json:"service_name"`
+	Price         int32          `json:"price"`
+	UserID        uuid.UUID      `json:"user_id"`
+	StartDate     time.Time      `json:"start_date"`
+	EndDate       sql.NullTime   `json:"end_date"`
+	TenantID      string         `json:"tenant_id"`
+	Currency      string         `json:"currency"`
+	BillingPeriod string         `json:"billing_period"`
+	BillingMonths sql.NullInt32  `json:"billing_months"`
+	AnchorDate    sql.NullTime   `json:"anchor_date"`
+	ServiceID     sql.NullInt32  `json:"service_id"`
+	Category      sql.NullString `json:"category"`
+	SplitRule     string         `json:"split_rule"`
 }
 
-func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) error {
-	_, err := q.db.ExecContext(ctx, updateSubscription,
+func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRowContext(ctx, updateSubscription,
 		arg.ID,
 		arg.ServiceName,
 		arg.Price,
 		arg.UserID,
 		arg.StartDate,
 		arg.EndDate,
+		arg.TenantID,
+		arg.Currency,
+		arg.BillingPeriod,
+		arg.BillingMonths,
+		arg.AnchorDate,
+		arg.ServiceID,
+		arg.Category,
+		arg.SplitRule,
 	)
-	return err
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceName,
+		&i.Price,
+		&i.UserID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TenantID,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.BillingPeriod,
+		&i.BillingMonths,
+		&i.AnchorDate,
+		&i.ServiceID,
+		&i.Category,
+		&i.CancelledAt,
+		&i.EndDateBeforeCancel,
+		&i.SplitRule,
+	)
+	return i, err
 }
 
 const userSubscriptions = `-- name: UserSubscriptions :many
-SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions WHERE user_id = $1
+SELECT id, service_name, price, user_id, start_date, end_date, tenant_id, deleted_at, currency, billing_period, billing_months, anchor_date, service_id, category, cancelled_at, end_date_before_cancel, split_rule FROM subscriptions
+WHERE (subscriptions.user_id = $1 OR id IN (SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = $1))
+  AND subscriptions.tenant_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) UserSubscriptions(ctx context.Context, userID uuid.UUID) ([]Subscription, error) {
-	rows, err := q.db.QueryContext(ctx, userSubscriptions, userID)
+type UserSubscriptionsParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	TenantID string    `json:"tenant_id"`
+}
+
+func (q *Queries) UserSubscriptions(ctx context.Context, arg UserSubscriptionsParams) ([]Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, userSubscriptions, arg.UserID, arg.TenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +549,17 @@ func (q *Queries) UserSubscriptions(ctx context.Context, userID uuid.UUID) ([]Su
 			&i.UserID,
 			&i.StartDate,
 			&i.EndDate,
+			&i.TenantID,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.BillingPeriod,
+			&i.BillingMonths,
+			&i.AnchorDate,
+			&i.ServiceID,
+			&i.Category,
+			&i.CancelledAt,
+			&i.EndDateBeforeCancel,
+			&i.SplitRule,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,214 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tags.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const addSubscriptionTag = `-- name: AddSubscriptionTag :exec
+INSERT INTO subscription_tags (subscription_id, tag_id, tenant_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+`
+
+type AddSubscriptionTagParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TagID          int32  `json:"tag_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) AddSubscriptionTag(ctx context.Context, arg AddSubscriptionTagParams) error {
+	_, err := q.db.ExecContext(ctx, addSubscriptionTag, arg.SubscriptionID, arg.TagID, arg.TenantID)
+	return err
+}
+
+const addSubscriptionTags = `-- name: AddSubscriptionTags :exec
+INSERT INTO subscription_tags (subscription_id, tag_id, tenant_id)
+SELECT ($1::int[])[i], ($2::int[])[i], $3::varchar
+FROM generate_series(1, cardinality($1::int[])) AS i
+ON CONFLICT DO NOTHING
+`
+
+type AddSubscriptionTagsParams struct {
+	SubscriptionIds []int32 `json:"subscription_ids"`
+	TagIds          []int32 `json:"tag_ids"`
+	TenantID        string  `json:"tenant_id"`
+}
+
+// i-й тег tag_ids добавляется i-й подписке subscription_ids
+func (q *Queries) AddSubscriptionTags(ctx context.Context, arg AddSubscriptionTagsParams) error {
+	_, err := q.db.ExecContext(ctx, addSubscriptionTags, pq.Array(arg.SubscriptionIds), pq.Array(arg.TagIds), arg.TenantID)
+	return err
+}
+
+const deleteSubscriptionTags = `-- name: DeleteSubscriptionTags :exec
+DELETE FROM subscription_tags WHERE subscription_id = $1 AND tenant_id = $2
+`
+
+type DeleteSubscriptionTagsParams struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	TenantID       string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteSubscriptionTags(ctx context.Context, arg DeleteSubscriptionTagsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSubscriptionTags, arg.SubscriptionID, arg.TenantID)
+	return err
+}
+
+const deleteTag = `-- name: DeleteTag :exec
+DELETE FROM tags WHERE id = $1 AND tenant_id = $2
+`
+
+type DeleteTagParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTag, arg.ID, arg.TenantID)
+	return err
+}
+
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, tenant_id, name FROM tags WHERE tenant_id = $1 AND lower(name) = lower($2::text)
+`
+
+type GetTagByNameParams struct {
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+}
+
+func (q *Queries) GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByName, arg.TenantID, arg.Name)
+	var i Tag
+	err := row.Scan(&i.ID, &i.TenantID, &i.Name)
+	return i, err
+}
+
+const moveSubscriptionTags = `-- name: MoveSubscriptionTags :exec
+INSERT INTO subscription_tags (subscription_id, tag_id, tenant_id)
+SELECT subscription_id, $1, tenant_id FROM subscription_tags WHERE subscription_tags.tag_id = $2 AND subscription_tags.tenant_id = $3
+ON CONFLICT DO NOTHING
+`
+
+type MoveSubscriptionTagsParams struct {
+	TargetID int32  `json:"target_id"`
+	SourceID int32  `json:"source_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) MoveSubscriptionTags(ctx context.Context, arg MoveSubscriptionTagsParams) error {
+	_, err := q.db.ExecContext(ctx, moveSubscriptionTags, arg.TargetID, arg.SourceID, arg.TenantID)
+	return err
+}
+
+const renameTag = `-- name: RenameTag :one
+UPDATE tags SET name = $3 WHERE id = $1 AND tenant_id = $2 RETURNING id, tenant_id, name
+`
+
+type RenameTagParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+	NewName  string `json:"new_name"`
+}
+
+func (q *Queries) RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, renameTag, arg.ID, arg.TenantID, arg.NewName)
+	var i Tag
+	err := row.Scan(&i.ID, &i.TenantID, &i.Name)
+	return i, err
+}
+
+const tagsForSubscriptions = `-- name: TagsForSubscriptions :many
+SELECT subscription_tags.subscription_id, tags.name
+FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+WHERE subscription_tags.subscription_id = ANY($1::int[])
+ORDER BY subscription_tags.subscription_id, lower(tags.name)
+`
+
+type TagsForSubscriptionsRow struct {
+	SubscriptionID int32  `json:"subscription_id"`
+	Name           string `json:"name"`
+}
+
+func (q *Queries) TagsForSubscriptions(ctx context.Context, subscriptionIds []int32) ([]TagsForSubscriptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, tagsForSubscriptions, pq.Array(subscriptionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagsForSubscriptionsRow
+	for rows.Next() {
+		var i TagsForSubscriptionsRow
+		if err := rows.Scan(&i.SubscriptionID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const tagsList = `-- name: TagsList :many
+SELECT tags.id, tags.name, count(subscription_tags.subscription_id) AS subscriptions
+FROM tags LEFT JOIN subscription_tags ON subscription_tags.tag_id = tags.id
+WHERE tags.tenant_id = $1
+GROUP BY tags.id, tags.name
+ORDER BY lower(tags.name)
+`
+
+type TagsListRow struct {
+	ID            int32  `json:"id"`
+	Name          string `json:"name"`
+	Subscriptions int64  `json:"subscriptions"`
+}
+
+func (q *Queries) TagsList(ctx context.Context, tenantID string) ([]TagsListRow, error) {
+	rows, err := q.db.QueryContext(ctx, tagsList, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagsListRow
+	for rows.Next() {
+		var i TagsListRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Subscriptions); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTag = `-- name: UpsertTag :one
+INSERT INTO tags (tenant_id, name) VALUES ($1, $2)
+ON CONFLICT (tenant_id, lower(name)) DO UPDATE SET name = tags.name
+RETURNING id, tenant_id, name
+`
+
+type UpsertTagParams struct {
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+}
+
+func (q *Queries) UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, upsertTag, arg.TenantID, arg.Name)
+	var i Tag
+	err := row.Scan(&i.ID, &i.TenantID, &i.Name)
+	return i, err
+}
@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tenants.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createTenant = `-- name: CreateTenant :one
+INSERT INTO tenants (id, name) VALUES ($1, $2) RETURNING id, name, created_at
+`
+
+type CreateTenantParams struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error) {
+	row := q.db.QueryRowContext(ctx, createTenant, arg.ID, arg.Name)
+	var i Tenant
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const getTenant = `-- name: GetTenant :one
+SELECT id, name, created_at FROM tenants WHERE id = $1
+`
+
+func (q *Queries) GetTenant(ctx context.Context, id string) (Tenant, error) {
+	row := q.db.QueryRowContext(ctx, getTenant, id)
+	var i Tenant
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const seedServices = `-- name: SeedServices :exec
+SELECT seed_services($1::varchar)
+`
+
+func (q *Queries) SeedServices(ctx context.Context, tenantID string) error {
+	_, err := q.db.ExecContext(ctx, seedServices, tenantID)
+	return err
+}
+
+const tenantsList = `-- name: TenantsList :many
+SELECT id, name, created_at FROM tenants ORDER BY id
+`
+
+func (q *Queries) TenantsList(ctx context.Context) ([]Tenant, error) {
+	rows, err := q.db.QueryContext(ctx, tenantsList)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tenant
+	for rows.Next() {
+		var i Tenant
+		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
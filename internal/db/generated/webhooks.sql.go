@@ -0,0 +1,730 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, next_attempt_at = $1
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= now()
+    AND webhook_id IN (SELECT webhooks.id FROM webhooks WHERE active)
+  ORDER BY next_attempt_at
+  LIMIT $2::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tenant_id, webhook_id, event_id, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseUntil time.Time `json:"lease_until"`
+	Batch      int32     `json:"batch"`
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries, arg.LeaseUntil, arg.Batch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (tenant_id, url, secret, events, active) VALUES ($1, $2, $3, $4, $5) RETURNING id, tenant_id, url, secret, events, active, created_at
+`
+
+type CreateWebhookParams struct {
+	TenantID string   `json:"tenant_id"`
+	Url      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events"`
+	Active   bool     `json:"active"`
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, createWebhook,
+		arg.TenantID,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.Active,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookAttempt = `-- name: CreateWebhookAttempt :exec
+INSERT INTO webhook_attempts (tenant_id, delivery_id, status_code, error, duration_ms) VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateWebhookAttemptParams struct {
+	TenantID   string         `json:"tenant_id"`
+	DeliveryID int64          `json:"delivery_id"`
+	StatusCode sql.NullInt32  `json:"status_code"`
+	Error      sql.NullString `json:"error"`
+	DurationMs int32          `json:"duration_ms"`
+}
+
+func (q *Queries) CreateWebhookAttempt(ctx context.Context, arg CreateWebhookAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, createWebhookAttempt,
+		arg.TenantID,
+		arg.DeliveryID,
+		arg.StatusCode,
+		arg.Error,
+		arg.DurationMs,
+	)
+	return err
+}
+
+const createWebhookDeliveries = `-- name: CreateWebhookDeliveries :exec
+INSERT INTO webhook_deliveries (tenant_id, webhook_id, event_id)
+SELECT webhooks.tenant_id, webhooks.id, $1::bigint FROM webhooks
+WHERE webhooks.tenant_id = $2 AND active
+  AND (cardinality(events) = 0 OR $3::text = ANY(events))
+`
+
+type CreateWebhookDeliveriesParams struct {
+	EventID   int64  `json:"event_id"`
+	TenantID  string `json:"tenant_id"`
+	EventType string `json:"event_type"`
+}
+
+func (q *Queries) CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) error {
+	_, err := q.db.ExecContext(ctx, createWebhookDeliveries, arg.EventID, arg.TenantID, arg.EventType)
+	return err
+}
+
+const createWebhookDeliveriesForEvents = `-- name: CreateWebhookDeliveriesForEvents :exec
+INSERT INTO webhook_deliveries (tenant_id, webhook_id, event_id)
+SELECT webhooks.tenant_id, webhooks.id, event_id FROM webhooks CROSS JOIN unnest($1::bigint[]) AS event_id
+WHERE webhooks.tenant_id = $2 AND active
+  AND (cardinality(events) = 0 OR $3::text = ANY(events))
+`
+
+type CreateWebhookDeliveriesForEventsParams struct {
+	EventIds  []int64 `json:"event_ids"`
+	TenantID  string  `json:"tenant_id"`
+	EventType string  `json:"event_type"`
+}
+
+func (q *Queries) CreateWebhookDeliveriesForEvents(ctx context.Context, arg CreateWebhookDeliveriesForEventsParams) error {
+	_, err := q.db.ExecContext(ctx, createWebhookDeliveriesForEvents, pq.Array(arg.EventIds), arg.TenantID, arg.EventType)
+	return err
+}
+
+const createWebhookEvent = `-- name: CreateWebhookEvent :one
+INSERT INTO webhook_events (tenant_id, event_type, subscription_id, payload) VALUES ($1, $2, $3, $4) RETURNING id, tenant_id, event_type, subscription_id, payload, created_at
+`
+
+type CreateWebhookEventParams struct {
+	TenantID       string          `json:"tenant_id"`
+	EventType      string          `json:"event_type"`
+	SubscriptionID int32           `json:"subscription_id"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+func (q *Queries) CreateWebhookEvent(ctx context.Context, arg CreateWebhookEventParams) (WebhookEvent, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookEvent,
+		arg.TenantID,
+		arg.EventType,
+		arg.SubscriptionID,
+		arg.Payload,
+	)
+	var i WebhookEvent
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.EventType,
+		&i.SubscriptionID,
+		&i.Payload,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookEvents = `-- name: CreateWebhookEvents :many
+INSERT INTO webhook_events (tenant_id, event_type, subscription_id, payload)
+SELECT $1::varchar, $2::varchar, ($3::int[])[i], ($4::text[])[i]::jsonb
+FROM generate_series(1, cardinality($3::int[])) AS i
+ORDER BY i
+RETURNING id
+`
+
+type CreateWebhookEventsParams struct {
+	TenantID        string   `json:"tenant_id"`
+	EventType       string   `json:"event_type"`
+	SubscriptionIds []int32  `json:"subscription_ids"`
+	Payloads        []string `json:"payloads"`
+}
+
+// События одного типа по нескольким подпискам, payloads - JSON подписок. id выдаются в порядке подписок
+func (q *Queries) CreateWebhookEvents(ctx context.Context, arg CreateWebhookEventsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, createWebhookEvents,
+		arg.TenantID,
+		arg.EventType,
+		pq.Array(arg.SubscriptionIds),
+		pq.Array(arg.Payloads),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2
+`
+
+type DeleteWebhookParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebhook, arg.ID, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deliveryTargets = `-- name: DeliveryTargets :many
+SELECT webhook_deliveries.id, webhooks.url, webhooks.secret, webhook_events.id AS event_id, webhook_events.event_type, webhook_events.subscription_id, webhook_events.payload, webhook_events.created_at
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+JOIN webhook_events ON webhook_events.id = webhook_deliveries.event_id
+WHERE webhook_deliveries.id = ANY($1::bigint[])
+`
+
+type DeliveryTargetsRow struct {
+	ID             int64           `json:"id"`
+	Url            string          `json:"url"`
+	Secret         string          `json:"secret"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	SubscriptionID int32           `json:"subscription_id"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func (q *Queries) DeliveryTargets(ctx context.Context, deliveryIds []int64) ([]DeliveryTargetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, deliveryTargets, pq.Array(deliveryIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeliveryTargetsRow
+	for rows.Next() {
+		var i DeliveryTargetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			&i.EventID,
+			&i.EventType,
+			&i.SubscriptionID,
+			&i.Payload,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhook = `-- name: GetWebhook :one
+SELECT id, tenant_id, url, secret, events, active, created_at FROM webhooks WHERE id = $1 AND tenant_id = $2
+`
+
+type GetWebhookParams struct {
+	ID       int32  `json:"id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhook, arg.ID, arg.TenantID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT webhook_deliveries.id, webhook_deliveries.tenant_id, webhook_deliveries.webhook_id, webhook_deliveries.event_id, webhook_deliveries.status, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.last_status_code, webhook_deliveries.last_error, webhook_deliveries.delivered_at, webhook_deliveries.created_at, webhook_events.event_type
+FROM webhook_deliveries JOIN webhook_events ON webhook_events.id = webhook_deliveries.event_id
+WHERE webhook_deliveries.id = $1 AND webhook_deliveries.webhook_id = $2 AND webhook_deliveries.tenant_id = $3
+`
+
+type GetWebhookDeliveryParams struct {
+	ID        int64  `json:"id"`
+	WebhookID int32  `json:"webhook_id"`
+	TenantID  string `json:"tenant_id"`
+}
+
+type GetWebhookDeliveryRow struct {
+	ID             int64          `json:"id"`
+	TenantID       string         `json:"tenant_id"`
+	WebhookID      int32          `json:"webhook_id"`
+	EventID        int64          `json:"event_id"`
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `json:"last_status_code"`
+	LastError      sql.NullString `json:"last_error"`
+	DeliveredAt    sql.NullTime   `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	EventType      string         `json:"event_type"`
+}
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, arg GetWebhookDeliveryParams) (GetWebhookDeliveryRow, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDelivery, arg.ID, arg.WebhookID, arg.TenantID)
+	var i GetWebhookDeliveryRow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WebhookID,
+		&i.EventID,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+		&i.EventType,
+	)
+	return i, err
+}
+
+const getWebhookEvent = `-- name: GetWebhookEvent :one
+SELECT id, tenant_id, event_type, subscription_id, payload, created_at FROM webhook_events WHERE id = $1
+`
+
+func (q *Queries) GetWebhookEvent(ctx context.Context, id int64) (WebhookEvent, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookEvent, id)
+	var i WebhookEvent
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.EventType,
+		&i.SubscriptionID,
+		&i.Payload,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const latestWebhookEvents = `-- name: LatestWebhookEvents :many
+SELECT id, tenant_id, event_type, subscription_id, payload, created_at FROM webhook_events ORDER BY id DESC LIMIT $1::int
+`
+
+func (q *Queries) LatestWebhookEvents(ctx context.Context, maxEvents int32) ([]WebhookEvent, error) {
+	rows, err := q.db.QueryContext(ctx, latestWebhookEvents, maxEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEvent
+	for rows.Next() {
+		var i WebhookEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.EventType,
+			&i.SubscriptionID,
+			&i.Payload,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markDeliveryDelivered = `-- name: MarkDeliveryDelivered :exec
+UPDATE webhook_deliveries SET status = 'delivered', delivered_at = now(), last_status_code = $2, last_error = NULL WHERE id = $1
+`
+
+type MarkDeliveryDeliveredParams struct {
+	ID             int64         `json:"id"`
+	LastStatusCode sql.NullInt32 `json:"last_status_code"`
+}
+
+func (q *Queries) MarkDeliveryDelivered(ctx context.Context, arg MarkDeliveryDeliveredParams) error {
+	_, err := q.db.ExecContext(ctx, markDeliveryDelivered, arg.ID, arg.LastStatusCode)
+	return err
+}
+
+const markDeliveryFailed = `-- name: MarkDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET status = $1, last_status_code = $2, last_error = $3, next_attempt_at = $4
+WHERE id = $5
+`
+
+type MarkDeliveryFailedParams struct {
+	Status         string         `json:"status"`
+	LastStatusCode sql.NullInt32  `json:"last_status_code"`
+	LastError      sql.NullString `json:"last_error"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) MarkDeliveryFailed(ctx context.Context, arg MarkDeliveryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markDeliveryFailed,
+		arg.Status,
+		arg.LastStatusCode,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const notifyWebhookEvent = `-- name: NotifyWebhookEvent :exec
+SELECT pg_notify('subscription_events', $1::text)
+`
+
+func (q *Queries) NotifyWebhookEvent(ctx context.Context, payload string) error {
+	_, err := q.db.ExecContext(ctx, notifyWebhookEvent, payload)
+	return err
+}
+
+const notifyWebhookEvents = `-- name: NotifyWebhookEvents :exec
+SELECT pg_notify('subscription_events', id::text) FROM unnest($1::bigint[]) AS id
+`
+
+func (q *Queries) NotifyWebhookEvents(ctx context.Context, eventIds []int64) error {
+	_, err := q.db.ExecContext(ctx, notifyWebhookEvents, pq.Array(eventIds))
+	return err
+}
+
+const purgeWebhookEvents = `-- name: PurgeWebhookEvents :execrows
+DELETE FROM webhook_events
+WHERE webhook_events.created_at < $1
+  AND NOT EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.event_id = webhook_events.id AND webhook_deliveries.status = 'pending')
+`
+
+// Удаляет события старше older_than вместе с доставками и попытками, кроме событий с неотправленными доставками
+func (q *Queries) PurgeWebhookEvents(ctx context.Context, olderThan time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeWebhookEvents, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const replayWebhookDelivery = `-- name: ReplayWebhookDelivery :execrows
+UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = now()
+WHERE id = $1 AND webhook_id = $2 AND tenant_id = $3 AND status <> 'pending'
+`
+
+type ReplayWebhookDeliveryParams struct {
+	ID        int64  `json:"id"`
+	WebhookID int32  `json:"webhook_id"`
+	TenantID  string `json:"tenant_id"`
+}
+
+func (q *Queries) ReplayWebhookDelivery(ctx context.Context, arg ReplayWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, replayWebhookDelivery, arg.ID, arg.WebhookID, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateWebhook = `-- name: UpdateWebhook :one
+UPDATE webhooks SET url = $3, events = $4, active = $5 WHERE id = $1 AND tenant_id = $2 RETURNING id, tenant_id, url, secret, events, active, created_at
+`
+
+type UpdateWebhookParams struct {
+	ID       int32    `json:"id"`
+	TenantID string   `json:"tenant_id"`
+	Url      string   `json:"url"`
+	Events   []string `json:"events"`
+	Active   bool     `json:"active"`
+}
+
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhook,
+		arg.ID,
+		arg.TenantID,
+		arg.Url,
+		pq.Array(arg.Events),
+		arg.Active,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const webhookAttempts = `-- name: WebhookAttempts :many
+SELECT id, tenant_id, delivery_id, status_code, error, duration_ms, attempted_at FROM webhook_attempts WHERE delivery_id = $1 AND tenant_id = $2 ORDER BY id
+`
+
+type WebhookAttemptsParams struct {
+	DeliveryID int64  `json:"delivery_id"`
+	TenantID   string `json:"tenant_id"`
+}
+
+func (q *Queries) WebhookAttempts(ctx context.Context, arg WebhookAttemptsParams) ([]WebhookAttempt, error) {
+	rows, err := q.db.QueryContext(ctx, webhookAttempts, arg.DeliveryID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookAttempt
+	for rows.Next() {
+		var i WebhookAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.DeliveryID,
+			&i.StatusCode,
+			&i.Error,
+			&i.DurationMs,
+			&i.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const webhookDeliveries = `-- name: WebhookDeliveries :many
+SELECT webhook_deliveries.id, webhook_deliveries.tenant_id, webhook_deliveries.webhook_id, webhook_deliveries.event_id, webhook_deliveries.status, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.last_status_code, webhook_deliveries.last_error, webhook_deliveries.delivered_at, webhook_deliveries.created_at, webhook_events.event_type
+FROM webhook_deliveries JOIN webhook_events ON webhook_events.id = webhook_deliveries.event_id
+WHERE webhook_deliveries.webhook_id = $1 AND webhook_deliveries.tenant_id = $2
+  AND ($3::text = '' OR webhook_deliveries.status = $3::text)
+ORDER BY webhook_deliveries.id DESC
+LIMIT 100
+`
+
+type WebhookDeliveriesParams struct {
+	WebhookID int32  `json:"webhook_id"`
+	TenantID  string `json:"tenant_id"`
+	Status    string `json:"status"`
+}
+
+type WebhookDeliveriesRow struct {
+	ID             int64          `json:"id"`
+	TenantID       string         `json:"tenant_id"`
+	WebhookID      int32          `json:"webhook_id"`
+	EventID        int64          `json:"event_id"`
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `json:"last_status_code"`
+	LastError      sql.NullString `json:"last_error"`
+	DeliveredAt    sql.NullTime   `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	EventType      string         `json:"event_type"`
+}
+
+func (q *Queries) WebhookDeliveries(ctx context.Context, arg WebhookDeliveriesParams) ([]WebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, webhookDeliveries, arg.WebhookID, arg.TenantID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDeliveriesRow
+	for rows.Next() {
+		var i WebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+			&i.EventType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const webhookEventsAfter = `-- name: WebhookEventsAfter :many
+SELECT id, tenant_id, event_type, subscription_id, payload, created_at FROM webhook_events WHERE id > $1 ORDER BY id LIMIT $2::int
+`
+
+type WebhookEventsAfterParams struct {
+	AfterID   int64 `json:"after_id"`
+	MaxEvents int32 `json:"max_events"`
+}
+
+func (q *Queries) WebhookEventsAfter(ctx context.Context, arg WebhookEventsAfterParams) ([]WebhookEvent, error) {
+	rows, err := q.db.QueryContext(ctx, webhookEventsAfter, arg.AfterID, arg.MaxEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEvent
+	for rows.Next() {
+		var i WebhookEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.EventType,
+			&i.SubscriptionID,
+			&i.Payload,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const webhooksList = `-- name: WebhooksList :many
+SELECT id, tenant_id, url, secret, events, active, created_at FROM webhooks WHERE tenant_id = $1 ORDER BY id
+`
+
+func (q *Queries) WebhooksList(ctx context.Context, tenantID string) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, webhooksList, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS tenants (
+  id VARCHAR(64) PRIMARY KEY,
+  name VARCHAR(128) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES tenants (id);
+
+CREATE INDEX IF NOT EXISTS subscriptions_tenant_id_idx ON subscriptions (tenant_id);
+
+-- Тенант выставляется на каждую транзакцию через SET LOCAL app.tenant_id.
+-- Значение '*' используется фоновыми задачами и админскими отчётами по всем тенантам.
+-- Политики не действуют на суперпользователя, поэтому сервис должен подключаться под отдельной ролью.
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscriptions_tenant_isolation ON subscriptions;
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS subscription_audit (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  subscription_id INT NOT NULL,
+  actor VARCHAR(128) NOT NULL,
+  request_id VARCHAR(128) NOT NULL DEFAULT '',
+  action VARCHAR(16) NOT NULL,
+  before JSONB,
+  after JSONB,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS subscription_audit_subscription_idx ON subscription_audit (tenant_id, subscription_id);
+CREATE INDEX IF NOT EXISTS subscription_audit_created_at_idx ON subscription_audit (tenant_id, created_at);
+
+ALTER TABLE subscription_audit ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_audit FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscription_audit_tenant_isolation ON subscription_audit;
+CREATE POLICY subscription_audit_tenant_isolation ON subscription_audit
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
@@ -0,0 +1,10 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+-- Курс - стоимость одной единицы валюты в рублях на дату rate_date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+  currency CHAR(3) NOT NULL,
+  rate_date DATE NOT NULL,
+  rate NUMERIC(18, 8) NOT NULL CHECK (rate > 0),
+  source VARCHAR(64) NOT NULL,
+  PRIMARY KEY (currency, rate_date)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS billing_period VARCHAR(16) NOT NULL DEFAULT 'monthly';
+-- Количество месяцев между списаниями для billing_period = 'custom'
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS billing_months INT;
+-- Дата первого списания, от которой отсчитываются следующие. NULL - совпадает со start_date
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS anchor_date DATE;
@@ -0,0 +1,25 @@
+-- Ценовые фазы подписки (пробный период, промо-цена) в порядке position.
+-- После последней фазы действует обычная цена подписки (subscriptions.price).
+-- start_date и end_date (включительно) вычисляются сервисом из start_date подписки и длительности фаз.
+CREATE TABLE IF NOT EXISTS subscription_phases (
+  id SERIAL PRIMARY KEY,
+  subscription_id INT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  position INT NOT NULL,
+  kind VARCHAR(16) NOT NULL,
+  price INT NOT NULL CHECK (price >= 0),
+  months INT,
+  days INT,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  UNIQUE (subscription_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS subscription_phases_end_date_idx ON subscription_phases (tenant_id, kind, end_date);
+
+ALTER TABLE subscription_phases ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_phases FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscription_phases_tenant_isolation ON subscription_phases;
+CREATE POLICY subscription_phases_tenant_isolation ON subscription_phases
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
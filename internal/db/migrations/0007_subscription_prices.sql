@@ -0,0 +1,26 @@
+-- История цены подписки: цена действует с effective_from до следующей записи.
+-- subscriptions.price совпадает с ценой последней записи.
+CREATE TABLE IF NOT EXISTS subscription_prices (
+  id SERIAL PRIMARY KEY,
+  subscription_id INT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  effective_from DATE NOT NULL,
+  price INT NOT NULL CHECK (price >= 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (subscription_id, effective_from)
+);
+
+-- Миграции выполняются повторно, поэтому начальная запись создаётся только подпискам без истории цен:
+-- иначе после изменения start_date появилась бы лишняя запись со старой ценой.
+-- Раньше отрицательная цена не проверялась, в истории такие подписки получают цену 0
+INSERT INTO subscription_prices (subscription_id, tenant_id, effective_from, price)
+SELECT s.id, s.tenant_id, s.start_date, GREATEST(s.price, 0) FROM subscriptions s
+WHERE NOT EXISTS (SELECT 1 FROM subscription_prices p WHERE p.subscription_id = s.id)
+ON CONFLICT (subscription_id, effective_from) DO NOTHING;
+
+ALTER TABLE subscription_prices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_prices FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscription_prices_tenant_isolation ON subscription_prices;
+CREATE POLICY subscription_prices_tenant_isolation ON subscription_prices
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
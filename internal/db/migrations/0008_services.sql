@@ -0,0 +1,72 @@
+-- Каталог сервисов тенанта. Название подписки сопоставляется с name или aliases без учёта регистра.
+CREATE TABLE IF NOT EXISTS services (
+  id SERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  name VARCHAR(128) NOT NULL,
+  aliases TEXT[] NOT NULL DEFAULT '{}',
+  category VARCHAR(64),
+  default_price INT CHECK (default_price >= 0),
+  currency CHAR(3) NOT NULL DEFAULT 'RUB',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS services_tenant_name_idx ON services (tenant_id, lower(name));
+
+ALTER TABLE services ENABLE ROW LEVEL SECURITY;
+ALTER TABLE services FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS services_tenant_isolation ON services;
+CREATE POLICY services_tenant_isolation ON services
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS service_id INT REFERENCES services (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS subscriptions_service_id_idx ON subscriptions (service_id);
+
+-- Начальный каталог популярных сервисов. Вызывается и при создании тенанта (POST /tenants)
+CREATE OR REPLACE FUNCTION seed_services(tenant VARCHAR) RETURNS void AS $$
+  INSERT INTO services (tenant_id, name, aliases, category)
+  SELECT tenant, catalog.name, catalog.aliases, catalog.category
+  FROM (VALUES
+    ('Yandex Plus', ARRAY['Яндекс Плюс', 'Yandex+', 'Яндекс+'], 'entertainment'),
+    ('Netflix', ARRAY['Нетфликс'], 'video'),
+    ('Spotify', ARRAY['Спотифай'], 'music'),
+    ('YouTube Premium', ARRAY['YouTube', 'Ютуб Премиум'], 'video'),
+    ('Kinopoisk', ARRAY['Кинопоиск'], 'video'),
+    ('Apple Music', ARRAY['Эпл Мьюзик'], 'music'),
+    ('iCloud', ARRAY['iCloud+', 'Айклауд'], 'cloud'),
+    ('VK Музыка', ARRAY['VK Music', 'ВК Музыка', 'BOOM'], 'music')
+  ) AS catalog (name, aliases, category)
+  ON CONFLICT (tenant_id, lower(name)) DO NOTHING;
+$$ LANGUAGE sql;
+
+-- Существующим тенантам с пустым каталогом. Миграции выполняются повторно, поэтому тенанты с каталогом
+-- пропускаются, чтобы не возвращать удалённые администратором сервисы
+SELECT seed_services(id) FROM tenants
+WHERE NOT EXISTS (SELECT 1 FROM services WHERE services.tenant_id = tenants.id);
+
+-- Привязка существующих подписок к каталогу. Миграция идемпотентна, поэтому после
+-- пополнения каталога её можно запустить повторно (или вызвать POST /services/link).
+UPDATE subscriptions
+SET service_id = services.id, service_name = services.name
+FROM services
+WHERE services.tenant_id = subscriptions.tenant_id
+  AND subscriptions.service_id IS NULL
+  AND (lower(services.name) = lower(subscriptions.service_name)
+    OR EXISTS (SELECT 1 FROM unnest(services.aliases) AS alias WHERE lower(alias) = lower(subscriptions.service_name)));
+
+DO $$
+DECLARE
+  unmatched RECORD;
+BEGIN
+  FOR unmatched IN
+    SELECT tenant_id, service_name, count(*) AS subscriptions
+    FROM subscriptions
+    WHERE service_id IS NULL
+    GROUP BY tenant_id, service_name
+    ORDER BY tenant_id, service_name
+  LOOP
+    RAISE NOTICE 'service not found in catalog: tenant=% service_name=% subscriptions=%',
+      unmatched.tenant_id, unmatched.service_name, unmatched.subscriptions;
+  END LOOP;
+END $$;
@@ -0,0 +1,72 @@
+-- Фиксированная иерархия категорий, общая для всех тенантов
+CREATE TABLE IF NOT EXISTS categories (
+  slug VARCHAR(64) PRIMARY KEY,
+  name VARCHAR(128) NOT NULL,
+  parent_slug VARCHAR(64) REFERENCES categories (slug)
+);
+
+INSERT INTO categories (slug, name, parent_slug) VALUES
+  ('entertainment', 'Развлечения', NULL),
+  ('video', 'Видео и стриминг', 'entertainment'),
+  ('music', 'Музыка', 'entertainment'),
+  ('games', 'Игры', 'entertainment'),
+  ('books', 'Книги и аудиокниги', 'entertainment'),
+  ('cloud', 'Облачные сервисы', NULL),
+  ('storage', 'Облачное хранилище', 'cloud'),
+  ('hosting', 'Хостинг и инфраструктура', 'cloud'),
+  ('software', 'Программы', NULL),
+  ('productivity', 'Офис и продуктивность', 'software'),
+  ('security', 'Безопасность и VPN', 'software'),
+  ('education', 'Образование', NULL),
+  ('news', 'Новости и медиа', NULL),
+  ('other', 'Другое', NULL)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Категории каталога сервисов становятся ссылками на categories
+UPDATE services SET category = 'other'
+WHERE category IS NOT NULL AND category NOT IN (SELECT slug FROM categories);
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'services_category_fkey') THEN
+    ALTER TABLE services ADD CONSTRAINT services_category_fkey FOREIGN KEY (category) REFERENCES categories (slug);
+  END IF;
+END $$;
+
+-- Категория подписки, по умолчанию берётся из каталога сервисов
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS category VARCHAR(64) REFERENCES categories (slug);
+
+UPDATE subscriptions SET category = services.category
+FROM services
+WHERE services.id = subscriptions.service_id AND subscriptions.category IS NULL;
+
+CREATE TABLE IF NOT EXISTS tags (
+  id SERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  name VARCHAR(64) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tags_tenant_name_idx ON tags (tenant_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS subscription_tags (
+  subscription_id INT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  tag_id INT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  PRIMARY KEY (subscription_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS subscription_tags_tag_id_idx ON subscription_tags (tag_id);
+
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tags FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tags_tenant_isolation ON tags;
+CREATE POLICY tags_tenant_isolation ON tags
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE subscription_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_tags FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscription_tags_tenant_isolation ON subscription_tags;
+CREATE POLICY subscription_tags_tenant_isolation ON subscription_tags
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
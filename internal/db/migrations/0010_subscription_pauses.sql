@@ -0,0 +1,20 @@
+-- Паузы подписки: подписка не списывается с paused_from до resumed_at (не включительно).
+-- resumed_at IS NULL - пауза не завершена.
+CREATE TABLE IF NOT EXISTS subscription_pauses (
+  id SERIAL PRIMARY KEY,
+  subscription_id INT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  paused_from DATE NOT NULL,
+  resumed_at DATE CHECK (resumed_at > paused_from),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Не больше одной незавершённой паузы на подписку
+CREATE UNIQUE INDEX IF NOT EXISTS subscription_pauses_open_idx ON subscription_pauses (subscription_id) WHERE resumed_at IS NULL;
+
+ALTER TABLE subscription_pauses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_pauses FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscription_pauses_tenant_isolation ON subscription_pauses;
+CREATE POLICY subscription_pauses_tenant_isolation ON subscription_pauses
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
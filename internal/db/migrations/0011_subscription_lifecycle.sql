@@ -0,0 +1,4 @@
+-- Время отмены подписки. Отменённая подписка действует до end_date, после чего завершается
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+-- end_date до отмены, восстанавливается при возобновлении подписки
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS end_date_before_cancel DATE;
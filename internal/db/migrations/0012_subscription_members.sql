@@ -0,0 +1,21 @@
+-- Правило разделения стоимости между плательщиком (user_id) и участниками подписки: equal, percent или fixed
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS split_rule VARCHAR(16) NOT NULL DEFAULT 'equal';
+
+-- Участники совместной подписки. share - процент стоимости (percent) или сумма в валюте подписки (fixed),
+-- остаток платит плательщик
+CREATE TABLE IF NOT EXISTS subscription_members (
+  subscription_id INT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  user_id UUID NOT NULL,
+  share DOUBLE PRECISION,
+  PRIMARY KEY (subscription_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS subscription_members_user_id_idx ON subscription_members (user_id);
+
+ALTER TABLE subscription_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_members FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS subscription_members_tenant_isolation ON subscription_members;
+CREATE POLICY subscription_members_tenant_isolation ON subscription_members
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
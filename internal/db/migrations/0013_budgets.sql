@@ -0,0 +1,49 @@
+-- Месячный бюджет пользователя на подписки: общий, на категорию (вместе с дочерними) или на сервис каталога
+CREATE TABLE IF NOT EXISTS budgets (
+  id SERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  user_id UUID NOT NULL,
+  category VARCHAR(64) REFERENCES categories (slug),
+  service_id INT REFERENCES services (id) ON DELETE RESTRICT,
+  monthly_limit INT NOT NULL CHECK (monthly_limit >= 0),
+  currency CHAR(3) NOT NULL DEFAULT 'RUB',
+  -- Запрещать изменения подписок, которые превышают бюджет
+  strict BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (category IS NULL OR service_id IS NULL)
+);
+
+-- Сервис с бюджетами нельзя удалить из каталога: бюджет на сервис не должен молча пропадать
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_service_id_fkey;
+ALTER TABLE budgets ADD CONSTRAINT budgets_service_id_fkey FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE RESTRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS budgets_scope_idx ON budgets (tenant_id, user_id, COALESCE(category, ''), COALESCE(service_id, 0));
+
+-- Превышения бюджетов, вызванные изменением подписки
+CREATE TABLE IF NOT EXISTS budget_alerts (
+  id SERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  budget_id INT NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+  user_id UUID NOT NULL,
+  subscription_id INT REFERENCES subscriptions (id) ON DELETE SET NULL,
+  spend DOUBLE PRECISION NOT NULL,
+  monthly_limit INT NOT NULL,
+  currency CHAR(3) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS budget_alerts_user_id_idx ON budget_alerts (user_id, created_at);
+
+ALTER TABLE budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budgets FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS budgets_tenant_isolation ON budgets;
+CREATE POLICY budgets_tenant_isolation ON budgets
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE budget_alerts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budget_alerts FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS budget_alerts_tenant_isolation ON budget_alerts;
+CREATE POLICY budget_alerts_tenant_isolation ON budget_alerts
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
@@ -0,0 +1,47 @@
+-- Настройки уведомлений пользователя
+CREATE TABLE IF NOT EXISTS notification_settings (
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  user_id UUID NOT NULL,
+  -- За сколько дней напоминать о списании, окончании подписки или пробного периода
+  reminder_days INT NOT NULL CHECK (reminder_days >= 0),
+  PRIMARY KEY (tenant_id, user_id)
+);
+
+-- Напоминания к отправке. Уникальность по событию не даёт планировщику создать повторное напоминание,
+-- а отправка повторяется до успеха или исчерпания попыток (at-least-once).
+CREATE TABLE IF NOT EXISTS reminder_jobs (
+  id SERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  subscription_id INT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  user_id UUID NOT NULL,
+  -- renewal, end или trial_end
+  kind VARCHAR(16) NOT NULL,
+  event_date DATE NOT NULL,
+  service_name VARCHAR(64) NOT NULL,
+  amount INT NOT NULL,
+  currency CHAR(3) NOT NULL,
+  -- pending, sent или failed
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (subscription_id, kind, event_date)
+);
+
+CREATE INDEX IF NOT EXISTS reminder_jobs_due_idx ON reminder_jobs (next_attempt_at) WHERE status = 'pending';
+
+ALTER TABLE notification_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notification_settings FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS notification_settings_tenant_isolation ON notification_settings;
+CREATE POLICY notification_settings_tenant_isolation ON notification_settings
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE reminder_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE reminder_jobs FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS reminder_jobs_tenant_isolation ON reminder_jobs;
+CREATE POLICY reminder_jobs_tenant_isolation ON reminder_jobs
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
@@ -0,0 +1,83 @@
+-- Получатели событий подписок. events - типы событий, пустой список - все события
+CREATE TABLE IF NOT EXISTS webhooks (
+  id SERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  events TEXT[] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Outbox: события записываются в той же транзакции, что и изменение подписки
+CREATE TABLE IF NOT EXISTS webhook_events (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  event_type VARCHAR(64) NOT NULL,
+  subscription_id INT NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Доставка события одному получателю. status: pending, delivered или failed
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  webhook_id INT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+  event_id BIGINT NOT NULL REFERENCES webhook_events (id) ON DELETE CASCADE,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_status_code INT,
+  last_error TEXT,
+  delivered_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- Для очистки старых событий вместе с их доставками
+CREATE INDEX IF NOT EXISTS webhook_events_created_at_idx ON webhook_events (created_at);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_event_id_idx ON webhook_deliveries (event_id);
+
+-- Каждая попытка доставки
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  delivery_id BIGINT NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+  status_code INT,
+  error TEXT,
+  duration_ms INT NOT NULL,
+  attempted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_attempts_delivery_id_idx ON webhook_attempts (delivery_id);
+
+ALTER TABLE webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhooks FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS webhooks_tenant_isolation ON webhooks;
+CREATE POLICY webhooks_tenant_isolation ON webhooks
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE webhook_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_events FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS webhook_events_tenant_isolation ON webhook_events;
+CREATE POLICY webhook_events_tenant_isolation ON webhook_events
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS webhook_deliveries_tenant_isolation ON webhook_deliveries;
+CREATE POLICY webhook_deliveries_tenant_isolation ON webhook_deliveries
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE webhook_attempts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_attempts FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS webhook_attempts_tenant_isolation ON webhook_attempts;
+CREATE POLICY webhook_attempts_tenant_isolation ON webhook_attempts
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
@@ -0,0 +1,11 @@
+-- Email-уведомления: адрес, язык писем и какие письма получать
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS email VARCHAR(254);
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS locale VARCHAR(2) NOT NULL DEFAULT 'ru';
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS email_reminders BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS email_budget_alerts BOOLEAN NOT NULL DEFAULT true;
+
+-- Превышение бюджета отправляется через ту же очередь, что и напоминания (kind = budget_exceeded).
+-- У совместной подписки бюджет может превысить каждый участник, поэтому пользователь входит в уникальность.
+ALTER TABLE reminder_jobs ADD COLUMN IF NOT EXISTS monthly_limit INT;
+ALTER TABLE reminder_jobs DROP CONSTRAINT IF EXISTS reminder_jobs_subscription_id_kind_event_date_key;
+CREATE UNIQUE INDEX IF NOT EXISTS reminder_jobs_event_idx ON reminder_jobs (subscription_id, user_id, kind, event_date);
@@ -0,0 +1,14 @@
+-- Выгрузка подписок читается из курсора партиями. FETCH нельзя использовать как подзапрос,
+-- поэтому партия возвращается функцией с типом строки subscriptions.
+CREATE OR REPLACE FUNCTION fetch_subscriptions(cursor_name refcursor, batch_size INT)
+RETURNS SETOF subscriptions AS $$
+DECLARE
+    item subscriptions;
+BEGIN
+    FOR i IN 1..batch_size LOOP
+        FETCH cursor_name INTO item;
+        EXIT WHEN NOT FOUND;
+        RETURN NEXT item;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,16 @@
+-- Секретные токены календарной подписки пользователя. Хранится только SHA-256 токена,
+-- сам токен показывается один раз при создании
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+  tenant_id VARCHAR(64) NOT NULL REFERENCES tenants (id),
+  user_id UUID NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (tenant_id, user_id)
+);
+
+ALTER TABLE calendar_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE calendar_tokens FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS calendar_tokens_tenant_isolation ON calendar_tokens;
+CREATE POLICY calendar_tokens_tenant_isolation ON calendar_tokens
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
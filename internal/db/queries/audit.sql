@@ -0,0 +1,21 @@
+-- name: CreateAuditRecord :exec
+INSERT INTO subscription_audit (tenant_id, subscription_id, actor, request_id, action, before, after) VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: SubscriptionHistory :many
+SELECT * FROM subscription_audit WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY id;
+
+-- name: AuditList :many
+SELECT * FROM subscription_audit
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CreateAuditRecords :exec
+-- Записи аудита одного действия по нескольким подпискам, afters - JSON подписок после действия
+INSERT INTO subscription_audit (tenant_id, subscription_id, actor, request_id, action, after)
+SELECT @tenant_id::varchar, (@subscription_ids::int[])[i], @actor::varchar, @request_id::varchar, @action::varchar, (@afters::text[])[i]::jsonb
+FROM generate_series(1, cardinality(@subscription_ids::int[])) AS i;
@@ -0,0 +1,16 @@
+-- name: UserBudgets :many
+SELECT * FROM budgets WHERE tenant_id = $1 AND user_id = $2 ORDER BY id;
+
+-- name: CreateBudget :one
+INSERT INTO budgets (tenant_id, user_id, category, service_id, monthly_limit, currency, strict) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateBudget :one
+UPDATE budgets SET category = $4, service_id = $5, monthly_limit = $6, currency = $7, strict = $8
+WHERE id = $1 AND tenant_id = $2 AND user_id = $3
+RETURNING *;
+
+-- name: DeleteBudget :execrows
+DELETE FROM budgets WHERE id = $1 AND tenant_id = $2 AND user_id = $3;
+
+-- name: CreateBudgetAlert :one
+INSERT INTO budget_alerts (tenant_id, budget_id, user_id, subscription_id, spend, monthly_limit, currency) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
@@ -0,0 +1,10 @@
+-- name: SaveCalendarToken :one
+INSERT INTO calendar_tokens (tenant_id, user_id, token_hash) VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()
+RETURNING *;
+
+-- name: DeleteCalendarToken :execrows
+DELETE FROM calendar_tokens WHERE tenant_id = $1 AND user_id = $2;
+
+-- name: CalendarTokenByHash :one
+SELECT * FROM calendar_tokens WHERE token_hash = $1;
@@ -0,0 +1,5 @@
+-- name: CategoriesList :many
+SELECT * FROM categories ORDER BY slug;
+
+-- name: GetCategory :one
+SELECT * FROM categories WHERE slug = $1;
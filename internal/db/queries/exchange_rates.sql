@@ -0,0 +1,9 @@
+-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (currency, rate_date, rate, source) VALUES ($1, $2, $3, $4)
+ON CONFLICT (currency, rate_date) DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source;
+
+-- name: ExchangeRatesList :many
+SELECT * FROM exchange_rates WHERE ($1 = '' OR currency = $1::text) ORDER BY currency, rate_date;
+
+-- name: ExchangeRatesForCurrencies :many
+SELECT * FROM exchange_rates WHERE currency = ANY(@currencies::text[]) ORDER BY currency, rate_date;
@@ -0,0 +1,20 @@
+-- Курсор живёт до конца транзакции, партии читаются FetchSubscriptionsExport
+
+-- name: DeclareSubscriptionsExport :exec
+DECLARE subscriptions_export NO SCROLL CURSOR FOR
+SELECT * FROM subscriptions
+WHERE subscriptions.tenant_id = @tenant_id
+  AND (deleted_at IS NULL OR @include_deleted::bool)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(participant_id)::uuid IS NULL OR user_id = sqlc.narg(participant_id) OR id IN (
+    SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = sqlc.narg(participant_id)
+  ))
+  AND (cardinality(@categories::text[]) = 0 OR category = ANY(@categories::text[]))
+  AND (cardinality(@tags::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY(@tags::text[])
+  ) = cardinality(@tags::text[]))
+ORDER BY id;
+
+-- name: FetchSubscriptionsExport :many
+SELECT * FROM fetch_subscriptions('subscriptions_export', @batch_size::int);
@@ -0,0 +1,37 @@
+-- Точки сохранения позволяют отменить одну строку импорта, не прерывая транзакцию
+
+-- name: SavepointImportRow :exec
+SAVEPOINT import_row;
+
+-- name: ReleaseImportRow :exec
+RELEASE SAVEPOINT import_row;
+
+-- name: RollbackImportRow :exec
+ROLLBACK TO SAVEPOINT import_row;
+
+-- name: ImportSubscriptions :many
+-- Добавляет подписки импорта одной командой: i-й элемент каждого массива - поле i-й подписки.
+-- Пустые строки и нули - NULL, id выдаются в порядке подписок
+INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date, tenant_id, currency, billing_period, billing_months, anchor_date, service_id, category, split_rule)
+SELECT
+  (@service_names::varchar[])[i],
+  (@prices::int[])[i],
+  (@user_ids::text[])[i]::uuid,
+  (@start_dates::text[])[i]::date,
+  NULLIF((@end_dates::text[])[i], '')::date,
+  @tenant_id::varchar,
+  (@currencies::text[])[i],
+  (@billing_periods::text[])[i],
+  NULLIF((@billing_months::int[])[i], 0),
+  NULLIF((@anchor_dates::text[])[i], '')::date,
+  NULLIF((@service_ids::int[])[i], 0),
+  NULLIF((@categories::text[])[i], ''),
+  (@split_rules::text[])[i]
+FROM generate_series(1, cardinality(@prices::int[])) AS i
+ORDER BY i
+RETURNING *;
+
+-- name: SaveImportedPrices :exec
+INSERT INTO subscription_prices (subscription_id, tenant_id, effective_from, price)
+SELECT id, tenant_id, start_date, price FROM subscriptions WHERE id = ANY(@subscription_ids::int[])
+ON CONFLICT (subscription_id, effective_from) DO NOTHING;
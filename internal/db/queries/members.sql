@@ -0,0 +1,8 @@
+-- name: AddSubscriptionMember :exec
+INSERT INTO subscription_members (subscription_id, tenant_id, user_id, share) VALUES ($1, $2, $3, $4);
+
+-- name: DeleteSubscriptionMembers :exec
+DELETE FROM subscription_members WHERE subscription_id = $1 AND tenant_id = $2;
+
+-- name: MembersForSubscriptions :many
+SELECT * FROM subscription_members WHERE subscription_id = ANY(@subscription_ids::int[]) ORDER BY subscription_id, user_id;
@@ -0,0 +1,14 @@
+-- name: CreatePause :one
+INSERT INTO subscription_pauses (subscription_id, tenant_id, paused_from, resumed_at) VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: SubscriptionPauses :many
+SELECT * FROM subscription_pauses WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY paused_from;
+
+-- name: ResumePause :one
+UPDATE subscription_pauses SET resumed_at = $3 WHERE id = $1 AND tenant_id = $2 RETURNING *;
+
+-- name: PausesForSubscriptions :many
+SELECT * FROM subscription_pauses WHERE subscription_id = ANY(@subscription_ids::int[]) ORDER BY subscription_id, paused_from;
+
+-- name: DeletePause :exec
+DELETE FROM subscription_pauses WHERE id = $1 AND tenant_id = $2;
@@ -0,0 +1,22 @@
+-- name: CreatePhase :exec
+INSERT INTO subscription_phases (subscription_id, tenant_id, position, kind, price, months, days, start_date, end_date) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: DeletePhases :exec
+DELETE FROM subscription_phases WHERE subscription_id = $1 AND tenant_id = $2;
+
+-- name: SubscriptionPhases :many
+SELECT * FROM subscription_phases WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY position;
+
+-- name: PhasesForSubscriptions :many
+SELECT * FROM subscription_phases WHERE subscription_id = ANY(@subscription_ids::int[]) ORDER BY subscription_id, position;
+
+-- name: TrialsEnding :many
+SELECT sqlc.embed(subscriptions), subscription_phases.end_date AS trial_end_date
+FROM subscription_phases
+JOIN subscriptions ON subscriptions.id = subscription_phases.subscription_id
+WHERE subscription_phases.tenant_id = @tenant_id
+  AND subscriptions.deleted_at IS NULL
+  AND subscription_phases.kind = 'trial'
+  AND subscription_phases.end_date BETWEEN @from_date::date AND @to_date::date
+  AND (sqlc.narg(user_id)::uuid IS NULL OR subscriptions.user_id = sqlc.narg(user_id))
+ORDER BY subscription_phases.end_date, subscriptions.id;
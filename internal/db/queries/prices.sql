@@ -0,0 +1,23 @@
+-- name: SavePrice :exec
+INSERT INTO subscription_prices (subscription_id, tenant_id, effective_from, price) VALUES ($1, $2, $3, $4)
+ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = now();
+
+-- name: DeletePricesAfter :exec
+DELETE FROM subscription_prices WHERE subscription_id = $1 AND tenant_id = $2 AND effective_from > $3;
+
+-- name: SubscriptionPrices :many
+SELECT * FROM subscription_prices WHERE subscription_id = $1 AND tenant_id = $2 ORDER BY effective_from;
+
+-- name: PricesForSubscriptions :many
+SELECT * FROM subscription_prices WHERE subscription_id = ANY(@subscription_ids::int[]) ORDER BY subscription_id, effective_from;
+
+-- name: SetSubscriptionPrice :exec
+UPDATE subscriptions SET price = $3 WHERE id = $1 AND tenant_id = $2;
+
+-- name: ActiveServiceSubscriptions :many
+SELECT * FROM subscriptions
+WHERE tenant_id = @tenant_id
+  AND lower(service_name) = lower(@service_name)
+  AND deleted_at IS NULL
+  AND (end_date IS NULL OR end_date >= @effective_from::date)
+ORDER BY id;
@@ -0,0 +1,44 @@
+-- name: GetNotificationSettings :one
+SELECT * FROM notification_settings WHERE tenant_id = $1 AND user_id = $2;
+
+-- name: SaveNotificationSettings :one
+INSERT INTO notification_settings (tenant_id, user_id, reminder_days, email, locale, email_reminders, email_budget_alerts)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+  reminder_days = EXCLUDED.reminder_days,
+  email = EXCLUDED.email,
+  locale = EXCLUDED.locale,
+  email_reminders = EXCLUDED.email_reminders,
+  email_budget_alerts = EXCLUDED.email_budget_alerts
+RETURNING *;
+
+-- name: AllNotificationSettings :many
+SELECT * FROM notification_settings;
+
+-- name: EnqueueReminder :exec
+INSERT INTO reminder_jobs (tenant_id, subscription_id, user_id, kind, event_date, service_name, amount, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (subscription_id, user_id, kind, event_date) DO NOTHING;
+
+-- name: EnqueueBudgetAlert :exec
+INSERT INTO reminder_jobs (tenant_id, subscription_id, user_id, kind, event_date, service_name, amount, currency, monthly_limit)
+VALUES ($1, $2, $3, 'budget_exceeded', $4, $5, $6, $7, $8)
+ON CONFLICT (subscription_id, user_id, kind, event_date) DO NOTHING;
+
+-- name: ClaimReminders :many
+UPDATE reminder_jobs
+SET attempts = attempts + 1, next_attempt_at = @lease_until
+WHERE id IN (
+  SELECT id FROM reminder_jobs
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT @batch::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkReminderSent :exec
+UPDATE reminder_jobs SET status = 'sent', sent_at = now(), last_error = NULL WHERE id = $1;
+
+-- name: MarkReminderFailed :exec
+UPDATE reminder_jobs SET status = @status, last_error = @last_error, next_attempt_at = @next_attempt_at WHERE id = @id;
@@ -0,0 +1,42 @@
+-- name: ServicesList :many
+SELECT * FROM services WHERE tenant_id = $1 ORDER BY name;
+
+-- name: GetService :one
+SELECT * FROM services WHERE id = $1 AND tenant_id = $2;
+
+-- name: CreateService :one
+INSERT INTO services (tenant_id, name, aliases, category, default_price, currency) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: UpdateService :one
+UPDATE services SET name = $3, aliases = $4, category = $5, default_price = $6, currency = $7 WHERE id = $1 AND tenant_id = $2 RETURNING *;
+
+-- name: DeleteService :execrows
+DELETE FROM services WHERE id = $1 AND tenant_id = $2;
+
+-- name: ResolveService :one
+SELECT * FROM services
+WHERE tenant_id = @tenant_id
+  AND (lower(name) = lower(@name::text)
+    OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE lower(alias) = lower(@name::text)))
+ORDER BY lower(name) = lower(@name::text) DESC, id
+LIMIT 1;
+
+-- name: RenameServiceSubscriptions :exec
+UPDATE subscriptions SET service_name = $3 WHERE service_id = $1 AND tenant_id = $2;
+
+-- name: LinkServices :execrows
+UPDATE subscriptions
+SET service_id = services.id, service_name = services.name
+FROM services
+WHERE subscriptions.tenant_id = @tenant_id
+  AND services.tenant_id = subscriptions.tenant_id
+  AND subscriptions.service_id IS NULL
+  AND (lower(services.name) = lower(subscriptions.service_name)
+    OR EXISTS (SELECT 1 FROM unnest(services.aliases) AS alias WHERE lower(alias) = lower(subscriptions.service_name)));
+
+-- name: UnmatchedServiceNames :many
+SELECT service_name, count(*) AS subscriptions
+FROM subscriptions
+WHERE tenant_id = $1 AND service_id IS NULL AND deleted_at IS NULL
+GROUP BY service_name
+ORDER BY service_name;
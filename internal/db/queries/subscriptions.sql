@@ -1,20 +1,79 @@
 -- name: SubscriptionsList :many
-SELECT * FROM subscriptions;
+SELECT * FROM subscriptions
+WHERE subscriptions.tenant_id = @tenant_id
+  AND (deleted_at IS NULL OR @include_deleted::bool)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id))
+  AND (cardinality(@categories::text[]) = 0 OR category = ANY(@categories::text[]))
+  AND (cardinality(@tags::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY(@tags::text[])
+  ) = cardinality(@tags::text[]));
 
--- name: CreateSubscription :exec
-INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date) VALUES ($1, $2, $3, $4, $5);
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date, tenant_id, currency, billing_period, billing_months, anchor_date, service_id, category, split_rule) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UserSubscriptions :many
-SELECT * FROM subscriptions WHERE user_id = $1;
+SELECT * FROM subscriptions
+WHERE (subscriptions.user_id = $1 OR id IN (SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = $1))
+  AND subscriptions.tenant_id = $2 AND deleted_at IS NULL;
 
 -- name: GetSubscription :one
-SELECT * FROM subscriptions WHERE id = $1;
+SELECT * FROM subscriptions WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL;
 
 -- name: DeleteSubscription :exec
-DELETE FROM subscriptions WHERE id = $1;
+UPDATE subscriptions SET deleted_at = now() WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL;
 
--- name: UpdateSubscription :exec
-UPDATE subscriptions SET service_name = $2, price = $3, user_id = $4, start_date = $5, end_date = $6 WHERE id = $1;
+-- name: RestoreSubscription :one
+UPDATE subscriptions SET deleted_at = NULL WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: PurgeDeletedSubscriptions :execrows
+DELETE FROM subscriptions WHERE deleted_at < $1;
+
+-- name: UpdateSubscription :one
+UPDATE subscriptions SET service_name = $2, price = $3, user_id = $4, start_date = $5, end_date = $6, currency = $8, billing_period = $9, billing_months = $10, anchor_date = $11, service_id = $12, category = $13, split_rule = $14 WHERE id = $1 AND tenant_id = $7 AND deleted_at IS NULL RETURNING *;
 
 -- name: GetSubscriptionsWithFilter :many
-SELECT price FROM subscriptions WHERE ($1 = '' OR user_id = $1::uuid) AND ($2 = '' OR service_name = $2::text) AND ($3 = '' OR start_date >= TO_DATE($3, 'MM-YYYY')) AND ($4 = '' OR end_date <= TO_DATE($4, 'MM-YYYY'));
+SELECT * FROM subscriptions
+WHERE subscriptions.tenant_id = @tenant_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg(user_id)::uuid IS NULL OR subscriptions.user_id = sqlc.narg(user_id) OR id IN (
+    SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = sqlc.narg(user_id)
+  ))
+  AND (@service_name::text = '' OR lower(service_name) = lower(@service_name::text))
+  AND (sqlc.narg(from_date)::date IS NULL OR end_date IS NULL OR end_date >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR start_date <= sqlc.narg(to_date))
+  AND (cardinality(@categories::text[]) = 0 OR category = ANY(@categories::text[]))
+  AND (cardinality(@tags::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY(@tags::text[])
+  ) = cardinality(@tags::text[]));
+
+-- name: GetAllTenantsSubscriptionsWithFilter :many
+SELECT * FROM subscriptions
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(user_id)::uuid IS NULL OR subscriptions.user_id = sqlc.narg(user_id) OR id IN (
+    SELECT subscription_id FROM subscription_members WHERE subscription_members.user_id = sqlc.narg(user_id)
+  ))
+  AND (@service_name::text = '' OR lower(service_name) = lower(@service_name::text))
+  AND (sqlc.narg(from_date)::date IS NULL OR end_date IS NULL OR end_date >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR start_date <= sqlc.narg(to_date))
+  AND (cardinality(@categories::text[]) = 0 OR category = ANY(@categories::text[]))
+  AND (cardinality(@tags::text[]) = 0 OR (
+    SELECT count(*) FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+    WHERE subscription_tags.subscription_id = subscriptions.id AND lower(tags.name) = ANY(@tags::text[])
+  ) = cardinality(@tags::text[]))
+ORDER BY subscriptions.tenant_id;
+
+-- name: CancelSubscription :one
+UPDATE subscriptions
+SET cancelled_at = COALESCE(cancelled_at, now()),
+    end_date_before_cancel = CASE WHEN cancelled_at IS NULL THEN end_date ELSE end_date_before_cancel END,
+    end_date = @end_date
+WHERE id = @id AND tenant_id = @tenant_id AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ReactivateSubscription :one
+UPDATE subscriptions
+SET cancelled_at = NULL, end_date = end_date_before_cancel, end_date_before_cancel = NULL
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,44 @@
+-- name: UpsertTag :one
+INSERT INTO tags (tenant_id, name) VALUES ($1, $2)
+ON CONFLICT (tenant_id, lower(name)) DO UPDATE SET name = tags.name
+RETURNING *;
+
+-- name: GetTagByName :one
+SELECT * FROM tags WHERE tenant_id = $1 AND lower(name) = lower(@name::text);
+
+-- name: AddSubscriptionTag :exec
+INSERT INTO subscription_tags (subscription_id, tag_id, tenant_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;
+
+-- name: DeleteSubscriptionTags :exec
+DELETE FROM subscription_tags WHERE subscription_id = $1 AND tenant_id = $2;
+
+-- name: TagsForSubscriptions :many
+SELECT subscription_tags.subscription_id, tags.name
+FROM subscription_tags JOIN tags ON tags.id = subscription_tags.tag_id
+WHERE subscription_tags.subscription_id = ANY(@subscription_ids::int[])
+ORDER BY subscription_tags.subscription_id, lower(tags.name);
+
+-- name: TagsList :many
+SELECT tags.id, tags.name, count(subscription_tags.subscription_id) AS subscriptions
+FROM tags LEFT JOIN subscription_tags ON subscription_tags.tag_id = tags.id
+WHERE tags.tenant_id = $1
+GROUP BY tags.id, tags.name
+ORDER BY lower(tags.name);
+
+-- name: RenameTag :one
+UPDATE tags SET name = @new_name WHERE id = $1 AND tenant_id = $2 RETURNING *;
+
+-- name: MoveSubscriptionTags :exec
+INSERT INTO subscription_tags (subscription_id, tag_id, tenant_id)
+SELECT subscription_id, @target_id, tenant_id FROM subscription_tags WHERE subscription_tags.tag_id = @source_id AND subscription_tags.tenant_id = @tenant_id
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteTag :exec
+DELETE FROM tags WHERE id = $1 AND tenant_id = $2;
+
+-- name: AddSubscriptionTags :exec
+-- i-й тег tag_ids добавляется i-й подписке subscription_ids
+INSERT INTO subscription_tags (subscription_id, tag_id, tenant_id)
+SELECT (@subscription_ids::int[])[i], (@tag_ids::int[])[i], @tenant_id::varchar
+FROM generate_series(1, cardinality(@subscription_ids::int[])) AS i
+ON CONFLICT DO NOTHING;
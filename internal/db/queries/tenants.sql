@@ -0,0 +1,11 @@
+-- name: CreateTenant :one
+INSERT INTO tenants (id, name) VALUES ($1, $2) RETURNING *;
+
+-- name: TenantsList :many
+SELECT * FROM tenants ORDER BY id;
+
+-- name: GetTenant :one
+SELECT * FROM tenants WHERE id = $1;
+
+-- name: SeedServices :exec
+SELECT seed_services(@tenant_id::varchar);
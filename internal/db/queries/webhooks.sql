@@ -0,0 +1,109 @@
+-- name: WebhooksList :many
+SELECT * FROM webhooks WHERE tenant_id = $1 ORDER BY id;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks WHERE id = $1 AND tenant_id = $2;
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (tenant_id, url, secret, events, active) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks SET url = $3, events = $4, active = $5 WHERE id = $1 AND tenant_id = $2 RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2;
+
+-- name: CreateWebhookEvent :one
+INSERT INTO webhook_events (tenant_id, event_type, subscription_id, payload) VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: NotifyWebhookEvent :exec
+SELECT pg_notify('subscription_events', @payload::text);
+
+-- name: CreateWebhookEvents :many
+-- События одного типа по нескольким подпискам, payloads - JSON подписок. id выдаются в порядке подписок
+INSERT INTO webhook_events (tenant_id, event_type, subscription_id, payload)
+SELECT @tenant_id::varchar, @event_type::varchar, (@subscription_ids::int[])[i], (@payloads::text[])[i]::jsonb
+FROM generate_series(1, cardinality(@subscription_ids::int[])) AS i
+ORDER BY i
+RETURNING id;
+
+-- name: NotifyWebhookEvents :exec
+SELECT pg_notify('subscription_events', id::text) FROM unnest(@event_ids::bigint[]) AS id;
+
+-- name: CreateWebhookDeliveries :exec
+INSERT INTO webhook_deliveries (tenant_id, webhook_id, event_id)
+SELECT webhooks.tenant_id, webhooks.id, @event_id::bigint FROM webhooks
+WHERE webhooks.tenant_id = @tenant_id AND active
+  AND (cardinality(events) = 0 OR @event_type::text = ANY(events));
+
+-- name: CreateWebhookDeliveriesForEvents :exec
+INSERT INTO webhook_deliveries (tenant_id, webhook_id, event_id)
+SELECT webhooks.tenant_id, webhooks.id, event_id FROM webhooks CROSS JOIN unnest(@event_ids::bigint[]) AS event_id
+WHERE webhooks.tenant_id = @tenant_id AND active
+  AND (cardinality(events) = 0 OR @event_type::text = ANY(events));
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, next_attempt_at = @lease_until
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= now()
+    AND webhook_id IN (SELECT webhooks.id FROM webhooks WHERE active)
+  ORDER BY next_attempt_at
+  LIMIT @batch::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: DeliveryTargets :many
+SELECT webhook_deliveries.id, webhooks.url, webhooks.secret, webhook_events.id AS event_id, webhook_events.event_type, webhook_events.subscription_id, webhook_events.payload, webhook_events.created_at
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+JOIN webhook_events ON webhook_events.id = webhook_deliveries.event_id
+WHERE webhook_deliveries.id = ANY(@delivery_ids::bigint[]);
+
+-- name: CreateWebhookAttempt :exec
+INSERT INTO webhook_attempts (tenant_id, delivery_id, status_code, error, duration_ms) VALUES ($1, $2, $3, $4, $5);
+
+-- name: MarkDeliveryDelivered :exec
+UPDATE webhook_deliveries SET status = 'delivered', delivered_at = now(), last_status_code = $2, last_error = NULL WHERE id = $1;
+
+-- name: MarkDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET status = @status, last_status_code = @last_status_code, last_error = @last_error, next_attempt_at = @next_attempt_at
+WHERE id = @id;
+
+-- name: WebhookDeliveries :many
+SELECT webhook_deliveries.*, webhook_events.event_type
+FROM webhook_deliveries JOIN webhook_events ON webhook_events.id = webhook_deliveries.event_id
+WHERE webhook_deliveries.webhook_id = @webhook_id AND webhook_deliveries.tenant_id = @tenant_id
+  AND (@status::text = '' OR webhook_deliveries.status = @status::text)
+ORDER BY webhook_deliveries.id DESC
+LIMIT 100;
+
+-- name: GetWebhookDelivery :one
+SELECT webhook_deliveries.*, webhook_events.event_type
+FROM webhook_deliveries JOIN webhook_events ON webhook_events.id = webhook_deliveries.event_id
+WHERE webhook_deliveries.id = @id AND webhook_deliveries.webhook_id = @webhook_id AND webhook_deliveries.tenant_id = @tenant_id;
+
+-- name: WebhookAttempts :many
+SELECT * FROM webhook_attempts WHERE delivery_id = $1 AND tenant_id = $2 ORDER BY id;
+
+-- name: ReplayWebhookDelivery :execrows
+UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = now()
+WHERE id = @id AND webhook_id = @webhook_id AND tenant_id = @tenant_id AND status <> 'pending';
+
+-- name: GetWebhookEvent :one
+SELECT * FROM webhook_events WHERE id = $1;
+
+-- name: WebhookEventsAfter :many
+SELECT * FROM webhook_events WHERE id > @after_id ORDER BY id LIMIT @max_events::int;
+
+-- name: PurgeWebhookEvents :execrows
+-- Удаляет события старше older_than вместе с доставками и попытками, кроме событий с неотправленными доставками
+DELETE FROM webhook_events
+WHERE webhook_events.created_at < @older_than
+  AND NOT EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.event_id = webhook_events.id AND webhook_deliveries.status = 'pending');
+
+-- name: LatestWebhookEvents :many
+SELECT * FROM webhook_events ORDER BY id DESC LIMIT @max_events::int;
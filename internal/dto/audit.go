@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+type AuditRecord struct {
+	ID             int64           `json:"id" example:"1"`
+	SubscriptionID int32           `json:"subscription_id" example:"1"`
+	Actor          string          `json:"actor" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	RequestID      string          `json:"request_id" example:"host/abcdef-000001"`
+	Action         string          `json:"action" example:"update"`
+	Before         json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After          json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func AuditFromSql(recordSql db.SubscriptionAudit) AuditRecord {
+	record := AuditRecord{
+		ID:             recordSql.ID,
+		SubscriptionID: recordSql.SubscriptionID,
+		Actor:          recordSql.Actor,
+		RequestID:      recordSql.RequestID,
+		Action:         recordSql.Action,
+		CreatedAt:      recordSql.CreatedAt,
+	}
+
+	if recordSql.Before.Valid {
+		record.Before = recordSql.Before.RawMessage
+	}
+	if recordSql.After.Valid {
+		record.After = recordSql.After.RawMessage
+	}
+
+	return record
+}
+
+type AuditFilter struct {
+	Actor  string
+	Action string
+	From   *time.Time
+	To     *time.Time
+	Limit  int32
+	Offset int32
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// Budget - месячный лимит расходов пользователя на подписки. Без category и service_id
+// ограничивает все подписки пользователя.
+type Budget struct {
+	ID     int32  `json:"id" example:"1" readonly:"true"`
+	UserID string `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba" readonly:"true"`
+	// Slug категории из /categories, учитываются и дочерние категории
+	Category *string `json:"category,omitempty" example:"entertainment"`
+	// Сервис из каталога
+	ServiceID    *int32 `json:"service_id,omitempty" example:"1"`
+	MonthlyLimit int    `json:"monthly_limit" example:"1500"`
+	Currency     string `json:"currency" example:"RUB"`
+	// Запрещать создание и изменение подписок, которые превышают бюджет
+	Strict bool `json:"strict" example:"false"`
+}
+
+func BudgetFromSql(budgetSql db.Budget) Budget {
+	budget := Budget{
+		ID:           budgetSql.ID,
+		UserID:       budgetSql.UserID.String(),
+		MonthlyLimit: int(budgetSql.MonthlyLimit),
+		Currency:     budgetSql.Currency,
+		Strict:       budgetSql.Strict,
+	}
+
+	if budgetSql.Category.Valid {
+		budget.Category = &budgetSql.Category.String
+	}
+	if budgetSql.ServiceID.Valid {
+		budget.ServiceID = &budgetSql.ServiceID.Int32
+	}
+
+	return budget
+}
+
+// BudgetStatus - прогноз расходов в месяц по действующим подпискам в сравнении с бюджетом
+type BudgetStatus struct {
+	Budget
+	// Стоимость в месяц действующих подписок пользователя (его доли в совместных) в валюте бюджета
+	Spend     float64 `json:"spend" example:"1299.5"`
+	Remaining float64 `json:"remaining" example:"200.5"`
+	Exceeded  bool    `json:"exceeded" example:"false"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// CalendarToken - секретный токен календарной подписки. Показывается только при создании
+type CalendarToken struct {
+	Token string `json:"token" example:"cal_3f9a0c2e7b1d4e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d"`
+	// Путь календаря с токеном, который добавляется в календарное приложение
+	URL       string    `json:"url" example:"/users/60601fee-2bf1-4721-ae6f-7636e79a0cba/calendar.ics?token=cal_3f9a0c2e7b1d4e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d"`
+	CreatedAt time.Time `json:"created_at"`
+}
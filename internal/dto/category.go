@@ -0,0 +1,22 @@
+package dto
+
+import (
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+type Category struct {
+	Slug       string  `json:"slug" example:"video"`
+	Name       string  `json:"name" example:"Видео и стриминг"`
+	ParentSlug *string `json:"parent_slug,omitempty" example:"entertainment"`
+}
+
+func CategoryFromSql(categorySql db.Category) Category {
+	category := Category{
+		Slug: categorySql.Slug,
+		Name: categorySql.Name,
+	}
+	if categorySql.ParentSlug.Valid {
+		category.ParentSlug = &categorySql.ParentSlug.String
+	}
+	return category
+}
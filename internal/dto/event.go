@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// Event - событие подписки, которое отправляется вебхукам и в поток /subscriptions/events
+type Event struct {
+	ID             int64     `json:"id" example:"1"`
+	Type           string    `json:"type" example:"subscription.created"`
+	SubscriptionID int32     `json:"subscription_id" example:"1"`
+	CreatedAt      time.Time `json:"created_at"`
+	// Подписка после изменения, для удаления - до него
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+func EventFromSql(eventSql db.WebhookEvent) Event {
+	return Event{
+		ID:             eventSql.ID,
+		Type:           eventSql.EventType,
+		SubscriptionID: eventSql.SubscriptionID,
+		CreatedAt:      eventSql.CreatedAt,
+		Data:           eventSql.Payload,
+	}
+}
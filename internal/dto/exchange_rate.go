@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"strconv"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+const DATE_FORMAT = "2006-01-02"
+
+// ExchangeRate - стоимость одной единицы валюты в рублях на дату
+type ExchangeRate struct {
+	Currency string  `json:"currency" example:"USD"`
+	Date     string  `json:"date" example:"2025-07-01"`
+	Rate     float64 `json:"rate" example:"78.5"`
+	Source   string  `json:"source" example:"cbr.ru"`
+}
+
+func ExchangeRateFromSql(rateSql db.ExchangeRate) (ExchangeRate, error) {
+	rate, err := strconv.ParseFloat(rateSql.Rate, 64)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+
+	return ExchangeRate{
+		Currency: rateSql.Currency,
+		Date:     rateSql.RateDate.Format(DATE_FORMAT),
+		Rate:     rate,
+		Source:   rateSql.Source,
+	}, nil
+}
+
+type SumResult struct {
+	Sum      float64        `json:"sum" example:"1234.5"`
+	Currency string         `json:"currency" example:"USD"`
+	Rates    []ExchangeRate `json:"rates"`
+}
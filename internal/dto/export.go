@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Колонки выгрузки подписок в CSV и XLSX. Названия совпадают с полями импорта,
+// поэтому выгрузку в CSV можно загрузить обратно через POST /subscriptions/import
+var SubscriptionColumns = []string{
+	"id",
+	"service_name",
+	"service_id",
+	"price",
+	"currency",
+	"monthly_equivalent",
+	"user_id",
+	"start_date",
+	"end_date",
+	"status",
+	"category",
+	"tags",
+	"billing_period",
+	"billing_months",
+	"anchor_date",
+	"split_rule",
+	"members",
+	"cancelled_at",
+	"deleted_at",
+}
+
+// ExportValues возвращает значения колонок SubscriptionColumns
+func (s Subscription) ExportValues() []any {
+	var members []string
+	for _, member := range s.Members {
+		if member.Share != nil {
+			members = append(members, member.UserID+":"+strconv.FormatFloat(*member.Share, 'f', -1, 64))
+		} else {
+			members = append(members, member.UserID)
+		}
+	}
+
+	return []any{
+		s.ID,
+		s.ServiceName,
+		optional(s.ServiceID),
+		s.Price,
+		s.Currency,
+		s.MonthlyEquivalent,
+		s.UserID,
+		exportDate(&s.StartDateISO),
+		exportDate(s.EndDateISO),
+		s.Status,
+		optional(s.Category),
+		strings.Join(s.Tags, ","),
+		s.BillingPeriod,
+		optional(s.BillingMonths),
+		exportDate(s.AnchorDate),
+		s.SplitRule,
+		strings.Join(members, ","),
+		optional(s.CancelledAt),
+		optional(s.DeletedAt),
+	}
+}
+
+// Колонки выгрузки отчёта
+var ReportColumns = []string{"key", "name", "sum", "currency"}
+
+// ExportValues возвращает значения колонок ReportColumns для строки отчёта
+func (r Report) ExportValues(row ReportRow) []any {
+	return []any{row.Key, row.Name, row.Sum, r.Currency}
+}
+
+// optional возвращает значение указателя или nil, чтобы пустая ячейка не превращалась в "<nil>"
+func optional[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// exportDate переводит дату YYYY-MM-DD в time.Time, чтобы в XLSX она была датой, а не строкой
+func exportDate(value *string) any {
+	if value == nil || *value == "" {
+		return nil
+	}
+	date, err := time.Parse(DATE_FORMAT, *value)
+	if err != nil {
+		return *value
+	}
+	return date
+}
@@ -0,0 +1,57 @@
+package dto
+
+// Режимы импорта
+const (
+	// Любая ошибка отменяет весь импорт
+	ImportAllOrNothing = "all_or_nothing"
+	// Строки с ошибками пропускаются, остальные добавляются
+	ImportBestEffort = "best_effort"
+)
+
+// Поля подписки, которые можно загрузить из CSV
+var ImportColumns = []string{
+	"service_name",
+	"service_id",
+	"price",
+	"currency",
+	"user_id",
+	"start_date",
+	"end_date",
+	"category",
+	"tags",
+	"billing_period",
+	"billing_months",
+	"anchor_date",
+}
+
+type ImportOptions struct {
+	// Поле подписки -> название колонки в заголовке CSV. Без сопоставления колонка называется как поле
+	Mapping   map[string]string
+	Delimiter rune
+	// Формат дат из YYYY, MM и DD, например DD.MM.YYYY. Пустой - как в API (YYYY-MM-DD или MM-YYYY)
+	DateFormat string
+	Mode       string
+	// Только проверить строки, ничего не сохраняя
+	DryRun bool
+}
+
+// ImportRowError - ошибка в строке CSV
+type ImportRowError struct {
+	// Номер строки файла, заголовок - строка 1
+	Row   int    `json:"row" example:"3"`
+	Error string `json:"error" example:"invalid subscription: price must not be negative"`
+}
+
+type ImportResult struct {
+	Mode   string `json:"mode" example:"all_or_nothing"`
+	DryRun bool   `json:"dry_run" example:"false"`
+	// Количество строк с данными
+	Total int `json:"total" example:"120"`
+	// Сколько подписок добавлено (при dry_run - прошло бы проверку)
+	Imported int `json:"imported" example:"118"`
+	Failed   int `json:"failed" example:"2"`
+	// Изменения сохранены
+	Committed bool             `json:"committed" example:"true"`
+	IDs       []int32          `json:"ids"`
+	Errors    []ImportRowError `json:"errors"`
+}
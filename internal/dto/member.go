@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"database/sql"
+	"fmt"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/google/uuid"
+)
+
+// Правила разделения стоимости совместной подписки
+const (
+	SplitEqual   = "equal"
+	SplitPercent = "percent"
+	SplitFixed   = "fixed"
+)
+
+// Member - участник совместной подписки, оплачиваемой плательщиком user_id
+type Member struct {
+	UserID string `json:"user_id" example:"2f1a2c4e-8a0b-4d52-9d0c-0e3b8a1f5d21"`
+	// Процент стоимости (split_rule = percent) или сумма в валюте подписки от полной цены (fixed).
+	// При equal не указывается
+	Share *float64 `json:"share,omitempty" example:"25"`
+}
+
+func MemberFromSql(memberSql db.SubscriptionMember) Member {
+	member := Member{
+		UserID: memberSql.UserID.String(),
+	}
+	if memberSql.Share.Valid {
+		member.Share = &memberSql.Share.Float64
+	}
+	return member
+}
+
+func ValidSplitRule(rule string) bool {
+	switch rule {
+	case SplitEqual, SplitPercent, SplitFixed:
+		return true
+	}
+	return false
+}
+
+// MembersToSql проверяет участников подписки sub: участник указан один раз и не совпадает с плательщиком,
+// доля задана для percent и fixed и в сумме не превышает 100% или цену подписки
+func MembersToSql(members []Member, sub db.Subscription) ([]db.SubscriptionMember, error) {
+	rows := []db.SubscriptionMember{}
+	seen := map[uuid.UUID]bool{}
+	total := 0.0
+
+	for i, member := range members {
+		userID, err := uuid.Parse(member.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: members[%d].user_id: %w", ErrInvalid, i, err)
+		}
+		if userID == sub.UserID {
+			return nil, fmt.Errorf("%w: members[%d]: payer can't be a member", ErrInvalid, i)
+		}
+		if seen[userID] {
+			return nil, fmt.Errorf("%w: members[%d]: duplicate user_id", ErrInvalid, i)
+		}
+		seen[userID] = true
+
+		share := sql.NullFloat64{}
+		switch sub.SplitRule {
+		case SplitEqual:
+			if member.Share != nil {
+				return nil, fmt.Errorf("%w: members[%d]: share is not used with split_rule equal", ErrInvalid, i)
+			}
+		default:
+			if member.Share == nil || *member.Share <= 0 {
+				return nil, fmt.Errorf("%w: members[%d]: share must be positive", ErrInvalid, i)
+			}
+			share = sql.NullFloat64{Float64: *member.Share, Valid: true}
+			total += *member.Share
+		}
+
+		rows = append(rows, db.SubscriptionMember{
+			SubscriptionID: sub.ID,
+			TenantID:       sub.TenantID,
+			UserID:         userID,
+			Share:          share,
+		})
+	}
+
+	if sub.SplitRule == SplitPercent && total > 100 {
+		return nil, fmt.Errorf("%w: members' shares exceed 100%%", ErrInvalid)
+	}
+	if sub.SplitRule == SplitFixed && total > float64(sub.Price) {
+		return nil, fmt.Errorf("%w: members' shares exceed the price", ErrInvalid)
+	}
+
+	return rows, nil
+}
+
+// Debt - сумма, которую участник должен плательщику совместных подписок за период
+type Debt struct {
+	From   string  `json:"from" example:"2f1a2c4e-8a0b-4d52-9d0c-0e3b8a1f5d21"`
+	To     string  `json:"to" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Amount float64 `json:"amount" example:"350.5"`
+}
+
+type Settlement struct {
+	Currency string `json:"currency" example:"RUB"`
+	// Взаимные долги сокращены, у каждой пары пользователей остаётся не больше одного долга
+	Debts []Debt `json:"debts"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// Pause - период, в который подписка не списывается
+type Pause struct {
+	PausedFrom string `json:"paused_from" example:"2025-09-01"`
+	// Первый день после паузы, отсутствует у незавершённой паузы
+	ResumedAt *string `json:"resumed_at,omitempty" example:"2025-12-01"`
+}
+
+func PauseFromSql(pauseSql db.SubscriptionPause) Pause {
+	pause := Pause{
+		PausedFrom: pauseSql.PausedFrom.Format(DATE_FORMAT),
+	}
+	if pauseSql.ResumedAt.Valid {
+		resumedAt := pauseSql.ResumedAt.Time.Format(DATE_FORMAT)
+		pause.ResumedAt = &resumedAt
+	}
+	return pause
+}
+
+type PauseRequest struct {
+	// Дата начала паузы (YYYY-MM-DD или MM-YYYY), по умолчанию сегодня
+	From *string `json:"from,omitempty" example:"2025-09-01"`
+	// Дата возобновления, без неё пауза длится до вызова resume
+	Until *string `json:"until,omitempty" example:"2025-12-01"`
+}
+
+type ResumeRequest struct {
+	// Дата возобновления (YYYY-MM-DD или MM-YYYY), по умолчанию сегодня
+	Date *string `json:"date,omitempty" example:"2025-11-15"`
+}
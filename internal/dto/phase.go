@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// Phase - ценовая фаза подписки. После последней фазы действует обычная цена подписки.
+type Phase struct {
+	Kind string `json:"kind" example:"trial" enums:"trial,promo"`
+	// Цена в валюте подписки, для trial всегда 0
+	Price int `json:"price" example:"0"`
+	// Длительность фазы задаётся либо в месяцах, либо в днях
+	Months    *int   `json:"months,omitempty" example:"1"`
+	Days      *int   `json:"days,omitempty" example:"14"`
+	StartDate string `json:"start_date" example:"2025-07-01" readonly:"true"`
+	EndDate   string `json:"end_date" example:"2025-07-14" readonly:"true"`
+}
+
+type TrialEnding struct {
+	Subscription
+	// Последний день пробного периода (YYYY-MM-DD)
+	TrialEndDate string `json:"trial_end_date" example:"2025-07-14"`
+}
+
+func PhaseFromSql(phaseSql db.SubscriptionPhase) Phase {
+	phase := Phase{
+		Kind:      phaseSql.Kind,
+		Price:     int(phaseSql.Price),
+		StartDate: phaseSql.StartDate.Format(DATE_FORMAT),
+		EndDate:   phaseSql.EndDate.Format(DATE_FORMAT),
+	}
+
+	if phaseSql.Months.Valid {
+		months := int(phaseSql.Months.Int32)
+		phase.Months = &months
+	}
+	if phaseSql.Days.Valid {
+		days := int(phaseSql.Days.Int32)
+		phase.Days = &days
+	}
+
+	return phase
+}
+
+// PhasesToSql проверяет фазы и вычисляет их даты: первая фаза начинается в start,
+// каждая следующая - на следующий день после окончания предыдущей
+func PhasesToSql(phases []Phase, start time.Time) ([]db.SubscriptionPhase, error) {
+	result := []db.SubscriptionPhase{}
+
+	for i, phase := range phases {
+		if phase.Kind != billing.PhaseTrial && phase.Kind != billing.PhasePromo {
+			return nil, fmt.Errorf("%w: phases[%d]: kind must be trial or promo", ErrInvalid, i)
+		}
+		if phase.Kind == billing.PhaseTrial && phase.Price != 0 {
+			return nil, fmt.Errorf("%w: phases[%d]: trial price must be 0", ErrInvalid, i)
+		}
+		if phase.Price < 0 {
+			return nil, fmt.Errorf("%w: phases[%d]: price must not be negative", ErrInvalid, i)
+		}
+
+		months := sql.NullInt32{}
+		days := sql.NullInt32{}
+		var end time.Time
+		switch {
+		case phase.Months != nil && phase.Days != nil:
+			return nil, fmt.Errorf("%w: phases[%d]: set either months or days", ErrInvalid, i)
+		case phase.Months != nil && *phase.Months > 0:
+			months = sql.NullInt32{Int32: int32(*phase.Months), Valid: true}
+			end = billing.AddMonths(start, *phase.Months).AddDate(0, 0, -1)
+		case phase.Days != nil && *phase.Days > 0:
+			days = sql.NullInt32{Int32: int32(*phase.Days), Valid: true}
+			end = start.AddDate(0, 0, *phase.Days-1)
+		default:
+			return nil, fmt.Errorf("%w: phases[%d]: months or days must be positive", ErrInvalid, i)
+		}
+
+		result = append(result, db.SubscriptionPhase{
+			Position:  int32(i),
+			Kind:      phase.Kind,
+			Price:     int32(phase.Price),
+			Months:    months,
+			Days:      days,
+			StartDate: start,
+			EndDate:   end,
+		})
+		start = end.AddDate(0, 0, 1)
+	}
+
+	return result, nil
+}
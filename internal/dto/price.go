@@ -0,0 +1,50 @@
+package dto
+
+import (
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// SubscriptionPrice - обычная цена подписки, действующая с effective_from до следующего изменения
+type SubscriptionPrice struct {
+	EffectiveFrom string `json:"effective_from" example:"2025-07-01"`
+	Price         int    `json:"price" example:"400"`
+}
+
+func PriceFromSql(priceSql db.SubscriptionPrice) SubscriptionPrice {
+	return SubscriptionPrice{
+		EffectiveFrom: priceSql.EffectiveFrom.Format(DATE_FORMAT),
+		Price:         int(priceSql.Price),
+	}
+}
+
+// PriceChange - изменение цены всех активных подписок сервиса
+type PriceChange struct {
+	ServiceName string `json:"service_name" example:"Netflix"`
+	Price       int    `json:"price" example:"799"`
+	// Месяц (MM-YYYY) или дата (YYYY-MM-DD), с которой действует новая цена
+	EffectiveFrom string `json:"effective_from" example:"09-2025"`
+	// Только показать затронутые подписки, ничего не меняя
+	DryRun bool `json:"dry_run" example:"true"`
+}
+
+type PriceChangeRow struct {
+	SubscriptionID int32  `json:"subscription_id" example:"1"`
+	UserID         string `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Currency       string `json:"currency" example:"RUB"`
+	OldPrice       int    `json:"old_price" example:"599"`
+	NewPrice       int    `json:"new_price" example:"799"`
+	// Изменение стоимости в месяц в валюте подписки
+	MonthlyDelta float64 `json:"monthly_delta" example:"200"`
+}
+
+type PriceChangeResult struct {
+	ServiceName   string           `json:"service_name" example:"Netflix"`
+	Price         int              `json:"price" example:"799"`
+	EffectiveFrom string           `json:"effective_from" example:"2025-09-01"`
+	DryRun        bool             `json:"dry_run" example:"true"`
+	Affected      int              `json:"affected" example:"1"`
+	Rows          []PriceChangeRow `json:"rows"`
+	// Суммарное изменение стоимости в месяц в валюте Currency
+	MonthlyImpact float64 `json:"monthly_impact" example:"200"`
+	Currency      string  `json:"currency" example:"RUB"`
+}
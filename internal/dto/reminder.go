@@ -0,0 +1,55 @@
+package dto
+
+import (
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// UpcomingEvent - ближайшее событие подписки: списание, окончание подписки или пробного периода
+type UpcomingEvent struct {
+	Subscription
+	Kind string `json:"kind" example:"renewal" enums:"renewal,end,trial_end"`
+	// Дата события (YYYY-MM-DD)
+	Date string `json:"date" example:"2025-08-01"`
+	// Сумма списания в валюте подписки, для trial_end - цена после пробного периода
+	Amount int `json:"amount" example:"400"`
+}
+
+// NotificationSettings - настройки уведомлений пользователя
+type NotificationSettings struct {
+	UserID string `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba" readonly:"true"`
+	// За сколько дней напоминать о списании, окончании подписки или пробного периода
+	ReminderDays int `json:"reminder_days" example:"3"`
+	// Адрес для писем, без него письма не отправляются
+	Email *string `json:"email,omitempty" example:"user@example.com"`
+	// Язык писем
+	Locale string `json:"locale" example:"ru" enums:"ru,en"`
+	// Получать письма о списаниях, окончании подписок и пробных периодов
+	EmailReminders bool `json:"email_reminders" example:"true"`
+	// Получать письма о превышении бюджета
+	EmailBudgetAlerts bool `json:"email_budget_alerts" example:"true"`
+}
+
+func NotificationSettingsFromSql(settingsSql db.NotificationSetting) NotificationSettings {
+	settings := NotificationSettings{
+		UserID:            settingsSql.UserID.String(),
+		ReminderDays:      int(settingsSql.ReminderDays),
+		Locale:            settingsSql.Locale,
+		EmailReminders:    settingsSql.EmailReminders,
+		EmailBudgetAlerts: settingsSql.EmailBudgetAlerts,
+	}
+
+	if settingsSql.Email.Valid {
+		settings.Email = &settingsSql.Email.String
+	}
+
+	return settings
+}
+
+// EmailPreview - письмо, собранное по шаблону с примером данных
+type EmailPreview struct {
+	Kind    string `json:"kind" example:"renewal"`
+	Locale  string `json:"locale" example:"ru"`
+	Subject string `json:"subject" example:"Скоро списание за Yandex Plus"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
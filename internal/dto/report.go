@@ -0,0 +1,35 @@
+package dto
+
+type ReportRow struct {
+	Key string `json:"key" example:"video"`
+	// Название категории для group_by=category
+	Name string  `json:"name,omitempty" example:"Видео и стриминг"`
+	Sum  float64 `json:"sum" example:"1200"`
+}
+
+// Report - стоимость списаний за период в разрезе group_by
+type Report struct {
+	GroupBy  string      `json:"group_by" example:"category"`
+	Currency string      `json:"currency" example:"RUB"`
+	Total    float64     `json:"total" example:"1200"`
+	Rows     []ReportRow `json:"rows"`
+}
+
+type ForecastMonth struct {
+	Month string  `json:"month" example:"2025-11"`
+	Sum   float64 `json:"sum" example:"1200"`
+	// Стоимость месяца в разрезе group_by
+	Groups []ReportRow `json:"groups,omitempty"`
+}
+
+// Forecast - прогноз стоимости списаний по месяцам
+type Forecast struct {
+	GroupBy  string `json:"group_by,omitempty" example:"service"`
+	Currency string `json:"currency" example:"RUB"`
+	// Стоимость в месяц подписок, действующих сегодня
+	MonthlyRecurring float64 `json:"monthly_recurring" example:"1200"`
+	// Годовой объём при текущей стоимости в месяц
+	AnnualRunRate float64         `json:"annual_run_rate" example:"14400"`
+	Total         float64         `json:"total" example:"3600"`
+	Months        []ForecastMonth `json:"months"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// Service - сервис из каталога, с которым сопоставляются названия подписок
+type Service struct {
+	ID   int32  `json:"id" example:"1" readonly:"true"`
+	Name string `json:"name" example:"Yandex Plus"`
+	// Другие написания названия, сравниваются без учёта регистра
+	Aliases []string `json:"aliases" example:"Яндекс Плюс,Yandex+"`
+	// Slug категории из /categories
+	Category *string `json:"category,omitempty" example:"video"`
+	// Цена по умолчанию для новых подписок без цены
+	DefaultPrice *int   `json:"default_price,omitempty" example:"400"`
+	Currency     string `json:"currency" example:"RUB"`
+}
+
+func ServiceFromSql(serviceSql db.Service) Service {
+	service := Service{
+		ID:       serviceSql.ID,
+		Name:     serviceSql.Name,
+		Aliases:  serviceSql.Aliases,
+		Currency: serviceSql.Currency,
+	}
+
+	if service.Aliases == nil {
+		service.Aliases = []string{}
+	}
+	if serviceSql.Category.Valid {
+		service.Category = &serviceSql.Category.String
+	}
+	if serviceSql.DefaultPrice.Valid {
+		price := int(serviceSql.DefaultPrice.Int32)
+		service.DefaultPrice = &price
+	}
+
+	return service
+}
+
+type UnmatchedService struct {
+	ServiceName   string `json:"service_name" example:"Мой сервис"`
+	Subscriptions int64  `json:"subscriptions" example:"3"`
+}
+
+// ServiceLinkResult - результат привязки подписок к каталогу
+type ServiceLinkResult struct {
+	Linked    int64              `json:"linked" example:"10"`
+	Unmatched []UnmatchedService `json:"unmatched"`
+}
@@ -0,0 +1,79 @@
+package dto
+
+// StatementSuggestion - повторяющиеся списания из выписки, которые похожи на подписку
+type StatementSuggestion struct {
+	// Название продавца в выписке
+	Merchant string `json:"merchant" example:"NETFLIX.COM 866-579-7172"`
+	// Название сервиса из каталога или продавца
+	ServiceName string `json:"service_name" example:"Netflix"`
+	// Сервис каталога, с которым сопоставлен продавец
+	ServiceID *int32  `json:"service_id,omitempty" example:"1"`
+	Category  *string `json:"category,omitempty" example:"video"`
+	// Типичная сумма списания
+	Price    int    `json:"price" example:"399"`
+	Currency string `json:"currency" example:"RUB"`
+	// monthly или yearly
+	BillingPeriod string `json:"billing_period" example:"monthly" enums:"monthly,yearly"`
+	// Дата первого списания в выписке (YYYY-MM-DD)
+	StartDate string `json:"start_date" example:"2025-03-14"`
+	// Дата последнего списания в выписке (YYYY-MM-DD)
+	LastCharge string `json:"last_charge" example:"2025-08-14" readonly:"true"`
+	// Ожидаемая дата следующего списания (YYYY-MM-DD)
+	NextCharge string `json:"next_charge" example:"2025-09-14" readonly:"true"`
+	// Количество найденных списаний
+	Charges int `json:"charges" example:"6" readonly:"true"`
+	// Уверенность от 0 до 1
+	Confidence float64 `json:"confidence" example:"0.95" readonly:"true"`
+	// Ожидаемое следующее списание не пропущено больше чем на полпериода до конца выписки
+	Active bool `json:"active" example:"true" readonly:"true"`
+	// Подписка пользователя на этот сервис, если она уже есть
+	SubscriptionID *int32 `json:"subscription_id,omitempty" example:"12" readonly:"true"`
+}
+
+// ToSubscription переводит подтверждённую подсказку в подписку пользователя
+func (s StatementSuggestion) ToSubscription(userID string) Subscription {
+	return Subscription{
+		ServiceName:   s.ServiceName,
+		ServiceID:     s.ServiceID,
+		Category:      s.Category,
+		Price:         s.Price,
+		Currency:      s.Currency,
+		UserID:        userID,
+		StartDate:     s.StartDate,
+		BillingPeriod: s.BillingPeriod,
+	}
+}
+
+type StatementOptions struct {
+	// csv, ofx или qif. Пустой - определяется по содержимому
+	Format string
+	// Поле операции (date, description, amount, currency) -> название колонки CSV
+	Mapping   map[string]string
+	Delimiter rune
+	// Формат дат из YYYY, MM и DD
+	DateFormat string
+	// Валюта операций, если в выписке её нет. По умолчанию RUB
+	Currency string
+	// Допустимое отклонение суммы списания в процентах, по умолчанию 10
+	AmountTolerance float64
+}
+
+// StatementAnalysis - результат разбора выписки
+type StatementAnalysis struct {
+	Format string `json:"format" example:"csv"`
+	// Количество операций в выписке
+	Transactions int                   `json:"transactions" example:"214"`
+	Suggestions  []StatementSuggestion `json:"suggestions"`
+	// Строки, которые не удалось разобрать
+	Errors []ImportRowError `json:"errors"`
+}
+
+// StatementConfirm - подсказки, которые пользователь подтвердил. Поля можно изменить перед подтверждением
+type StatementConfirm struct {
+	Suggestions []StatementSuggestion `json:"suggestions"`
+}
+
+type StatementConfirmResult struct {
+	// Id созданных подписок в порядке подсказок
+	IDs []int32 `json:"ids"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+// Состояния подписки на сегодня: scheduled -> active -> (paused) -> cancelled -> ended
+const (
+	StatusScheduled = "scheduled"
+	StatusActive    = "active"
+	StatusPaused    = "paused"
+	StatusCancelled = "cancelled"
+	StatusEnded     = "ended"
+)
+
+func ValidStatus(status string) bool {
+	switch status {
+	case StatusScheduled, StatusActive, StatusPaused, StatusCancelled, StatusEnded:
+		return true
+	}
+	return false
+}
+
+type CancelRequest struct {
+	// true - подписка завершается сразу, иначе - в конце текущего периода оплаты
+	Immediately bool `json:"immediately" example:"false"`
+}
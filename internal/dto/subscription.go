@@ -2,35 +2,124 @@ package dto
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/feproldo/effective-mobile/internal/billing"
 	db "github.com/feproldo/effective-mobile/internal/db/generated"
 	"github.com/google/uuid"
 )
 
 const TIME_FORMAT = "01-2006"
 
+const DEFAULT_CURRENCY = "RUB"
+
+var ErrInvalid = errors.New("invalid subscription")
+
 type Subscription struct {
-	ServiceName string  `json:"service_name" example:"Yandex Plus"`
-	Price       int     `json:"price" example:"400"`
-	UserID      string  `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   string  `json:"start_date" example:"07-2025"`
-	EndDate     *string `json:"end_date" example:"08-2025"`
+	ID int32 `json:"id" example:"1" readonly:"true"`
+	// Название сопоставляется с каталогом сервисов и заменяется каноническим
+	ServiceName string `json:"service_name" example:"Yandex Plus"`
+	// Сервис из каталога, вместо service_name
+	ServiceID *int32 `json:"service_id,omitempty" example:"1"`
+	Price     int    `json:"price" example:"400"`
+	Currency  string `json:"currency" example:"RUB"`
+	UserID    string `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	// YYYY-MM-DD или MM-YYYY (первое число месяца), в ответе - MM-YYYY
+	StartDate string `json:"start_date" example:"07-2025"`
+	// YYYY-MM-DD или MM-YYYY (первое число месяца), в ответе - MM-YYYY
+	EndDate *string `json:"end_date" example:"08-2025"`
+	// Полная дата начала (YYYY-MM-DD)
+	StartDateISO string `json:"start_date_iso" example:"2025-07-01" readonly:"true"`
+	// Полная дата окончания (YYYY-MM-DD)
+	EndDateISO *string `json:"end_date_iso" example:"2025-08-01" readonly:"true"`
+	// Категория (slug из /categories), по умолчанию - категория сервиса из каталога
+	Category *string `json:"category,omitempty" example:"video"`
+	// Теги подписки. При обновлении отсутствие поля сохраняет текущие теги, пустой список удаляет их
+	Tags []string `json:"tags,omitempty" example:"family,work"`
+	// Периодичность списаний, по умолчанию monthly
+	BillingPeriod string `json:"billing_period" example:"monthly" enums:"weekly,monthly,quarterly,yearly,custom"`
+	// Количество месяцев между списаниями для billing_period = custom
+	BillingMonths *int `json:"billing_months,omitempty" example:"2"`
+	// Дата первого списания (YYYY-MM-DD), по умолчанию совпадает с start_date
+	AnchorDate *string `json:"anchor_date,omitempty" example:"2025-07-15"`
+	// При обновлении: месяц (MM-YYYY) или дата (YYYY-MM-DD), с которой действует новая цена.
+	// По умолчанию - начало текущего месяца, прошлые списания считаются по старой цене
+	PriceEffectiveFrom *string `json:"price_effective_from,omitempty" example:"09-2025"`
+	// Пробный период и промо-цены до перехода на обычную цену price.
+	// При обновлении отсутствие поля сохраняет текущие фазы, пустой список удаляет их
+	Phases []Phase `json:"phases,omitempty"`
+	// Совместная подписка: user_id платит, участники members возмещают свою долю по правилу split_rule.
+	// По умолчанию equal, при обновлении пустое значение сохраняет текущее правило
+	SplitRule string `json:"split_rule,omitempty" example:"equal" enums:"equal,percent,fixed"`
+	// Участники подписки. При обновлении отсутствие поля сохраняет текущих участников, пустой список удаляет их
+	Members []Member `json:"members,omitempty"`
+	// Состояние подписки на сегодня
+	Status string `json:"status" example:"active" enums:"scheduled,active,paused,cancelled,ended" readonly:"true"`
+	// Паузы подписки
+	Pauses []Pause `json:"pauses,omitempty" readonly:"true"`
+	// Время отмены, подписка действует до end_date
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" readonly:"true"`
+	// Стоимость в месяц в валюте подписки
+	MonthlyEquivalent float64    `json:"monthly_equivalent" example:"400" readonly:"true"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty" readonly:"true"`
 }
 
 func FromSql(subSql db.Subscription) Subscription {
 	temp := Subscription{
+		ID:          subSql.ID,
 		ServiceName: subSql.ServiceName,
 		Price:       int(subSql.Price),
+		Currency:    subSql.Currency,
 		UserID:      subSql.UserID.String(),
 		StartDate:   subSql.StartDate.Format(TIME_FORMAT),
 		EndDate:     nil,
+
+		StartDateISO: subSql.StartDate.Format(DATE_FORMAT),
+
+		BillingPeriod: subSql.BillingPeriod,
+		SplitRule:     subSql.SplitRule,
+	}
+
+	if subSql.ServiceID.Valid {
+		temp.ServiceID = &subSql.ServiceID.Int32
+	}
+
+	if subSql.Category.Valid {
+		temp.Category = &subSql.Category.String
+	}
+
+	if subSql.BillingMonths.Valid {
+		months := int(subSql.BillingMonths.Int32)
+		temp.BillingMonths = &months
+	}
+
+	if subSql.AnchorDate.Valid {
+		anchorDate := subSql.AnchorDate.Time.Format(DATE_FORMAT)
+		temp.AnchorDate = &anchorDate
+	}
+
+	if period, err := billing.ParsePeriod(subSql.BillingPeriod, int(subSql.BillingMonths.Int32)); err == nil {
+		temp.MonthlyEquivalent = math.Round(period.MonthlyEquivalent(float64(subSql.Price))*100) / 100
 	}
 
 	if subSql.EndDate.Valid == true {
 		endDate := subSql.EndDate.Time
 		endDateParsed := endDate.Format(TIME_FORMAT)
 		temp.EndDate = &endDateParsed
+		endDateISO := endDate.Format(DATE_FORMAT)
+		temp.EndDateISO = &endDateISO
+	}
+
+	if subSql.CancelledAt.Valid {
+		temp.CancelledAt = &subSql.CancelledAt.Time
+	}
+
+	if subSql.DeletedAt.Valid {
+		temp.DeletedAt = &subSql.DeletedAt.Time
 	}
 
 	return temp
@@ -39,34 +128,131 @@ func FromSql(subSql db.Subscription) Subscription {
 func (sub *Subscription) ToSql() (*db.Subscription, error) {
 	userUUID, err := uuid.Parse(sub.UserID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: user_id: %w", ErrInvalid, err)
 	}
 
-	startDate, err := time.Parse(TIME_FORMAT, sub.StartDate)
+	if sub.Price < 0 {
+		return nil, fmt.Errorf("%w: price must not be negative", ErrInvalid)
+	}
+
+	startDate, _, err := ParseDate(sub.StartDate)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: start_date: %w", ErrInvalid, err)
 	}
 
 	var endDate sql.NullTime = sql.NullTime{Valid: false, Time: time.Now()}
 
-	if sub.EndDate != nil {
+	if sub.EndDate != nil && *sub.EndDate != "" {
+		endTime, _, err := ParseDate(*sub.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: end_date: %w", ErrInvalid, err)
+		}
+		if endTime.Before(startDate) {
+			return nil, fmt.Errorf("%w: end_date is before start_date", ErrInvalid)
+		}
+		endDate = sql.NullTime{
+			Valid: true,
+			Time:  endTime,
+		}
+	}
+
+	currency := strings.ToUpper(sub.Currency)
+	if currency == "" {
+		currency = DEFAULT_CURRENCY
+	}
+
+	months := 0
+	if sub.BillingMonths != nil {
+		months = *sub.BillingMonths
+	}
+	period, err := billing.ParsePeriod(sub.BillingPeriod, months)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	billingMonths := sql.NullInt32{}
+	if period.Kind == billing.Custom {
+		billingMonths = sql.NullInt32{Int32: int32(period.Months), Valid: true}
+	}
 
-		endTime, err := time.Parse(TIME_FORMAT, *sub.EndDate)
-		if err == nil {
-			endDate = sql.NullTime{
-				Valid: true,
-				Time:  endTime,
-			}
+	anchorDate := sql.NullTime{}
+	if sub.AnchorDate != nil && *sub.AnchorDate != "" {
+		anchor, err := time.Parse(DATE_FORMAT, *sub.AnchorDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: anchor_date: %w", ErrInvalid, err)
 		}
+		anchorDate = sql.NullTime{Time: anchor, Valid: true}
+	}
+
+	serviceID := sql.NullInt32{}
+	if sub.ServiceID != nil {
+		serviceID = sql.NullInt32{Int32: *sub.ServiceID, Valid: true}
+	}
+
+	category := sql.NullString{}
+	if sub.Category != nil && *sub.Category != "" {
+		category = sql.NullString{String: *sub.Category, Valid: true}
+	}
+
+	if sub.SplitRule != "" && !ValidSplitRule(sub.SplitRule) {
+		return nil, fmt.Errorf("%w: split_rule must be one of equal, percent, fixed", ErrInvalid)
 	}
 
 	temp := db.Subscription{
-		ServiceName: sub.ServiceName,
-		Price:       int32(sub.Price),
-		UserID:      userUUID,
-		StartDate:   startDate,
-		EndDate:     endDate,
+		ServiceName:   sub.ServiceName,
+		Price:         int32(sub.Price),
+		Currency:      currency,
+		UserID:        userUUID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		BillingPeriod: period.Kind,
+		BillingMonths: billingMonths,
+		AnchorDate:    anchorDate,
+		ServiceID:     serviceID,
+		Category:      category,
+		SplitRule:     sub.SplitRule,
 	}
 
 	return &temp, nil
 }
+
+// ParseDate разбирает дату в формате YYYY-MM-DD или MM-YYYY.
+// Для MM-YYYY возвращается первое число месяца и wholeMonth = true.
+func ParseDate(value string) (date time.Time, wholeMonth bool, err error) {
+	date, err = time.Parse(DATE_FORMAT, value)
+	if err == nil {
+		return date, false, nil
+	}
+
+	date, err = time.Parse(TIME_FORMAT, value)
+	if err != nil {
+		return date, false, fmt.Errorf("date %q must be in YYYY-MM-DD or MM-YYYY format", value)
+	}
+	return date, true, nil
+}
+
+type SumFilter struct {
+	StartDate   string
+	EndDate     string
+	UserID      string
+	ServiceName string
+	// Валюта результата, по умолчанию DEFAULT_CURRENCY
+	Currency string
+	// billing.PrecisionMonth или billing.PrecisionDay, по умолчанию из настроек сервиса
+	DatePrecision string
+	// Категория вместе с дочерними
+	Category string
+	// Подписка должна иметь все перечисленные теги
+	Tags []string
+}
+
+type ListFilter struct {
+	// Вместе с удалёнными подписками (только для администратора)
+	IncludeDeleted bool
+	// Состояние подписки на сегодня
+	Status string
+	// Категория вместе с дочерними
+	Category string
+	// Подписка должна иметь все перечисленные теги
+	Tags []string
+}
@@ -0,0 +1,18 @@
+package dto
+
+type Tag struct {
+	ID            int32  `json:"id" example:"1"`
+	Name          string `json:"name" example:"family"`
+	Subscriptions int64  `json:"subscriptions" example:"3"`
+}
+
+type TagRename struct {
+	From string `json:"from" example:"famly"`
+	To   string `json:"to" example:"family"`
+}
+
+// TagMerge - перенос подписок с тегов From на тег To с удалением тегов From
+type TagMerge struct {
+	From []string `json:"from" example:"home,household"`
+	To   string   `json:"to" example:"family"`
+}
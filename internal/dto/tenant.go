@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+type Tenant struct {
+	ID        string     `json:"id" example:"marketing"`
+	Name      string     `json:"name" example:"Marketing department"`
+	CreatedAt *time.Time `json:"created_at,omitempty" swaggerignore:"true"`
+}
+
+func TenantFromSql(tenantSql db.Tenant) Tenant {
+	return Tenant{
+		ID:        tenantSql.ID,
+		Name:      tenantSql.Name,
+		CreatedAt: &tenantSql.CreatedAt,
+	}
+}
+
+type TenantSum struct {
+	TenantID string  `json:"tenant_id" example:"default"`
+	Sum      float64 `json:"sum" example:"1200"`
+	Currency string  `json:"currency" example:"RUB"`
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+// Webhook - получатель событий подписок
+type Webhook struct {
+	ID  int32  `json:"id" example:"1" readonly:"true"`
+	URL string `json:"url" example:"https://example.com/hooks/subscriptions"`
+	// Ключ подписи HMAC-SHA256. Если не задан, генерируется при создании. Возвращается только при создании
+	Secret string `json:"secret,omitempty" example:"whsec_2f1c..."`
+	// Типы событий, пустой список - все события
+	Events    []string  `json:"events" example:"subscription.created,subscription.cancelled"`
+	Active    bool      `json:"active" example:"true"`
+	CreatedAt time.Time `json:"created_at" readonly:"true"`
+}
+
+func WebhookFromSql(webhookSql db.Webhook) Webhook {
+	webhook := Webhook{
+		ID:        webhookSql.ID,
+		URL:       webhookSql.Url,
+		Events:    webhookSql.Events,
+		Active:    webhookSql.Active,
+		CreatedAt: webhookSql.CreatedAt,
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	return webhook
+}
+
+// WebhookDelivery - доставка события получателю
+type WebhookDelivery struct {
+	ID        int64  `json:"id" example:"1"`
+	EventID   int64  `json:"event_id" example:"1"`
+	EventType string `json:"event_type" example:"subscription.created"`
+	Status    string `json:"status" example:"failed" enums:"pending,delivered,failed"`
+	Attempts  int    `json:"attempts" example:"3"`
+	// Время следующей попытки для pending
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code,omitempty" example:"500"`
+	LastError      *string    `json:"last_error,omitempty" example:"unexpected status 500"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	// Попытки доставки, только для одной доставки
+	AttemptsLog []WebhookAttempt `json:"attempts_log,omitempty"`
+}
+
+func WebhookDeliveryFromSql(deliverySql db.GetWebhookDeliveryRow) WebhookDelivery {
+	delivery := WebhookDelivery{
+		ID:            deliverySql.ID,
+		EventID:       deliverySql.EventID,
+		EventType:     deliverySql.EventType,
+		Status:        deliverySql.Status,
+		Attempts:      int(deliverySql.Attempts),
+		NextAttemptAt: deliverySql.NextAttemptAt,
+		CreatedAt:     deliverySql.CreatedAt,
+	}
+
+	if deliverySql.LastStatusCode.Valid {
+		code := int(deliverySql.LastStatusCode.Int32)
+		delivery.LastStatusCode = &code
+	}
+	if deliverySql.LastError.Valid {
+		delivery.LastError = &deliverySql.LastError.String
+	}
+	if deliverySql.DeliveredAt.Valid {
+		delivery.DeliveredAt = &deliverySql.DeliveredAt.Time
+	}
+
+	return delivery
+}
+
+// WebhookAttempt - попытка доставки. Без status_code - запрос не дошёл до получателя
+type WebhookAttempt struct {
+	StatusCode  *int      `json:"status_code,omitempty" example:"500"`
+	Error       *string   `json:"error,omitempty" example:"unexpected status 500"`
+	DurationMs  int       `json:"duration_ms" example:"120"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+func WebhookAttemptFromSql(attemptSql db.WebhookAttempt) WebhookAttempt {
+	attempt := WebhookAttempt{
+		DurationMs:  int(attemptSql.DurationMs),
+		AttemptedAt: attemptSql.AttemptedAt,
+	}
+
+	if attemptSql.StatusCode.Valid {
+		code := int(attemptSql.StatusCode.Int32)
+		attempt.StatusCode = &code
+	}
+	if attemptSql.Error.Valid {
+		attempt.Error = &attemptSql.Error.String
+	}
+
+	return attempt
+}
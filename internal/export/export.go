@@ -0,0 +1,224 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Форматы выгрузки
+const (
+	JSON   = "json"
+	CSV    = "csv"
+	XLSX   = "xlsx"
+	NDJSON = "ndjson"
+)
+
+var ErrFormat = errors.New("format must be one of json, csv, xlsx, ndjson")
+
+var contentTypes = map[string]string{
+	JSON:   "application/json",
+	CSV:    "text/csv; charset=utf-8",
+	XLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	NDJSON: "application/x-ndjson",
+}
+
+// Типы из Accept и соответствующие им форматы
+var mediaTypes = map[string]string{
+	"application/json": JSON,
+	"text/csv":         CSV,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": XLSX,
+	"application/x-ndjson": NDJSON,
+	"application/ndjson":   NDJSON,
+	"application/jsonl":    NDJSON,
+}
+
+// Negotiate выбирает формат ответа: параметр format важнее заголовка Accept.
+// Без поддерживаемого типа в Accept ответ остаётся в JSON.
+func Negotiate(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if _, ok := contentTypes[format]; !ok {
+			return "", ErrFormat
+		}
+		return format, nil
+	}
+
+	type accepted struct {
+		format string
+		q      float64
+	}
+	var list []accepted
+	for _, value := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		format, ok := mediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			list = append(list, accepted{format: format, q: q})
+		}
+	}
+	if len(list) == 0 {
+		return JSON, nil
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].q > list[j].q })
+	return list[0].format, nil
+}
+
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Writer записывает выгрузку построчно. Заголовок пишется перед первой строкой или при Close,
+// поэтому до первой строки ещё можно ответить ошибкой.
+type Writer interface {
+	// Write добавляет строку: values - значения колонок для CSV и XLSX, object - строка NDJSON.
+	// Без object строка NDJSON собирается из колонок.
+	Write(object any, values []any) error
+	// Close дописывает файл
+	Close() error
+	// Written сообщает, что в ответ уже что-то отправлено
+	Written() bool
+}
+
+// New создаёт Writer формата csv, xlsx или ndjson. name - название листа XLSX
+func New(w io.Writer, format string, name string, columns []string) (Writer, error) {
+	out := &counter{w: w}
+
+	switch format {
+	case CSV:
+		return &csvWriter{out: out, csv: csv.NewWriter(out), columns: columns}, nil
+	case XLSX:
+		return newXLSX(out, name, columns), nil
+	case NDJSON:
+		return &ndjsonWriter{out: out, encoder: json.NewEncoder(out), columns: columns}, nil
+	}
+	return nil, ErrFormat
+}
+
+// counter считает отправленные байты
+type counter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type csvWriter struct {
+	out     *counter
+	csv     *csv.Writer
+	columns []string
+	started bool
+}
+
+func (w *csvWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	// BOM нужен Excel, чтобы прочитать UTF-8. Импорт его пропускает
+	if _, err := io.WriteString(w.out, "\ufeff"); err != nil {
+		return err
+	}
+	return w.csv.Write(w.columns)
+}
+
+func (w *csvWriter) Write(_ any, values []any) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+	}
+	return w.csv.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Written() bool {
+	return w.out.n > 0
+}
+
+type ndjsonWriter struct {
+	out     *counter
+	encoder *json.Encoder
+	columns []string
+}
+
+func (w *ndjsonWriter) Write(object any, values []any) error {
+	if object == nil {
+		row := map[string]any{}
+		for i, column := range w.columns {
+			if i < len(values) {
+				row[column] = values[i]
+			}
+		}
+		object = row
+	}
+	return w.encoder.Encode(object)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+func (w *ndjsonWriter) Written() bool {
+	return w.out.n > 0
+}
+
+// formatValue переводит значение колонки в текст CSV
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if isDate(v) {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// isDate - время без времени суток, то есть дата
+func isDate(t time.Time) bool {
+	return t.Equal(t.Truncate(24*time.Hour)) && t.Location() == time.UTC
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	auditService "github.com/feproldo/effective-mobile/internal/services/audit"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+type Handler struct {
+	services *auditService.Services
+}
+
+func NewHandler(services *auditService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get audit log
+// @Description  Get subscription changes of the tenant, newest first (admin only)
+// @Tags         audit
+// @Produce      json
+// @Param        actor       query       string false "Actor (JWT subject or anonymous)"
+// @Param        action      query       string false "Action" Enums(create, update, delete, restore, pause, resume, cancel, reactivate)
+// @Param        from        query       string false "From time (RFC 3339), inclusive"
+// @Param        to          query       string false "To time (RFC 3339), exclusive"
+// @Param        limit       query       int    false "Page size (default 100, max 1000)"
+// @Param        offset      query       int    false "Page offset"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {array}     dto.AuditRecord
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /audit [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := dto.AuditFilter{
+		Actor:  query.Get("actor"),
+		Action: query.Get("action"),
+		Limit:  defaultLimit,
+	}
+
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Err(err).Msg("can't parse query param \"" + name + "\"")
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		*target = &parsed
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = int32(min(limit, maxLimit))
+	}
+
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = int32(offset)
+	}
+
+	list, err := h.services.List(r.Context(), filter)
+	if err != nil {
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,209 @@
+package budgets
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	budgetService "github.com/feproldo/effective-mobile/internal/services/budgets"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *budgetService.Services
+}
+
+func NewHandler(services *budgetService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get user budgets
+// @Description  Get monthly budgets of the user
+// @Tags         budgets
+// @Produce      json
+// @Param        user_id     path      string true  "user_id (UUID)"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {array}   dto.Budget
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/budgets [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.services.List(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Add a user budget
+// @Description  Add a monthly budget for all subscriptions of the user, a category (with child categories) or a catalog service
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        user_id     path      string     true  "user_id (UUID)"
+// @Param        request     body      dto.Budget true  "Budget data"
+// @Param        X-Tenant-ID header    string     false "Tenant id"
+// @Success      201  {object}  dto.Budget
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      409  string    "budget already exists"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/budgets [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var body dto.Budget
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.services.Create(r.Context(), userID, body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(budget)
+}
+
+// @Summary      Update a user budget
+// @Description  Update a monthly budget of the user
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        user_id     path      string     true  "user_id (UUID)"
+// @Param        id          path      int        true  "Budget id"
+// @Param        request     body      dto.Budget true  "Budget data"
+// @Param        X-Tenant-ID header    string     false "Tenant id"
+// @Success      200  {object}  dto.Budget
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Budget not found"
+// @Failure      409  string    "budget already exists"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/budgets/{id} [put]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var body dto.Budget
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.services.Update(r.Context(), userID, id, body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+// @Summary      Delete a user budget
+// @Description  Delete a monthly budget of the user
+// @Tags         budgets
+// @Param        user_id     path      string true  "user_id (UUID)"
+// @Param        id          path      int    true  "Budget id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      204
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Budget not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/budgets/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Delete(r.Context(), userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, budgetService.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, budgetService.ErrInvalid), errors.Is(err, rates.ErrInvalidCurrency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, budgetService.ErrExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
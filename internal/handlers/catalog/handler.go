@@ -0,0 +1,223 @@
+package catalog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	catalogService "github.com/feproldo/effective-mobile/internal/services/catalog"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *catalogService.Services
+}
+
+func NewHandler(services *catalogService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get service catalog
+// @Description  Get services of the catalog ordered by name
+// @Tags         services
+// @Produce      json
+// @Param        X-Tenant-ID header   string false "Tenant id"
+// @Success      200  {array}   dto.Service
+// @Failure      401  string    "Unauthorized"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /services [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.List(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Get service by id
+// @Description  Get service of the catalog by id
+// @Tags         services
+// @Produce      json
+// @Param        id          path      int    true  "Service id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {object}  dto.Service
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      404  string    "Service not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /services/{id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	service, err := h.services.Get(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service)
+}
+
+// @Summary      Add a service to the catalog
+// @Description  Add a service to the catalog (admin only)
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Param        request     body      dto.Service true "Service data"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      201  {object}  dto.Service
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      409  string    "service already exists"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /services [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var body dto.Service
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.services.Create(r.Context(), body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(service)
+}
+
+// @Summary      Update a service of the catalog
+// @Description  Update a service of the catalog (admin only). Renaming also renames linked subscriptions
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int    true  "Service id"
+// @Param        request     body      dto.Service true "Service data"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {object}  dto.Service
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Service not found"
+// @Failure      409  string    "service already exists"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /services/{id} [put]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var body dto.Service
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.services.Update(r.Context(), id, body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service)
+}
+
+// @Summary      Delete a service from the catalog
+// @Description  Delete a service from the catalog (admin only). Linked subscriptions keep their service_name. A service with budgets can't be deleted
+// @Tags         services
+// @Param        id          path      int    true  "Service id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      204
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Service not found"
+// @Failure      409  string    "Service is used by budgets"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /services/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Delete(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Link subscriptions to the catalog
+// @Description  Link subscriptions without service_id to the catalog by name or alias (admin only). Returns names that could not be matched
+// @Tags         services
+// @Produce      json
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {object}  dto.ServiceLinkResult
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /services/link [post]
+func (h *Handler) Link(w http.ResponseWriter, r *http.Request) {
+	result, err := h.services.Link(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, catalogService.ErrInvalid), errors.Is(err, rates.ErrInvalidCurrency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, catalogService.ErrExists), errors.Is(err, catalogService.ErrInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
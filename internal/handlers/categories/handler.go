@@ -0,0 +1,39 @@
+package categories
+
+import (
+	"encoding/json"
+	"net/http"
+
+	categoriesService "github.com/feproldo/effective-mobile/internal/services/categories"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *categoriesService.Services
+}
+
+func NewHandler(services *categoriesService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get categories
+// @Description  Get the fixed hierarchy of subscription categories
+// @Tags         categories
+// @Produce      json
+// @Success      200  {array}   dto.Category
+// @Failure      401  string    "Unauthorized"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /categories [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	eventService "github.com/feproldo/effective-mobile/internal/services/events"
+	"github.com/rs/zerolog/log"
+)
+
+// Как часто отправлять комментарий, чтобы прокси не закрывали неактивное соединение
+const keepAlive = 15 * time.Second
+
+type Handler struct {
+	services *eventService.Services
+}
+
+func NewHandler(services *eventService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Stream subscription events
+// @Description  Server-Sent Events stream of subscription changes of the tenant from all replicas. Each event has the event id, the event type as the SSE event name and dto.Event as data. With Last-Event-ID (header or last_event_id param) the stream replays newer events from a bounded buffer; if some of them are no longer buffered, a "reset" event is sent first and the client should reload subscriptions
+// @Tags         subscriptions
+// @Produce      text/event-stream
+// @Param        user_id       query     string false "user_id (UUID)"
+// @Param        type          query     string false "Comma separated event types, e.g. subscription.created,subscription.deleted"
+// @Param        last_event_id query     string false "Id of the last received event, for clients that can't set Last-Event-ID"
+// @Param        Last-Event-ID header    string false "Id of the last received event"
+// @Param        X-Tenant-ID   header    string false "Tenant id"
+// @Success      200  {object}  dto.Event
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Security     BearerAuth
+// @Router       /subscriptions/events [get]
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var types []string
+	if value := query.Get("type"); value != "" {
+		types = strings.Split(value, ",")
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+
+	sub, err := h.services.Subscribe(r.Context(), query.Get("user_id"), types, lastEventID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	defer h.services.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	controller := http.NewResponseController(w)
+	fmt.Fprint(w, ": connected\n\n")
+
+	if sub.Reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range sub.Replay {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		log.Error().Err(err).Msg("can't flush event stream")
+		return
+	}
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event dto.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, eventService.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, eventService.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,158 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/notify"
+	notificationService "github.com/feproldo/effective-mobile/internal/services/notifications"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *notificationService.Services
+}
+
+func NewHandler(services *notificationService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get notification settings
+// @Description  Get notification settings of the user, defaults if the user has not set them
+// @Tags         notifications
+// @Produce      json
+// @Param        user_id     path      string true  "user_id (UUID)"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {object}  dto.NotificationSettings
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/notification-settings [get]
+func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.services.Settings(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// @Summary      Save notification settings
+// @Description  Save notification settings of the user
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        user_id     path      string                   true  "user_id (UUID)"
+// @Param        request     body      dto.NotificationSettings true  "Notification settings"
+// @Param        X-Tenant-ID header    string                   false "Tenant id"
+// @Success      200  {object}  dto.NotificationSettings
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/notification-settings [put]
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// Без полей email_reminders и email_budget_alerts письма включены
+	body := dto.NotificationSettings{EmailReminders: true, EmailBudgetAlerts: true}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.services.Save(r.Context(), userID, body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// @Summary      Preview email template
+// @Description  Render an email template with sample data. format=html returns the html version as a page, format=text - the text version
+// @Tags         notifications
+// @Produce      json
+// @Produce      html
+// @Produce      plain
+// @Param        kind        query     string false "Notification kind" Enums(renewal, end, trial_end, budget_exceeded) default(renewal)
+// @Param        locale      query     string false "Email language" Enums(ru, en) default(ru)
+// @Param        format      query     string false "Response format" Enums(json, html, text) default(json)
+// @Success      200  {object}  dto.EmailPreview
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /notifications/preview [get]
+func (h *Handler) Preview(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	kind := query.Get("kind")
+	if kind == "" {
+		kind = notify.KindRenewal
+	}
+
+	preview, err := h.services.Preview(kind, query.Get("locale"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	switch query.Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(preview)
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, preview.HTML)
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, "Subject: "+preview.Subject+"\n\n"+preview.Text)
+	default:
+		http.Error(w, "format must be json, html or text", http.StatusBadRequest)
+	}
+}
+
+func parseUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, notificationService.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, notificationService.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,87 @@
+package rates
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	ratesService "github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *ratesService.Services
+}
+
+func NewHandler(services *ratesService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get exchange rates
+// @Description  Get exchange rates to RUB
+// @Tags         exchange-rates
+// @Produce      json
+// @Param        currency query     string false "ISO 4217 currency code"
+// @Success      200      {array}   dto.ExchangeRate
+// @Failure      401      string    "Unauthorized"
+// @Failure      500      string    "Internal error"
+// @Security     BearerAuth
+// @Router       /exchange-rates [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.List(r.Context(), r.URL.Query().Get("currency"))
+	if err != nil {
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Load exchange rates
+// @Description  Load exchange rates to RUB (admin only). Accepts a JSON array or CSV with header currency,date,rate,source. Existing rates for the same date are replaced
+// @Tags         exchange-rates
+// @Accept       json
+// @Accept       text/csv
+// @Produce      plain
+// @Param        request body      []dto.ExchangeRate true "Exchange rates"
+// @Success      204
+// @Failure      400    string     "bad request"
+// @Failure      401    string     "Unauthorized"
+// @Failure      403    string     "Forbidden"
+// @Failure      500    string     "internal server error"
+// @Security     BearerAuth
+// @Router       /exchange-rates [post]
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	var body []dto.ExchangeRate
+	var err error
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		body, err = ratesService.ParseCSV(r.Body)
+	} else {
+		err = json.NewDecoder(r.Body).Decode(&body)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	err = h.services.Save(r.Context(), body)
+	if err != nil {
+		if errors.Is(err, ratesService.ErrInvalidRate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
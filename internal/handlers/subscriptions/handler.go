@@ -4,10 +4,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/feproldo/effective-mobile/internal/calendar"
 	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/export"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
 	subsService "github.com/feproldo/effective-mobile/internal/services/subscriptions"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -25,18 +32,49 @@ func NewHandler(services *subsService.Services) *Handler {
 }
 
 // @Summary      Get list of the subscriptions
-// @Description  Get list of the subscriptions
+// @Description  Get list of the subscriptions. With format (or Accept: text/csv, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet, application/x-ndjson) the list is streamed as a file from a database cursor
 // @Tags         subscriptions
 // @Produce      json
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
+// @Param        include_deleted query bool false "Include deleted subscriptions (admin only)"
+// @Param        category    query     string false "Category slug, child categories are included"
+// @Param        tags        query     string false "Comma-separated tags, the subscription must have all of them"
+// @Param        status      query     string false "Status of the subscription today" Enums(scheduled, active, paused, cancelled, ended)
+// @Param        format      query     string false "Response format, overrides Accept" Enums(json, csv, xlsx, ndjson)
+// @Param        X-Tenant-ID header   string false "Tenant id"
 // @Success      200  {array}   dto.Subscription
 // @Failure      404  string    "Subscriptions list is empty"
+// @Failure      401  string    "Unauthorized"
 // @Failure      500  string		"Internal error"
+// @Security     BearerAuth
 // @Router       /subscriptions [get]
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	list, err := h.services.List(r.Context())
+	filter := dto.ListFilter{
+		IncludeDeleted: r.URL.Query().Get("include_deleted") == "true",
+		Category:       r.URL.Query().Get("category"),
+		Tags:           splitList(r.URL.Query().Get("tags")),
+		Status:         r.URL.Query().Get("status"),
+	}
+
+	format, err := export.Negotiate(r)
 	if err != nil {
-		log.Error().Err(err).Send()
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != export.JSON {
+		writeExport(w, format, "subscriptions", dto.SubscriptionColumns, func(writer export.Writer) error {
+			return h.services.Export(r.Context(), filter, func(sub dto.Subscription) error {
+				return writer.Write(sub, sub.ExportValues())
+			})
+		})
+		return
+	}
+
+	list, err := h.services.List(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 	if len(*list) == 0 {
@@ -54,9 +92,14 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      plain
 // @Param        request body      dto.Subscription true "Subscription data"
+// @Param        X-Tenant-ID header   string false "Tenant id"
 // @Success      201    string     "created"
 // @Failure      400    string     "bad request"
+// @Failure      401    string     "unauthorized"
+// @Failure      403    string     "user_id belongs to another user"
+// @Failure      422    string     "strict budget exceeded"
 // @Failure      500    string     "interbal server error"
+// @Security     BearerAuth
 // @Router       /subscriptions    [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var body dto.Subscription
@@ -68,8 +111,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.services.Create(r.Context(), body)
 	if err != nil {
-		log.Error().Err(err).Send()
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -82,10 +124,14 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags         subscriptions
 // @Produce      json
 // @Param        id      path        int true "Serial primary key"
+// @Param        X-Tenant-ID header   string false "Tenant id"
 // @Success      200     {object}    dto.Subscription
 // @Failure      400     string     "Id not found"
 // @Failure      404     string     "Subscription not found"
 // @Failure      500     string		  "Internal error"
+// @Failure      401     string     "Unauthorized"
+// @Failure      403     string     "Forbidden"
+// @Security     BearerAuth
 // @Router       /subscriptions/{id} [get]
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -100,13 +146,7 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	sub, err := h.services.Get(r.Context(), int32(idParsed))
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error().Err(err).Msg("subscription not found")
-			http.Error(w, "not found", http.StatusNotFound)
-			return
-		}
-		log.Error().Err(err).Send()
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -114,14 +154,22 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary      Get subscription by user_id
-// @Description  Get subscription by user_id
+// @Description  Get subscriptions paid by the user and shared subscriptions where the user is a member. With format (or Accept) the list is streamed as a CSV, XLSX or NDJSON file
 // @Tags         subscriptions
 // @Produce      json
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
 // @Param        user_id path        string true "user UUID"
+// @Param        format  query       string false "Response format, overrides Accept" Enums(json, csv, xlsx, ndjson)
+// @Param        X-Tenant-ID header   string false "Tenant id"
 // @Success      200     {object}    dto.Subscription
 // @Failure      400     string     "user_id (UUID) not found"
 // @Failure      404     string     "Subscription not found"
 // @Failure      500     string		  "Internal error"
+// @Failure      401     string     "Unauthorized"
+// @Failure      403     string     "Forbidden"
+// @Security     BearerAuth
 // @Router       /subscriptions/user/{user_id} [get]
 func (h *Handler) GetByUserId(w http.ResponseWriter, r *http.Request) {
 	userId := chi.URLParam(r, "user_id")
@@ -132,11 +180,24 @@ func (h *Handler) GetByUserId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format, err := export.Negotiate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != export.JSON {
+		writeExport(w, format, "subscriptions", dto.SubscriptionColumns, func(writer export.Writer) error {
+			return h.services.ExportByUser(r.Context(), userUUID, func(sub dto.Subscription) error {
+				return writer.Write(sub, sub.ExportValues())
+			})
+		})
+		return
+	}
+
 	list, err := h.services.GetByUserId(r.Context(), userUUID)
 
 	if err != nil {
-		log.Error().Err(err).Send()
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -151,13 +212,17 @@ func (h *Handler) GetByUserId(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary      Delete subscription by its id
-// @Description  Delete subscription by its Serial Primary Key
+// @Description  Delete subscription by its Serial Primary Key. The subscription can be restored until it is purged after the retention period
 // @Tags         subscriptions
 // @Produce      plain
 // @Param        id      path        int true "Serial primary key"
+// @Param        X-Tenant-ID header   string false "Tenant id"
 // @Success      204
 // @Failure      400     string     "user_id (UUID) not found"
 // @Failure      500     string		  "Internal error"
+// @Failure      401     string     "Unauthorized"
+// @Failure      403     string     "Forbidden"
+// @Security     BearerAuth
 // @Router       /subscriptions/{id} [delete]
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -172,8 +237,7 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	err = h.services.Delete(r.Context(), int32(idParsed))
 
 	if err != nil {
-		log.Error().Err(err).Send()
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -186,9 +250,15 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Produce      plain
 // @Param        id      path        int true "Serial primary key"
 // @Param        request body        dto.Subscription true "Subscription data"
+// @Param        X-Tenant-ID header   string false "Tenant id"
 // @Success      204
 // @Failure      400     string     "user_id (UUID) not found"
 // @Failure      500     string		  "Internal error"
+// @Failure      401     string     "Unauthorized"
+// @Failure      403     string     "Forbidden"
+// @Failure      409     string     "end_date of a cancelled subscription is changed"
+// @Failure      422     string     "strict budget exceeded"
+// @Security     BearerAuth
 // @Router       /subscriptions/{id} [put]
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -212,8 +282,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	err = h.services.Update(r.Context(), int32(idParsed), body)
 
 	if err != nil {
-		log.Error().Err(err).Send()
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -221,30 +290,1005 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// @Summary      Get subscription by id
-// @Description  Get subscription by it's serial primary key
+// @Summary      Get total cost of the subscriptions
+// @Description  Get total cost of all charges that fall within the period. Charges follow the billing period of each subscription. Without end_date the period ends today
 // @Tags         subscriptions
 // @Produce      json
 // @Param        user_id      query       string false "user_id (UUID)"
-// @Param        servuce_name query       string false "Service name"
-// @Param        start_date   query       string false "Start date (MM-YYYY)"
-// @Param        end_date     query       string false "End date (MM-YYYY)"
-// @Success      200     plain       "Sum"
+// @Param        service_name query       string false "Service name"
+// @Param        start_date   query       string false "Start date (YYYY-MM-DD or MM-YYYY)"
+// @Param        end_date     query       string false "End date (YYYY-MM-DD or MM-YYYY, MM-YYYY includes the whole month)"
+// @Param        date_precision query     string false "month - whole charges, day - cost prorated by days. Defaults to DATE_PRECISION" Enums(month, day)
+// @Param        category     query       string false "Category slug, child categories are included"
+// @Param        tags         query       string false "Comma-separated tags, the subscription must have all of them"
+// @Param        currency     query       string false "Result currency (ISO 4217). If set, the response is a JSON object with the exchange rates used"
+// @Param        by_tenant    query       bool   false "Totals per tenant (admin only)"
+// @Param        X-Tenant-ID  header      string false "Tenant id"
+// @Success      200     plain       "Sum in RUB"
+// @Success      200     {object}    dto.SumResult
+// @Success      200     {array}     dto.TenantSum
+// @Failure      400     string      "Invalid currency, date or date_precision"
+// @Failure      422     string      "No exchange rate"
 // @Failure      500     string		  "Internal error"
+// @Failure      401     string     "Unauthorized"
+// @Failure      403     string     "Forbidden"
+// @Security     BearerAuth
 // @Router       /subscriptions/sum [get]
 func (h *Handler) Sum(w http.ResponseWriter, r *http.Request) {
-	userId := r.URL.Query().Get("user_id")
-	serviceName := r.URL.Query().Get("service_name")
-	startDate := r.URL.Query().Get("start_date")
-	endDate := r.URL.Query().Get("end_date")
+	filter := sumFilter(r)
+
+	if r.URL.Query().Get("by_tenant") == "true" {
+		sums, err := h.services.SumByTenant(r.Context(), filter)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sums)
+		return
+	}
+
+	sum, err := h.services.Sum(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if filter.Currency == "" {
+		w.Write([]byte(strconv.FormatFloat(sum.Sum, 'f', -1, 64)))
+		return
+	}
+	json.NewEncoder(w).Encode(sum)
+}
+
+// @Summary      Get cost report
+// @Description  Get total cost of the charges within the period grouped by category, service, user, month or tag. Takes the same filters as /subscriptions/sum. With group_by=tag a subscription with several tags is counted in each of them
+// @Tags         subscriptions
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
+// @Param        group_by     query       string true  "Grouping dimension" Enums(category, service, user, month, tag)
+// @Param        format       query       string false "Response format, overrides Accept. CSV, XLSX and NDJSON contain the report rows" Enums(json, csv, xlsx, ndjson)
+// @Param        user_id      query       string false "user_id (UUID)"
+// @Param        service_name query       string false "Service name"
+// @Param        start_date   query       string false "Start date (YYYY-MM-DD or MM-YYYY)"
+// @Param        end_date     query       string false "End date (YYYY-MM-DD or MM-YYYY, MM-YYYY includes the whole month)"
+// @Param        date_precision query     string false "month - whole charges, day - cost prorated by days. Defaults to DATE_PRECISION" Enums(month, day)
+// @Param        category     query       string false "Category slug, child categories are included"
+// @Param        tags         query       string false "Comma-separated tags, the subscription must have all of them"
+// @Param        currency     query       string false "Result currency (ISO 4217), RUB by default"
+// @Param        X-Tenant-ID  header      string false "Tenant id"
+// @Success      200     {object}    dto.Report
+// @Failure      400     string      "bad request"
+// @Failure      401     string      "Unauthorized"
+// @Failure      403     string      "Forbidden"
+// @Failure      422     string      "No exchange rate"
+// @Failure      500     string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/report [get]
+func (h *Handler) Report(w http.ResponseWriter, r *http.Request) {
+	format, err := export.Negotiate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.services.Report(r.Context(), sumFilter(r), r.URL.Query().Get("group_by"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
-	sum, err := h.services.Sum(r.Context(), startDate, endDate, userId, serviceName)
+	if format != export.JSON {
+		writeExport(w, format, "report", dto.ReportColumns, func(writer export.Writer) error {
+			for _, row := range report.Rows {
+				if err := writer.Write(nil, report.ExportValues(row)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// @Summary      Get settlement of shared subscriptions
+// @Description  Get how much members of shared subscriptions owe their payers for the charges within the period. Mutual debts are netted. Takes the same filters as /subscriptions/sum
+// @Tags         subscriptions
+// @Produce      json
+// @Param        user_id      query       string false "Only debts of this user or to this user (UUID)"
+// @Param        service_name query       string false "Service name"
+// @Param        start_date   query       string false "Start date (YYYY-MM-DD or MM-YYYY)"
+// @Param        end_date     query       string false "End date (YYYY-MM-DD or MM-YYYY, MM-YYYY includes the whole month)"
+// @Param        date_precision query     string false "month - whole charges, day - cost prorated by days. Defaults to DATE_PRECISION" Enums(month, day)
+// @Param        category     query       string false "Category slug, child categories are included"
+// @Param        tags         query       string false "Comma-separated tags, the subscription must have all of them"
+// @Param        currency     query       string false "Result currency (ISO 4217), RUB by default"
+// @Param        X-Tenant-ID  header      string false "Tenant id"
+// @Success      200     {object}    dto.Settlement
+// @Failure      400     string      "bad request"
+// @Failure      401     string      "Unauthorized"
+// @Failure      403     string      "Forbidden"
+// @Failure      422     string      "No exchange rate"
+// @Failure      500     string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/settlement [get]
+func (h *Handler) Settlement(w http.ResponseWriter, r *http.Request) {
+	settlement, err := h.services.Settlement(r.Context(), sumFilter(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settlement)
+}
+
+// @Summary      Get spend forecast
+// @Description  Get projected cost of the charges for the next months starting with the current one. Respects end dates, billing periods, pauses, pricing phases and scheduled price changes. Takes the same filters as /subscriptions/sum
+// @Tags         subscriptions
+// @Produce      json
+// @Param        months       query       int    false "Number of months, 3 by default"
+// @Param        group_by     query       string false "Split of each month" Enums(user, service)
+// @Param        user_id      query       string false "user_id (UUID)"
+// @Param        service_name query       string false "Service name"
+// @Param        date_precision query     string false "month - whole charges, day - cost prorated by days. Defaults to DATE_PRECISION" Enums(month, day)
+// @Param        category     query       string false "Category slug, child categories are included"
+// @Param        tags         query       string false "Comma-separated tags, the subscription must have all of them"
+// @Param        currency     query       string false "Result currency (ISO 4217), RUB by default"
+// @Param        X-Tenant-ID  header      string false "Tenant id"
+// @Success      200     {object}    dto.Forecast
+// @Failure      400     string      "bad request"
+// @Failure      401     string      "Unauthorized"
+// @Failure      403     string      "Forbidden"
+// @Failure      422     string      "No exchange rate"
+// @Failure      500     string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/forecast [get]
+func (h *Handler) Forecast(w http.ResponseWriter, r *http.Request) {
+	months := 3
+	if value := r.URL.Query().Get("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Err(err).Msg("can't parse query param \"months\"")
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		months = parsed
+	}
+
+	forecast, err := h.services.Forecast(r.Context(), sumFilter(r), months, r.URL.Query().Get("group_by"))
 	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forecast)
+}
+
+func sumFilter(r *http.Request) dto.SumFilter {
+	return dto.SumFilter{
+		UserID:      r.URL.Query().Get("user_id"),
+		ServiceName: r.URL.Query().Get("service_name"),
+		StartDate:   r.URL.Query().Get("start_date"),
+		EndDate:     r.URL.Query().Get("end_date"),
+		Currency:    r.URL.Query().Get("currency"),
+		Category:    r.URL.Query().Get("category"),
+		Tags:        splitList(r.URL.Query().Get("tags")),
+
+		DatePrecision: r.URL.Query().Get("date_precision"),
+	}
+}
+
+// splitList разбирает список значений через запятую
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// writeExport отправляет файл выгрузки, который заполняет fill. Ошибку до первой строки можно вернуть обычным ответом,
+// после неё соединение обрывается, чтобы клиент не принял неполный файл за целый.
+func writeExport(w http.ResponseWriter, format string, name string, columns []string, fill func(export.Writer) error) {
+	writer, err := export.New(w, format, name, columns)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	err = fill(writer)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if !writer.Written() {
+		w.Header().Del("Content-Disposition")
+		writeServiceError(w, err)
+		return
+	}
+	log.Error().Err(err).Str("format", format).Msg("export interrupted")
+	panic(http.ErrAbortHandler)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Error().Err(err).Msg("subscription not found")
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, subsService.ErrForbidden):
+		log.Info().Err(err).Msg("access denied")
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, dto.ErrInvalid), errors.Is(err, rates.ErrInvalidCurrency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, subsService.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, rates.ErrNoRate), errors.Is(err, subsService.ErrOverBudget):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
 		log.Error().Err(err).Send()
 		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// @Summary      Get subscription change history
+// @Description  Get audit records of the subscription in chronological order
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id          path        int    true  "Serial primary key"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {array}     dto.AuditRecord
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "History not found"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/history [get]
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.services.History(r.Context(), int32(idParsed))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// @Summary      Restore deleted subscription
+// @Description  Restore subscription deleted with DELETE /subscriptions/{id}
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id          path        int    true  "Serial primary key"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {object}    dto.Subscription
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Deleted subscription not found"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/restore [post]
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.services.Restore(r.Context(), int32(idParsed))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// @Summary      Get subscriptions whose trial ends soon
+// @Description  Get subscriptions whose trial phase ends within the next days (including today)
+// @Tags         subscriptions
+// @Produce      json
+// @Param        days        query       int    false "Days ahead, 7 by default"
+// @Param        user_id     query       string false "user_id (UUID)"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {array}     dto.TrialEnding
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/trials/ending [get]
+func (h *Handler) TrialsEnding(w http.ResponseWriter, r *http.Request) {
+	days := 7
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Err(err).Msg("can't parse query param \"days\"")
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	trials, err := h.services.TrialsEnding(r.Context(), days, r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trials)
+}
+
+// @Summary      Get subscription price history
+// @Description  Get regular prices of the subscription ordered by effective_from
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id          path        int    true  "Serial primary key"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {array}     dto.SubscriptionPrice
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Subscription not found"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/prices [get]
+func (h *Handler) Prices(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	prices, err := h.services.Prices(r.Context(), int32(idParsed))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prices)
+}
+
+// @Summary      Change the price of a service
+// @Description  Apply a new price to all active subscriptions of service_name from effective_from. Earlier charges keep the old price. With dry_run only the affected subscriptions and the monthly cost impact are returned
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        request     body        dto.PriceChange true "Price change"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {object}    dto.PriceChangeResult
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      422         string      "No exchange rate"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/price-changes [post]
+func (h *Handler) ChangePrice(w http.ResponseWriter, r *http.Request) {
+	var body dto.PriceChange
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.services.ChangePrice(r.Context(), body)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte(strconv.Itoa(*sum)))
+	json.NewEncoder(w).Encode(result)
+}
+
+// @Summary      Pause subscription
+// @Description  Pause subscription from the date (today by default) until the date or until resume. Paused charges are excluded from sums and reports
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id          path        int              true  "Serial primary key"
+// @Param        request     body        dto.PauseRequest false "Pause period"
+// @Param        X-Tenant-ID header      string           false "Tenant id"
+// @Success      200         {object}    dto.Subscription
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Subscription not found"
+// @Failure      409         string      "Subscription is already paused, cancelled or ended"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/pause [post]
+func (h *Handler) Pause(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	var body dto.PauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		log.Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.services.Pause(r.Context(), int32(idParsed), body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// @Summary      Resume subscription
+// @Description  Resume paused subscription from the date (today by default). A pause that has not started by the date is cancelled
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id          path        int               true  "Serial primary key"
+// @Param        request     body        dto.ResumeRequest false "Resume date"
+// @Param        X-Tenant-ID header      string            false "Tenant id"
+// @Success      200         {object}    dto.Subscription
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Subscription not found"
+// @Failure      409         string      "Subscription is not paused"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/resume [post]
+func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	var body dto.ResumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		log.Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.services.Resume(r.Context(), int32(idParsed), body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// @Summary      Cancel subscription
+// @Description  Cancel active or paused subscription at the end of the current billing period, or immediately. A cancelled subscription can be cancelled immediately or reactivated until it ends
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id          path        int               true  "Serial primary key"
+// @Param        request     body        dto.CancelRequest false "Cancellation mode"
+// @Param        X-Tenant-ID header      string            false "Tenant id"
+// @Success      200         {object}    dto.Subscription
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Subscription not found"
+// @Failure      409         string      "Invalid status transition"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/cancel [post]
+func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	var body dto.CancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		log.Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.services.Cancel(r.Context(), int32(idParsed), body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// @Summary      Reactivate cancelled subscription
+// @Description  Undo cancellation of a subscription that has not ended yet and restore its end_date
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id          path        int    true  "Serial primary key"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {object}    dto.Subscription
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Subscription not found"
+// @Failure      409         string      "Subscription is not cancelled"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/{id}/reactivate [post]
+func (h *Handler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idParsed, err := strconv.Atoi(id)
+
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.services.Reactivate(r.Context(), int32(idParsed))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// @Summary      Get user budget status
+// @Description  Compare monthly cost of the user's active subscriptions (the user's share of shared ones) with each budget of the user
+// @Tags         budgets
+// @Produce      json
+// @Param        user_id     path        string true  "user_id (UUID)"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {array}     dto.BudgetStatus
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      422         string      "No exchange rate"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/budget-status [get]
+func (h *Handler) BudgetStatus(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	statuses, err := h.services.BudgetStatus(r.Context(), userUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// @Summary      Get upcoming subscription events
+// @Description  Get charges, subscription ends and trial ends within the next days (including today) ordered by date
+// @Tags         subscriptions
+// @Produce      json
+// @Param        days        query       int    false "Days ahead, 7 by default"
+// @Param        user_id     query       string false "user_id (UUID)"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {array}     dto.UpcomingEvent
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/upcoming [get]
+func (h *Handler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	days := 7
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Err(err).Msg("can't parse query param \"days\"")
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	upcoming, err := h.services.Upcoming(r.Context(), days, r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(upcoming)
+}
+
+// Наибольший размер загружаемого файла
+const maxImportSize = 10 << 20
+
+// @Summary      Import subscriptions from CSV
+// @Description  Add subscriptions from a CSV file in one transaction. The file is sent as the "file" form field or as a text/csv body; the first row is the header. Every row is validated like POST /subscriptions. In all_or_nothing mode any row error cancels the import (422), in best_effort mode only the valid rows are added. The response lists errors of all rows by file line. With dry_run the rows are only validated
+// @Tags         subscriptions
+// @Accept       mpfd
+// @Produce      json
+// @Param        file        formData    file   true  "CSV file"
+// @Param        mapping     query       string false "JSON object field -> CSV column, e.g. {\"service_name\":\"Сервис\",\"price\":\"Цена\"}. Unmapped fields are read from the column with the field name"
+// @Param        delimiter   query       string false "Column delimiter: one character or tab" default(,)
+// @Param        date_format query       string false "Date format of YYYY, MM and DD, e.g. DD.MM.YYYY. Dates as in the API by default"
+// @Param        mode        query       string false "Import mode" Enums(all_or_nothing, best_effort) default(all_or_nothing)
+// @Param        dry_run     query       bool   false "Only validate the rows"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      200         {object}    dto.ImportResult
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      413         string      "File is too large"
+// @Failure      422         {object}    dto.ImportResult
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /subscriptions/import [post]
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	options, err := importOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.services.Import(r.Context(), file, options)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Committed && !result.DryRun {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func importOptions(r *http.Request) (dto.ImportOptions, error) {
+	query := r.URL.Query()
+	options := dto.ImportOptions{
+		DateFormat: query.Get("date_format"),
+		Mode:       query.Get("mode"),
+		DryRun:     query.Get("dry_run") == "true",
+	}
+
+	var err error
+	if options.Mapping, err = parseMapping(query.Get("mapping")); err != nil {
+		return options, err
+	}
+	if options.Delimiter, err = parseDelimiter(query.Get("delimiter")); err != nil {
+		return options, err
+	}
+
+	return options, nil
+}
+
+// uploadedFile возвращает загруженный файл: поле формы file или тело запроса
+func uploadedFile(w http.ResponseWriter, r *http.Request) (io.ReadCloser, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, true
+	}
+
+	file, _, err := r.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	if err != nil {
+		log.Info().Err(err).Msg("can't read uploaded file")
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return nil, false
+	}
+	return file, true
+}
+
+// parseMapping разбирает сопоставление полей с колонками CSV из JSON-объекта
+func parseMapping(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+		return nil, errors.New("mapping must be a JSON object")
+	}
+	return mapping, nil
+}
+
+func parseDelimiter(value string) (rune, error) {
+	switch {
+	case value == "":
+		return 0, nil
+	case value == "tab" || value == `\t`:
+		return '\t', nil
+	case utf8.RuneCountInString(value) == 1:
+		delimiter, _ := utf8.DecodeRuneInString(value)
+		return delimiter, nil
+	}
+	return 0, errors.New("delimiter must be one character")
+}
+
+func writeImportError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+	default:
+		writeServiceError(w, err)
+	}
+}
+
+// @Summary      Analyze bank statement
+// @Description  Find recurring charges in a bank statement (CSV, OFX or QIF) and suggest them as subscriptions of the user. Charges are grouped by similar merchant names and by amount within amount_tolerance, and must repeat monthly or yearly. Merchants are matched against the service catalog; subscription_id marks suggestions the user already has. Nothing is saved, confirmed suggestions are created with /users/{user_id}/statement-analysis/confirm
+// @Tags         subscriptions
+// @Accept       mpfd
+// @Produce      json
+// @Param        user_id          path        string true  "user_id (UUID)"
+// @Param        file             formData    file   true  "Statement file"
+// @Param        format           query       string false "Statement format, detected from the content by default" Enums(csv, ofx, qif)
+// @Param        mapping          query       string false "CSV only: JSON object field -> column for date, description, amount, currency"
+// @Param        delimiter        query       string false "CSV only: column delimiter, one character or tab. Detected from the header by default"
+// @Param        date_format      query       string false "Date format of YYYY, MM and DD, e.g. DD.MM.YYYY. Common formats are tried by default"
+// @Param        currency         query       string false "Currency of the statement if it has none (ISO 4217), RUB by default"
+// @Param        amount_tolerance query       number false "Allowed deviation of a charge from the typical amount, percent" default(10)
+// @Param        X-Tenant-ID      header      string false "Tenant id"
+// @Success      200              {object}    dto.StatementAnalysis
+// @Failure      400              string      "bad request"
+// @Failure      401              string      "Unauthorized"
+// @Failure      403              string      "Forbidden"
+// @Failure      413              string      "File is too large"
+// @Failure      500              string      "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/statement-analysis [post]
+func (h *Handler) AnalyzeStatement(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	options, err := statementOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	analysis, err := h.services.AnalyzeStatement(r.Context(), userUUID, file, options)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analysis)
+}
+
+// @Summary      Confirm statement suggestions
+// @Description  Create subscriptions of the user from the suggestions of /users/{user_id}/statement-analysis in one transaction: if any of them is invalid, none is created. Suggestion fields may be edited before confirming
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        user_id     path        string               true  "user_id (UUID)"
+// @Param        request     body        dto.StatementConfirm true  "Confirmed suggestions"
+// @Param        X-Tenant-ID header      string               false "Tenant id"
+// @Success      201         {object}    dto.StatementConfirmResult
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      422         string      "strict budget exceeded"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/statement-analysis/confirm [post]
+func (h *Handler) ConfirmStatement(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	var body dto.StatementConfirm
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.services.ConfirmStatement(r.Context(), userUUID, body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func statementOptions(r *http.Request) (dto.StatementOptions, error) {
+	query := r.URL.Query()
+	options := dto.StatementOptions{
+		Format:     query.Get("format"),
+		DateFormat: query.Get("date_format"),
+		Currency:   strings.ToUpper(query.Get("currency")),
+	}
+
+	var err error
+	if options.Mapping, err = parseMapping(query.Get("mapping")); err != nil {
+		return options, err
+	}
+	if options.Delimiter, err = parseDelimiter(query.Get("delimiter")); err != nil {
+		return options, err
+	}
+	if value := query.Get("amount_tolerance"); value != "" {
+		if options.AmountTolerance, err = strconv.ParseFloat(value, 64); err != nil {
+			return options, errors.New("amount_tolerance must be a number")
+		}
+	}
+
+	return options, nil
+}
+
+// @Summary      Create calendar token
+// @Description  Create a secret token of the user's calendar feed /users/{user_id}/calendar.ics. The previous token stops working. The token is only shown in this response
+// @Tags         calendar
+// @Produce      json
+// @Param        user_id     path        string true  "user_id (UUID)"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      201         {object}    dto.CalendarToken
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/calendar-token [post]
+func (h *Handler) CreateCalendarToken(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.services.CreateCalendarToken(r.Context(), userUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// @Summary      Revoke calendar token
+// @Description  Delete the token of the user's calendar feed, the feed stops being available
+// @Tags         calendar
+// @Param        user_id     path        string true  "user_id (UUID)"
+// @Param        X-Tenant-ID header      string false "Tenant id"
+// @Success      204
+// @Failure      400         string      "bad request"
+// @Failure      401         string      "Unauthorized"
+// @Failure      403         string      "Forbidden"
+// @Failure      404         string      "Token not found"
+// @Failure      500         string      "Internal error"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/calendar-token [delete]
+func (h *Handler) RevokeCalendarToken(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.services.RevokeCalendarToken(r.Context(), userUUID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      User calendar feed
+// @Description  iCalendar (RFC 5545) feed of the user's upcoming charges for calendar apps: a recurring all-day event per subscription with future charges, starting from the next charge, with the price in the summary, UNTIL from end_date and a reminder reminder_days before the charge. Authenticated by the secret token from /users/{user_id}/calendar-token instead of headers
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        user_id     path        string true  "user_id (UUID)"
+// @Param        token       query       string true  "Calendar token"
+// @Success      200         string      "iCalendar feed"
+// @Failure      400         string      "bad request"
+// @Failure      403         string      "Forbidden"
+// @Failure      500         string      "Internal error"
+// @Router       /users/{user_id}/calendar.ics [get]
+func (h *Handler) Calendar(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"user_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.services.Calendar(r.Context(), userUUID, r.URL.Query().Get("token"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", calendar.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="subscriptions.ics"`)
+	feed := calendar.New(w, "Subscriptions")
+	for _, event := range events {
+		if err := feed.Event(event); err != nil {
+			log.Error().Err(err).Msg("can't write calendar")
+			return
+		}
+	}
+	if err := feed.Close(); err != nil {
+		log.Error().Err(err).Msg("can't write calendar")
+	}
 }
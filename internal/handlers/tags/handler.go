@@ -0,0 +1,119 @@
+package tags
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	tagsService "github.com/feproldo/effective-mobile/internal/services/tags"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *tagsService.Services
+}
+
+func NewHandler(services *tagsService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get tags
+// @Description  Get tags of the tenant with the number of subscriptions
+// @Tags         tags
+// @Produce      json
+// @Param        X-Tenant-ID header   string false "Tenant id"
+// @Success      200  {array}   dto.Tag
+// @Failure      401  string    "Unauthorized"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /tags [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.List(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Rename a tag
+// @Description  Rename a tag across all subscriptions (admin only). If the new name is taken, use merge
+// @Tags         tags
+// @Accept       json
+// @Param        request     body      dto.TagRename true "Rename"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      204
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Tag not found"
+// @Failure      409  string    "tag already exists, use merge"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /tags/rename [post]
+func (h *Handler) Rename(w http.ResponseWriter, r *http.Request) {
+	var body dto.TagRename
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.services.Rename(r.Context(), body); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Merge tags
+// @Description  Move subscriptions from the tags in "from" to the tag "to" and delete the tags in "from" (admin only)
+// @Tags         tags
+// @Accept       json
+// @Param        request     body      dto.TagMerge true "Merge"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      204
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Tag not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /tags/merge [post]
+func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
+	var body dto.TagMerge
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.services.Merge(r.Context(), body); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "tag not found", http.StatusNotFound)
+	case errors.Is(err, tagsService.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, tagsService.ErrExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,84 @@
+package tenants
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	tenantsService "github.com/feproldo/effective-mobile/internal/services/tenants"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *tenantsService.Services
+}
+
+func NewHandler(services *tenantsService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get list of the tenants
+// @Description  Get list of the tenants (admin only)
+// @Tags         tenants
+// @Produce      json
+// @Success      200  {array}   dto.Tenant
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /tenants [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Add a new tenant
+// @Description  Add a new tenant with the default service catalog (admin only)
+// @Tags         tenants
+// @Accept       json
+// @Produce      json
+// @Param        request body      dto.Tenant true "Tenant data"
+// @Success      201    {object}   dto.Tenant
+// @Failure      400    string     "bad request"
+// @Failure      401    string     "Unauthorized"
+// @Failure      403    string     "Forbidden"
+// @Failure      409    string     "tenant already exists"
+// @Failure      500    string     "internal server error"
+// @Security     BearerAuth
+// @Router       /tenants [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var body dto.Tenant
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	tenant, err := h.services.Create(r.Context(), body)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenantsService.ErrInvalidID):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, tenantsService.ErrExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Error().Err(err).Send()
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tenant)
+}
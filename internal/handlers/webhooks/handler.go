@@ -0,0 +1,308 @@
+package webhooks
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+	webhookService "github.com/feproldo/effective-mobile/internal/services/webhooks"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type Handler struct {
+	services *webhookService.Services
+}
+
+func NewHandler(services *webhookService.Services) *Handler {
+	return &Handler{
+		services: services,
+	}
+}
+
+// @Summary      Get webhooks
+// @Description  Get webhooks of the tenant (admin only). Secrets are not returned
+// @Tags         webhooks
+// @Produce      json
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {array}   dto.Webhook
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.List(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Get webhook by id
+// @Description  Get webhook by id (admin only)
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int    true  "Webhook id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {object}  dto.Webhook
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Webhook not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.services.Get(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// @Summary      Register a webhook
+// @Description  Register a receiver of subscription events (admin only). Requests are signed with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" in X-Webhook-Signature. The secret is generated if empty and returned only here
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request     body      dto.Webhook true  "Webhook data"
+// @Param        X-Tenant-ID header    string      false "Tenant id"
+// @Success      201  {object}  dto.Webhook
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	// Без поля active получатель включён
+	body := dto.Webhook{Active: true}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.services.Create(r.Context(), body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// @Summary      Update a webhook
+// @Description  Update url, events and active flag of a webhook (admin only). The secret is not changed
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int         true  "Webhook id"
+// @Param        request     body      dto.Webhook true  "Webhook data"
+// @Param        X-Tenant-ID header    string      false "Tenant id"
+// @Success      200  {object}  dto.Webhook
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Webhook not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [put]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	// Без поля active получатель включён
+	body := dto.Webhook{Active: true}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Msg("can't decode request body")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.services.Update(r.Context(), id, body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// @Summary      Delete a webhook
+// @Description  Delete a webhook with its deliveries (admin only)
+// @Tags         webhooks
+// @Param        id          path      int    true  "Webhook id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      204
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Webhook not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Delete(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Get webhook deliveries
+// @Description  Get the last 100 deliveries of a webhook (admin only)
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int    true  "Webhook id"
+// @Param        status      query     string false "Delivery status" Enums(pending, delivered, failed)
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {array}   dto.WebhookDelivery
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Webhook not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *Handler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.services.Deliveries(r.Context(), id, r.URL.Query().Get("status"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary      Get webhook delivery
+// @Description  Get a delivery with all its attempts (admin only)
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int    true  "Webhook id"
+// @Param        delivery_id path      int    true  "Delivery id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      200  {object}  dto.WebhookDelivery
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Delivery not found"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *Handler) Delivery(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := parseDeliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.services.Delivery(r.Context(), id, deliveryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// @Summary      Replay webhook delivery
+// @Description  Queue a failed or delivered delivery again with the full number of attempts (admin only)
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int    true  "Webhook id"
+// @Param        delivery_id path      int    true  "Delivery id"
+// @Param        X-Tenant-ID header    string false "Tenant id"
+// @Success      202  {object}  dto.WebhookDelivery
+// @Failure      400  string    "bad request"
+// @Failure      401  string    "Unauthorized"
+// @Failure      403  string    "Forbidden"
+// @Failure      404  string    "Delivery not found"
+// @Failure      409  string    "delivery is already pending"
+// @Failure      500  string    "Internal error"
+// @Security     BearerAuth
+// @Router       /webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (h *Handler) Replay(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := parseDeliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.services.Replay(r.Context(), id, deliveryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func parseDeliveryID(w http.ResponseWriter, r *http.Request) (int32, int64, bool) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "delivery_id"), 10, 64)
+	if err != nil {
+		log.Err(err).Msg("can't get URL param \"delivery_id\"")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, deliveryID, true
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, webhookService.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, webhookService.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error().Err(err).Send()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
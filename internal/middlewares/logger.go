@@ -23,8 +23,9 @@ func ZeroLogLogger(next http.Handler) http.Handler {
 
 		defer func() {
 			log.Info().
+				Str("request_id", middleware.GetReqID(r.Context())).
 				Str("method", r.Method).
-				Str("path", r.RequestURI).
+				Str("path", requestURI(r)).
 				Str("remote_addr", r.RemoteAddr).
 				Int("status", ww.Status()).
 				Int("bytes", ww.BytesWritten()).
@@ -35,3 +36,13 @@ func ZeroLogLogger(next http.Handler) http.Handler {
 		next.ServeHTTP(ww, r)
 	})
 }
+
+// requestURI возвращает URI запроса со скрытым секретным токеном календаря
+func requestURI(r *http.Request) string {
+	query := r.URL.Query()
+	if !query.Has("token") {
+		return r.RequestURI
+	}
+	query.Set("token", "redacted")
+	return r.URL.Path + "?" + query.Encode()
+}
@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/ratelimit"
+	"github.com/rs/zerolog/log"
+)
+
+type RateLimits struct {
+	Read  ratelimit.Limit
+	Write ratelimit.Limit
+}
+
+// RateLimit ограничивает частоту запросов клиента к группе маршрутов group: отдельно для чтения (GET/HEAD) и записи.
+// Клиент определяется по проверенному токену, поэтому middleware ставится после Authenticate,
+// а без аутентификации - по IP.
+func RateLimit(store ratelimit.Store, group string, limits RateLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			kind, limit := "write", limits.Write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				kind, limit = "read", limits.Read
+			}
+
+			take(w, r, next, store, group+":"+kind+":"+clientKey(r), limit)
+		})
+	}
+}
+
+// RateLimitIP ограничивает частоту запросов с одного IP ко всем маршрутам с аутентификацией. Ставится перед
+// Authenticate: RateLimit после неё не видит запросов с неверными или просроченными токенами.
+func RateLimitIP(store ratelimit.Store, limit ratelimit.Limit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			take(w, r, next, store, "auth:"+ipKey(r), limit)
+		})
+	}
+}
+
+// take списывает токен из bucket'а key и пропускает запрос дальше или отвечает 429
+func take(w http.ResponseWriter, r *http.Request, next http.Handler, store ratelimit.Store, key string, limit ratelimit.Limit) {
+	if !limit.Enabled() {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	result, err := store.Take(r.Context(), key, limit)
+	if err != nil {
+		// Недоступность хранилища лимитов не должна ронять сервис
+		log.Error().Err(err).Msg("rate limiter error")
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", seconds(result.Reset))
+
+	if !result.Allowed {
+		w.Header().Set("Retry-After", seconds(result.RetryAfter))
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// clientKey возвращает пользователя из проверенного токена или IP клиента. Непроверенные заголовки
+// не используются: иначе случайное значение в каждом запросе давало бы новый bucket.
+func clientKey(r *http.Request) string {
+	if p, ok := auth.FromContext(r.Context()); ok {
+		return "user:" + p.TenantID + ":" + p.Subject
+	}
+	return ipKey(r)
+}
+
+func ipKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,121 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/ratelimit"
+	"github.com/google/uuid"
+)
+
+func TestRateLimitClient(t *testing.T) {
+	limits := RateLimits{
+		Read:  ratelimit.Limit{Requests: 2, Per: time.Minute},
+		Write: ratelimit.Limit{Requests: 1, Per: time.Minute},
+	}
+	alice := auth.Principal{Subject: "alice", UserID: uuid.New()}
+	bob := auth.Principal{Subject: "bob", UserID: uuid.New()}
+
+	type request struct {
+		method    string
+		addr      string
+		bearer    string
+		principal *auth.Principal
+		status    int
+	}
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			// Непроверенный токен не даёт нового bucket'а
+			name: "unverified tokens share the ip bucket",
+			requests: []request{
+				{method: http.MethodGet, addr: "10.0.0.1:1000", bearer: "random-1", status: http.StatusOK},
+				{method: http.MethodGet, addr: "10.0.0.1:1001", bearer: "random-2", status: http.StatusOK},
+				{method: http.MethodGet, addr: "10.0.0.1:1002", bearer: "random-3", status: http.StatusTooManyRequests},
+				{method: http.MethodGet, addr: "10.0.0.2:1000", status: http.StatusOK},
+			},
+		},
+		{
+			name: "verified principals have own buckets",
+			requests: []request{
+				{method: http.MethodGet, addr: "10.0.0.1:1000", principal: &alice, status: http.StatusOK},
+				{method: http.MethodGet, addr: "10.0.0.2:1000", principal: &alice, status: http.StatusOK},
+				{method: http.MethodGet, addr: "10.0.0.3:1000", principal: &alice, status: http.StatusTooManyRequests},
+				{method: http.MethodGet, addr: "10.0.0.1:1000", principal: &bob, status: http.StatusOK},
+			},
+		},
+		{
+			name: "read and write limits are separate",
+			requests: []request{
+				{method: http.MethodPost, addr: "10.0.0.1:1000", principal: &alice, status: http.StatusOK},
+				{method: http.MethodPost, addr: "10.0.0.1:1000", principal: &alice, status: http.StatusTooManyRequests},
+				{method: http.MethodGet, addr: "10.0.0.1:1000", principal: &alice, status: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		handler := RateLimit(ratelimit.NewMemoryStore(), "subscriptions", limits)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		for i, req := range tt.requests {
+			r := httptest.NewRequest(req.method, "/subscriptions", nil)
+			r.RemoteAddr = req.addr
+			if req.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+req.bearer)
+			}
+			if req.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), *req.principal))
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != req.status {
+				t.Errorf("%s: request %d: status %d, want %d", tt.name, i, w.Code, req.status)
+			}
+			if req.status == http.StatusTooManyRequests {
+				if _, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil {
+					t.Errorf("%s: request %d: Retry-After %q", tt.name, i, w.Header().Get("Retry-After"))
+				}
+			}
+		}
+	}
+}
+
+func TestRateLimitIP(t *testing.T) {
+	handler := RateLimitIP(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Per: time.Minute})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	alice := auth.Principal{Subject: "alice", UserID: uuid.New()}
+
+	requests := []struct {
+		method    string
+		addr      string
+		principal *auth.Principal
+		status    int
+	}{
+		{method: http.MethodGet, addr: "10.0.0.1:1000", status: http.StatusOK},
+		{method: http.MethodPost, addr: "10.0.0.1:1001", status: http.StatusOK},
+		// Чтение, запись и пользователь не дают нового bucket'а
+		{method: http.MethodGet, addr: "10.0.0.1:1002", principal: &alice, status: http.StatusTooManyRequests},
+		{method: http.MethodGet, addr: "10.0.0.2:1000", status: http.StatusOK},
+	}
+
+	for i, req := range requests {
+		r := httptest.NewRequest(req.method, "/subscriptions", nil)
+		r.RemoteAddr = req.addr
+		r.Header.Set("Authorization", "Bearer expired")
+		if req.principal != nil {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), *req.principal))
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != req.status {
+			t.Errorf("request %d: status %d, want %d", i, w.Code, req.status)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/rs/zerolog/log"
+)
+
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant определяет тенанта запроса: из claim tenant_id токена, иначе из заголовка X-Tenant-ID.
+// Переопределить тенанта из токена заголовком может только администратор, пользователь без tenant_id
+// в токене работает в тенанте по умолчанию.
+func Tenant(exists func(ctx context.Context, id string) (bool, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := r.Header.Get(TenantHeader)
+
+			if p, ok := auth.FromContext(r.Context()); ok {
+				switch {
+				case p.TenantID != "":
+					if tenantID != "" && tenantID != p.TenantID && !p.Admin {
+						http.Error(w, "forbidden", http.StatusForbidden)
+						return
+					}
+					if tenantID == "" {
+						tenantID = p.TenantID
+					}
+				// Без claim tenant_id пользователь работает только в тенанте по умолчанию
+				case !p.Admin && tenantID != "" && tenantID != tenancy.Default:
+					http.Error(w, "forbidden", http.StatusForbidden)
+					return
+				}
+			}
+
+			if tenantID == "" {
+				tenantID = tenancy.Default
+			}
+
+			ok, err := exists(r.Context(), tenantID)
+			if err != nil {
+				log.Error().Err(err).Send()
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				http.Error(w, "unknown tenant", http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenancy.WithTenant(r.Context(), tenantID)))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Виды уведомлений
+const (
+	KindRenewal  = "renewal"
+	KindEnd      = "end"
+	KindTrialEnd = "trial_end"
+	// Превышение месячного бюджета после изменения подписки
+	KindBudgetExceeded = "budget_exceeded"
+)
+
+var Kinds = []string{KindRenewal, KindEnd, KindTrialEnd, KindBudgetExceeded}
+
+// Message - уведомление пользователя о событии подписки
+type Message struct {
+	Kind           string
+	TenantID       string
+	UserID         uuid.UUID
+	SubscriptionID int32
+	ServiceName    string
+	// Дата события: списания, окончания подписки или пробного периода
+	Date     time.Time
+	Amount   int
+	Currency string
+	// Лимит бюджета для budget_exceeded, Amount - расходы в месяц
+	MonthlyLimit int
+	// Адрес для писем, пустой - пользователь не получает писем этого вида
+	Email  string
+	Locale string
+}
+
+// Notifier доставляет уведомления. Ошибка означает, что доставку нужно повторить.
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// Log пишет уведомления в лог. Используется, если другие способы доставки не настроены.
+type Log struct{}
+
+func (Log) Notify(ctx context.Context, message Message) error {
+	log.Info().
+		Str("kind", message.Kind).
+		Str("tenant_id", message.TenantID).
+		Str("user_id", message.UserID.String()).
+		Int32("subscription_id", message.SubscriptionID).
+		Str("service_name", message.ServiceName).
+		Str("date", message.Date.Format(time.DateOnly)).
+		Msg("notification")
+	return nil
+}
+
+// Multi доставляет уведомление всеми способами и возвращает их ошибки
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, message Message) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// Режимы TLS для SMTP
+const (
+	// Без шифрования, например для локального перехватчика писем (Mailpit)
+	TLSNone = "none"
+	// STARTTLS после подключения, обычно порт 587
+	TLSStartTLS = "starttls"
+	// TLS с самого подключения, обычно порт 465
+	TLSImplicit = "tls"
+)
+
+var ErrInvalidSMTPConfig = errors.New("invalid smtp configuration")
+
+type SMTPConfig struct {
+	Host string
+	Port string
+	// Без имени пользователя авторизация не выполняется
+	Username string
+	Password string
+	// Отправитель, например "Подписки <noreply@example.com>"
+	From string
+	TLS  string
+	// Таймаут отправки письма, по умолчанию 30 секунд
+	Timeout time.Duration
+}
+
+// SMTP отправляет письма по шаблонам. Сообщения без адреса пропускаются.
+type SMTP struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+func NewSMTP(config SMTPConfig) (*SMTP, error) {
+	if config.Host == "" || config.Port == "" {
+		return nil, fmt.Errorf("%w: host and port are required", ErrInvalidSMTPConfig)
+	}
+	if config.TLS != TLSNone && config.TLS != TLSStartTLS && config.TLS != TLSImplicit {
+		return nil, fmt.Errorf("%w: tls must be none, starttls or tls", ErrInvalidSMTPConfig)
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from: %v", ErrInvalidSMTPConfig, err)
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &SMTP{config: config, from: from}, nil
+}
+
+func (s *SMTP) Notify(ctx context.Context, message Message) error {
+	if message.Email == "" {
+		return nil
+	}
+
+	email, err := Render(message)
+	if err != nil {
+		return err
+	}
+
+	data, err := s.build(message.Email, email)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, message.Email, data)
+}
+
+// build собирает письмо multipart/alternative с текстовой и html-версией
+func (s *SMTP) build(to string, email Email) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	var data bytes.Buffer
+	fmt.Fprintf(&data, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&data, "To: %s\r\n", (&mail.Address{Address: to}).String())
+	fmt.Fprintf(&data, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&data, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&data, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), s.config.Host)
+	fmt.Fprintf(&data, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&data, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	data.Write(body.Bytes())
+
+	return data.Bytes(), nil
+}
+
+func (s *SMTP) send(ctx context.Context, to string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	address := net.JoinHostPort(s.config.Host, s.config.Port)
+	tlsConfig := &tls.Config{ServerName: s.config.Host}
+
+	var conn net.Conn
+	var err error
+	if s.config.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.config.TLS == TLSStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"slices"
+	"text/template"
+	"time"
+)
+
+// Языки писем
+const (
+	LocaleRu = "ru"
+	LocaleEn = "en"
+	// Язык по умолчанию, документация проекта на русском
+	DefaultLocale = LocaleRu
+)
+
+var Locales = []string{LocaleRu, LocaleEn}
+
+// Шаблоны писем: templates/<locale>/<kind>.txt задаёт "subject" и "text",
+// templates/<locale>/<kind>.html - "content" для общего layout.html
+//
+//go:embed templates
+var templateFS embed.FS
+
+// Email - письмо, собранное по шаблону
+type Email struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type emailTemplate struct {
+	text *template.Template
+	html *htmlTemplate.Template
+}
+
+var templates = parseTemplates()
+
+func parseTemplates() map[string]map[string]emailTemplate {
+	result := map[string]map[string]emailTemplate{}
+	for _, locale := range Locales {
+		funcs := map[string]any{"date": dateFormatter(locale)}
+
+		result[locale] = map[string]emailTemplate{}
+		for _, kind := range Kinds {
+			textFile := fmt.Sprintf("templates/%s/%s.txt", locale, kind)
+			htmlFile := fmt.Sprintf("templates/%s/%s.html", locale, kind)
+
+			result[locale][kind] = emailTemplate{
+				text: template.Must(template.New(kind).Funcs(funcs).ParseFS(templateFS, textFile)),
+				html: htmlTemplate.Must(htmlTemplate.New(kind).Funcs(funcs).ParseFS(templateFS, "templates/layout.html", textFile, htmlFile)),
+			}
+		}
+	}
+	return result
+}
+
+func dateFormatter(locale string) func(time.Time) string {
+	layout := "02.01.2006"
+	if locale == LocaleEn {
+		layout = "January 2, 2006"
+	}
+	return func(date time.Time) string {
+		return date.Format(layout)
+	}
+}
+
+// Render собирает письмо по шаблону вида и языка сообщения. Неизвестный язык заменяется языком по умолчанию.
+func Render(message Message) (Email, error) {
+	if !slices.Contains(Locales, message.Locale) {
+		message.Locale = DefaultLocale
+	}
+
+	tmpl, ok := templates[message.Locale][message.Kind]
+	if !ok {
+		return Email{}, fmt.Errorf("no template for notification kind %q", message.Kind)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", message); err != nil {
+		return Email{}, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", message); err != nil {
+		return Email{}, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", message); err != nil {
+		return Email{}, err
+	}
+
+	return Email{Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
+
+// Sample возвращает сообщение с примером данных для предпросмотра шаблона
+func Sample(kind string, locale string) Message {
+	message := Message{
+		Kind:         kind,
+		ServiceName:  "Yandex Plus",
+		Date:         time.Now().AddDate(0, 0, 3),
+		Amount:       400,
+		Currency:     "RUB",
+		MonthlyLimit: 1500,
+		Locale:       locale,
+	}
+	if kind == KindBudgetExceeded {
+		message.Amount = 1799
+	}
+	return message
+}
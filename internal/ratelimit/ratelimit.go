@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit - token bucket ёмкостью Requests токенов, который полностью восстанавливается за Per
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Per.String()
+}
+
+// ParseLimit разбирает лимит в формате "100/1m"
+func ParseLimit(s string) (Limit, error) {
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected format N/duration", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+
+	d, err := time.ParseDuration(per)
+	if err != nil {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+
+	// Токен восстанавливается за Per/Requests, меньше наносекунды это время не бывает
+	if n > 0 && d > 0 && d/time.Duration(n) == 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: more than one request per nanosecond", s)
+	}
+
+	return Limit{Requests: n, Per: d}, nil
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Через сколько bucket наполнится полностью
+	Reset time.Duration
+	// Через сколько появится следующий токен, если запрос отклонён
+	RetryAfter time.Duration
+}
+
+// Store хранит состояние bucket'ов. Сейчас есть только MemoryStore,
+// для нескольких реплик можно будет добавить реализацию поверх общего хранилища.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	capacity := float64(limit.Requests)
+	perToken := limit.Per / time.Duration(limit.Requests)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		m.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updated)
+	b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(perToken))
+	b.updated = now
+
+	result := Result{Limit: limit.Requests}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+
+	return result, nil
+}
+
+// Cleanup периодически удаляет bucket'ы, к которым не обращались дольше maxIdle.
+// maxIdle должен быть не меньше самого длинного Limit.Per, иначе лимит можно обойти.
+func (m *MemoryStore) Cleanup(ctx context.Context, interval time.Duration, maxIdle time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			now := m.now()
+			for key, b := range m.buckets {
+				if now.Sub(b.updated) > maxIdle {
+					delete(m.buckets, key)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
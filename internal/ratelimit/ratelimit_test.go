@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{value: "100/1m", want: Limit{Requests: 100, Per: time.Minute}},
+		{value: "0/1m", want: Limit{Requests: 0, Per: time.Minute}},
+		{value: "5/30s", want: Limit{Requests: 5, Per: 30 * time.Second}},
+		{value: "100", wantErr: true},
+		{value: "x/1m", wantErr: true},
+		{value: "100/minute", wantErr: true},
+		{value: "1000/1us", want: Limit{Requests: 1000, Per: time.Microsecond}},
+		{value: "1001/1us", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.value)
+		if (err != nil) != tt.wantErr || !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v, want %+v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	limit := Limit{Requests: 3, Per: 3 * time.Second}
+
+	type step struct {
+		// Сколько прошло с предыдущего запроса
+		after      time.Duration
+		key        string
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst up to capacity",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0},
+				{key: "a", allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "tokens are refilled over time",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0},
+				{after: 500 * time.Millisecond, key: "a", allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+				{after: 500 * time.Millisecond, key: "a", allowed: true, remaining: 0},
+				{after: 10 * time.Second, key: "a", allowed: true, remaining: 2},
+			},
+		},
+		{
+			name: "keys are independent",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0},
+				{key: "b", allowed: true, remaining: 2},
+				{key: "a", allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewMemoryStore()
+		store.now = func() time.Time { return now }
+
+		for i, s := range tt.steps {
+			now = now.Add(s.after)
+			result, err := store.Take(context.Background(), s.key, limit)
+			if err != nil {
+				t.Fatalf("%s: step %d: %v", tt.name, i, err)
+			}
+			if result.Allowed != s.allowed || result.Remaining != s.remaining || result.RetryAfter != s.retryAfter {
+				t.Errorf("%s: step %d: got %+v, want allowed %v, remaining %d, retry after %s",
+					tt.name, i, result, s.allowed, s.remaining, s.retryAfter)
+			}
+			if result.Limit != limit.Requests || result.Reset > limit.Per {
+				t.Errorf("%s: step %d: limit %d, reset %s", tt.name, i, result.Limit, result.Reset)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/sqlc-dev/pqtype"
+)
+
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDelete     = "delete"
+	ActionRestore    = "restore"
+	ActionPause      = "pause"
+	ActionResume     = "resume"
+	ActionCancel     = "cancel"
+	ActionReactivate = "reactivate"
+)
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+// Record пишет запись аудита. q должен быть привязан к транзакции, в которой выполняется само изменение.
+func Record(ctx context.Context, q *db.Queries, action string, subscriptionID int32, before any, after any) error {
+	beforeJson, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJson, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+
+	return q.CreateAuditRecord(ctx, db.CreateAuditRecordParams{
+		TenantID:       tenancy.FromContext(ctx),
+		SubscriptionID: subscriptionID,
+		Actor:          Actor(ctx),
+		RequestID:      middleware.GetReqID(ctx),
+		Action:         action,
+		Before:         beforeJson,
+		After:          afterJson,
+	})
+}
+
+// RecordMany пишет записи аудита действия action по нескольким подпискам одной командой.
+// after[i] - подписка subscriptionIDs[i] после действия.
+func RecordMany(ctx context.Context, q *db.Queries, action string, subscriptionIDs []int32, after []any) error {
+	afters := make([]string, 0, len(after))
+	for _, v := range after {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		afters = append(afters, string(data))
+	}
+
+	return q.CreateAuditRecords(ctx, db.CreateAuditRecordsParams{
+		TenantID:        tenancy.FromContext(ctx),
+		SubscriptionIds: subscriptionIDs,
+		Actor:           Actor(ctx),
+		RequestID:       middleware.GetReqID(ctx),
+		Action:          action,
+		Afters:          afters,
+	})
+}
+
+// Actor возвращает subject вызывающего или "anonymous", если аутентификация выключена
+func Actor(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.Subject
+	}
+	return "anonymous"
+}
+
+func snapshot(v any) (pqtype.NullRawMessage, error) {
+	if v == nil {
+		return pqtype.NullRawMessage{}, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return pqtype.NullRawMessage{}, err
+	}
+	return pqtype.NullRawMessage{RawMessage: data, Valid: true}, nil
+}
+
+func (s *Services) List(ctx context.Context, filter dto.AuditFilter) (*[]dto.AuditRecord, error) {
+	params := db.AuditListParams{
+		TenantID:  tenancy.FromContext(ctx),
+		Actor:     sql.NullString{String: filter.Actor, Valid: filter.Actor != ""},
+		Action:    sql.NullString{String: filter.Action, Valid: filter.Action != ""},
+		RowLimit:  filter.Limit,
+		RowOffset: filter.Offset,
+	}
+	if filter.From != nil {
+		params.FromTime = sql.NullTime{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		params.ToTime = sql.NullTime{Time: *filter.To, Valid: true}
+	}
+
+	var list []db.SubscriptionAudit
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		list, err = q.AuditList(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromSql(list), nil
+}
+
+// History возвращает историю изменений подписки в хронологическом порядке
+func History(ctx context.Context, q *db.Queries, subscriptionID int32) (*[]dto.AuditRecord, error) {
+	list, err := q.SubscriptionHistory(ctx, db.SubscriptionHistoryParams{
+		SubscriptionID: subscriptionID,
+		TenantID:       tenancy.FromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromSql(list), nil
+}
+
+func fromSql(list []db.SubscriptionAudit) *[]dto.AuditRecord {
+	records := []dto.AuditRecord{}
+
+	for _, el := range list {
+		records = append(records, dto.AuditFromSql(el))
+	}
+	return &records
+}
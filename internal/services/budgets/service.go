@@ -0,0 +1,233 @@
+package budgets
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalid   = errors.New("invalid budget")
+	ErrExists    = errors.New("budget already exists")
+	ErrForbidden = errors.New("forbidden")
+)
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+// List возвращает бюджеты пользователя. Пользователь без роли администратора видит только свои бюджеты.
+func (s *Services) List(ctx context.Context, userID uuid.UUID) (*[]dto.Budget, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	budgets := []dto.Budget{}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		list, err := q.UserBudgets(ctx, db.UserBudgetsParams{
+			TenantID: tenancy.FromContext(ctx),
+			UserID:   userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, el := range list {
+			budgets = append(budgets, dto.BudgetFromSql(el))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &budgets, nil
+}
+
+func (s *Services) Create(ctx context.Context, userID uuid.UUID, budget dto.Budget) (*dto.Budget, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	params, err := toParams(budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var created dto.Budget
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		if err := checkScope(ctx, q, params); err != nil {
+			return err
+		}
+
+		params.TenantID = tenancy.FromContext(ctx)
+		params.UserID = userID
+
+		budgetSql, err := q.CreateBudget(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		created = dto.BudgetFromSql(budgetSql)
+		return nil
+	})
+	if err != nil {
+		return nil, uniqueError(err)
+	}
+
+	return &created, nil
+}
+
+func (s *Services) Update(ctx context.Context, userID uuid.UUID, id int32, budget dto.Budget) (*dto.Budget, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	params, err := toParams(budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated dto.Budget
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		if err := checkScope(ctx, q, params); err != nil {
+			return err
+		}
+
+		budgetSql, err := q.UpdateBudget(ctx, db.UpdateBudgetParams{
+			ID:           id,
+			TenantID:     tenancy.FromContext(ctx),
+			UserID:       userID,
+			Category:     params.Category,
+			ServiceID:    params.ServiceID,
+			MonthlyLimit: params.MonthlyLimit,
+			Currency:     params.Currency,
+			Strict:       params.Strict,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated = dto.BudgetFromSql(budgetSql)
+		return nil
+	})
+	if err != nil {
+		return nil, uniqueError(err)
+	}
+
+	return &updated, nil
+}
+
+func (s *Services) Delete(ctx context.Context, userID uuid.UUID, id int32) error {
+	if err := checkUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		deleted, err := q.DeleteBudget(ctx, db.DeleteBudgetParams{
+			ID:       id,
+			TenantID: tenancy.FromContext(ctx),
+			UserID:   userID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// checkUser запрещает пользователю без роли администратора работать с чужими бюджетами
+func checkUser(ctx context.Context, userID uuid.UUID) error {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func toParams(budget dto.Budget) (db.CreateBudgetParams, error) {
+	params := db.CreateBudgetParams{
+		MonthlyLimit: int32(budget.MonthlyLimit),
+		Currency:     strings.ToUpper(budget.Currency),
+		Strict:       budget.Strict,
+	}
+
+	if budget.MonthlyLimit < 0 {
+		return params, fmt.Errorf("%w: monthly_limit must not be negative", ErrInvalid)
+	}
+
+	if budget.Category != nil && *budget.Category != "" {
+		params.Category = sql.NullString{String: *budget.Category, Valid: true}
+	}
+	if budget.ServiceID != nil {
+		params.ServiceID = sql.NullInt32{Int32: *budget.ServiceID, Valid: true}
+	}
+	if params.Category.Valid && params.ServiceID.Valid {
+		return params, fmt.Errorf("%w: budget can't have both category and service_id", ErrInvalid)
+	}
+
+	if params.Currency == "" {
+		params.Currency = dto.DEFAULT_CURRENCY
+	}
+	if !rates.ValidCurrency(params.Currency) {
+		return params, rates.ErrInvalidCurrency
+	}
+
+	return params, nil
+}
+
+// checkScope проверяет, что категория и сервис бюджета существуют
+func checkScope(ctx context.Context, q *db.Queries, params db.CreateBudgetParams) error {
+	if params.Category.Valid {
+		_, err := q.GetCategory(ctx, params.Category.String)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: unknown category %q", ErrInvalid, params.Category.String)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if params.ServiceID.Valid {
+		_, err := q.GetService(ctx, db.GetServiceParams{
+			ID:       params.ServiceID.Int32,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: service_id %d not found in catalog", ErrInvalid, params.ServiceID.Int32)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func uniqueError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrExists
+	}
+	return err
+}
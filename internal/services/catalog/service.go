@@ -0,0 +1,282 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalid = errors.New("invalid service")
+	ErrExists  = errors.New("service already exists")
+	ErrInUse   = errors.New("service is used by budgets")
+)
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+func (s *Services) List(ctx context.Context) (*[]dto.Service, error) {
+	services := []dto.Service{}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		list, err := q.ServicesList(ctx, tenancy.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		for _, el := range list {
+			services = append(services, dto.ServiceFromSql(el))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &services, nil
+}
+
+func (s *Services) Get(ctx context.Context, id int32) (*dto.Service, error) {
+	var service dto.Service
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		serviceSql, err := q.GetService(ctx, db.GetServiceParams{
+			ID:       id,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		service = dto.ServiceFromSql(serviceSql)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+func (s *Services) Create(ctx context.Context, service dto.Service) (*dto.Service, error) {
+	params, err := toParams(service)
+	if err != nil {
+		return nil, err
+	}
+
+	var created dto.Service
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		if err := checkCategory(ctx, q, params.Category); err != nil {
+			return err
+		}
+
+		params.TenantID = tenancy.FromContext(ctx)
+
+		serviceSql, err := q.CreateService(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		created = dto.ServiceFromSql(serviceSql)
+		return nil
+	})
+	if err != nil {
+		return nil, uniqueError(err)
+	}
+
+	return &created, nil
+}
+
+// Update изменяет сервис. При переименовании меняется и service_name привязанных подписок.
+func (s *Services) Update(ctx context.Context, id int32, service dto.Service) (*dto.Service, error) {
+	params, err := toParams(service)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated dto.Service
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		if err := checkCategory(ctx, q, params.Category); err != nil {
+			return err
+		}
+
+		serviceSql, err := q.UpdateService(ctx, db.UpdateServiceParams{
+			ID:           id,
+			TenantID:     tenancy.FromContext(ctx),
+			Name:         params.Name,
+			Aliases:      params.Aliases,
+			Category:     params.Category,
+			DefaultPrice: params.DefaultPrice,
+			Currency:     params.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.RenameServiceSubscriptions(ctx, db.RenameServiceSubscriptionsParams{
+			ServiceID:   sql.NullInt32{Int32: id, Valid: true},
+			TenantID:    serviceSql.TenantID,
+			ServiceName: serviceSql.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated = dto.ServiceFromSql(serviceSql)
+		return nil
+	})
+	if err != nil {
+		return nil, uniqueError(err)
+	}
+
+	return &updated, nil
+}
+
+// Delete удаляет сервис из каталога. Подписки сохраняют название, но теряют привязку.
+func (s *Services) Delete(ctx context.Context, id int32) error {
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		deleted, err := q.DeleteService(ctx, db.DeleteServiceParams{
+			ID:       id,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return inUseError(err)
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// Link привязывает к каталогу подписки без service_id и возвращает названия, которые не удалось сопоставить
+func (s *Services) Link(ctx context.Context) (*dto.ServiceLinkResult, error) {
+	result := dto.ServiceLinkResult{Unmatched: []dto.UnmatchedService{}}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		result.Linked, err = q.LinkServices(ctx, tenancy.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		unmatched, err := q.UnmatchedServiceNames(ctx, tenancy.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		for _, el := range unmatched {
+			result.Unmatched = append(result.Unmatched, dto.UnmatchedService{
+				ServiceName:   el.ServiceName,
+				Subscriptions: el.Subscriptions,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Resolve ищет сервис по названию или псевдониму без учёта регистра
+func Resolve(ctx context.Context, q *db.Queries, name string) (*db.Service, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+
+	service, err := q.ResolveService(ctx, db.ResolveServiceParams{
+		TenantID: tenancy.FromContext(ctx),
+		Name:     strings.TrimSpace(name),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+func toParams(service dto.Service) (db.CreateServiceParams, error) {
+	params := db.CreateServiceParams{
+		Name:     strings.TrimSpace(service.Name),
+		Aliases:  []string{},
+		Currency: strings.ToUpper(service.Currency),
+	}
+
+	if params.Name == "" {
+		return params, fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+
+	for _, alias := range service.Aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			params.Aliases = append(params.Aliases, alias)
+		}
+	}
+
+	if service.Category != nil && *service.Category != "" {
+		params.Category = sql.NullString{String: *service.Category, Valid: true}
+	}
+
+	if service.DefaultPrice != nil {
+		if *service.DefaultPrice < 0 {
+			return params, fmt.Errorf("%w: default_price must not be negative", ErrInvalid)
+		}
+		params.DefaultPrice = sql.NullInt32{Int32: int32(*service.DefaultPrice), Valid: true}
+	}
+
+	if params.Currency == "" {
+		params.Currency = dto.DEFAULT_CURRENCY
+	}
+	if !rates.ValidCurrency(params.Currency) {
+		return params, rates.ErrInvalidCurrency
+	}
+
+	return params, nil
+}
+
+func checkCategory(ctx context.Context, q *db.Queries, category sql.NullString) error {
+	if !category.Valid {
+		return nil
+	}
+
+	_, err := q.GetCategory(ctx, category.String)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalid, category.String)
+	}
+	return err
+}
+
+func uniqueError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrExists
+	}
+	return err
+}
+
+// inUseError - удаление сервиса, на который есть бюджеты (budgets.service_id ON DELETE RESTRICT)
+func inUseError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return ErrInUse
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package categories
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+type Services struct {
+	queries *db.Queries
+}
+
+func NewService(queries *db.Queries) *Services {
+	return &Services{
+		queries: queries,
+	}
+}
+
+func (s *Services) List(ctx context.Context) (*[]dto.Category, error) {
+	list, err := s.queries.CategoriesList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []dto.Category{}
+	for _, el := range list {
+		categories = append(categories, dto.CategoryFromSql(el))
+	}
+	return &categories, nil
+}
+
+// Expand возвращает категорию вместе со всеми дочерними. Пустой slug - без ограничения.
+func Expand(ctx context.Context, q *db.Queries, slug string) ([]string, error) {
+	if slug == "" {
+		return []string{}, nil
+	}
+
+	list, err := q.CategoriesList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string][]string{}
+	known := false
+	for _, el := range list {
+		if el.Slug == slug {
+			known = true
+		}
+		if el.ParentSlug.Valid {
+			children[el.ParentSlug.String] = append(children[el.ParentSlug.String], el.Slug)
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: unknown category %q", dto.ErrInvalid, slug)
+	}
+
+	result := []string{slug}
+	for i := 0; i < len(result); i++ {
+		result = append(result, children[result[i]]...)
+	}
+	return result, nil
+}
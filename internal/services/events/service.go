@@ -0,0 +1,319 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/webhooks"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// Канал NOTIFY, в который webhooks.Publish отправляет id событий
+const channel = "subscription_events"
+
+// Сколько событий может ждать отправки клиенту. Клиент, который не успевает их читать,
+// отключается и может продолжить с Last-Event-ID.
+const subscriberBuffer = 64
+
+var (
+	ErrInvalid   = errors.New("invalid events filter")
+	ErrForbidden = errors.New("forbidden")
+)
+
+type Config struct {
+	// Сколько последних событий хранится для продолжения по Last-Event-ID
+	BufferSize int
+}
+
+// event - событие в буфере с данными для фильтрации
+type event struct {
+	dto.Event
+	tenantID string
+	userIDs  []string
+}
+
+// Services получает события всех реплик через LISTEN/NOTIFY и раздаёт их подписчикам
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+	config  Config
+
+	mu     sync.Mutex
+	buffer []event
+	// В буфере есть все события с id больше since
+	since int64
+	// Наибольший полученный id, с него догружаются события после переподключения
+	last        int64
+	subscribers map[*Subscription]struct{}
+}
+
+func NewService(conn *sql.DB, queries *db.Queries, config Config) *Services {
+	return &Services{
+		conn:        conn,
+		queries:     queries,
+		config:      config,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscription - подписка клиента на поток событий
+type Subscription struct {
+	events   chan dto.Event
+	tenantID string
+	userID   string
+	types    []string
+	// События из буфера после Last-Event-ID
+	Replay []dto.Event
+	// Часть событий после Last-Event-ID уже вытеснена из буфера, клиенту нужно перечитать состояние
+	Reset bool
+}
+
+// Events возвращает канал новых событий. Канал закрывается, если клиент не успевает читать события.
+func (s *Subscription) Events() <-chan dto.Event {
+	return s.events
+}
+
+func (s *Subscription) match(e event) bool {
+	if s.tenantID != tenancy.All && e.tenantID != s.tenantID {
+		return false
+	}
+	if s.userID != "" && !slices.Contains(e.userIDs, s.userID) {
+		return false
+	}
+	return len(s.types) == 0 || slices.Contains(s.types, e.Type)
+}
+
+// Run слушает NOTIFY subscription_events, пока не отменён ctx
+func (s *Services) Run(ctx context.Context, dsn string) {
+	ctx = tenancy.WithTenant(ctx, tenancy.All)
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Warn().Err(err).Msg("events listener")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		log.Error().Err(err).Msg("can't listen for subscription events")
+		return
+	}
+	if err := s.load(ctx); err != nil {
+		log.Error().Err(err).Msg("can't load recent subscription events")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-listener.Notify:
+			// nil приходит после переподключения: уведомления за время разрыва потеряны
+			if notification == nil {
+				if err := s.catchUp(ctx); err != nil {
+					log.Error().Err(err).Msg("can't load missed subscription events")
+				}
+				continue
+			}
+
+			id, err := strconv.ParseInt(notification.Extra, 10, 64)
+			if err != nil {
+				log.Warn().Str("payload", notification.Extra).Msg("unexpected subscription event notification")
+				continue
+			}
+			if err := s.fetch(ctx, id); err != nil {
+				log.Error().Err(err).Int64("event_id", id).Msg("can't load subscription event")
+			}
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+// Subscribe подписывает клиента на события тенанта из контекста. Пользователь без роли администратора
+// получает только события своих подписок. lastEventID - id последнего полученного события, если клиент продолжает поток.
+func (s *Services) Subscribe(ctx context.Context, userID string, types []string, lastEventID string) (*Subscription, error) {
+	if userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, fmt.Errorf("%w: user_id must be a UUID", ErrInvalid)
+		}
+	}
+	if scopedID, scoped := auth.Scoped(ctx); scoped {
+		if userID != "" && userID != scopedID.String() {
+			return nil, ErrForbidden
+		}
+		userID = scopedID.String()
+	}
+
+	for _, eventType := range types {
+		if !slices.Contains(webhooks.Events, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalid, eventType)
+		}
+	}
+
+	var lastID int64
+	if lastEventID != "" {
+		var err error
+		if lastID, err = strconv.ParseInt(lastEventID, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: Last-Event-ID must be an event id", ErrInvalid)
+		}
+	}
+
+	sub := &Subscription{
+		events:   make(chan dto.Event, subscriberBuffer),
+		tenantID: tenancy.FromContext(ctx),
+		userID:   userID,
+		types:    types,
+		Replay:   []dto.Event{},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lastEventID != "" {
+		sub.Reset = lastID < s.since
+		for _, e := range s.buffer {
+			if e.ID > lastID && sub.match(e) {
+				sub.Replay = append(sub.Replay, e.Event)
+			}
+		}
+	}
+	s.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+func (s *Services) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// load заполняет буфер последними событиями при запуске
+func (s *Services) load(ctx context.Context) error {
+	var list []db.WebhookEvent
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		list, err = q.LatestWebhookEvents(ctx, int32(s.config.BufferSize))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(list) == s.config.BufferSize && len(list) > 0 {
+		s.mu.Lock()
+		s.since = list[len(list)-1].ID - 1
+		s.mu.Unlock()
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		s.add(list[i])
+	}
+	return nil
+}
+
+// catchUp догружает события, пропущенные за время разрыва соединения
+func (s *Services) catchUp(ctx context.Context) error {
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		list, err := q.WebhookEventsAfter(ctx, db.WebhookEventsAfterParams{
+			AfterID:   last,
+			MaxEvents: int32(s.config.BufferSize),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, el := range list {
+			s.add(el)
+		}
+		return nil
+	})
+}
+
+func (s *Services) fetch(ctx context.Context, id int64) error {
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		eventSql, err := q.GetWebhookEvent(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		s.add(eventSql)
+		return nil
+	})
+}
+
+// add кладёт событие в буфер по порядку id и отправляет подходящим подписчикам
+func (s *Services) add(eventSql db.WebhookEvent) {
+	e := event{
+		Event:    dto.EventFromSql(eventSql),
+		tenantID: eventSql.TenantID,
+		userIDs:  participants(eventSql.Payload),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := sort.Search(len(s.buffer), func(i int) bool { return s.buffer[i].ID >= e.ID })
+	if (i < len(s.buffer) && s.buffer[i].ID == e.ID) || e.ID <= s.since {
+		return
+	}
+	s.buffer = slices.Insert(s.buffer, i, e)
+	if len(s.buffer) > s.config.BufferSize {
+		s.since = s.buffer[0].ID
+		s.buffer = slices.Delete(s.buffer, 0, 1)
+	}
+	s.last = max(s.last, e.ID)
+
+	for sub := range s.subscribers {
+		if !sub.match(e) {
+			continue
+		}
+
+		select {
+		case sub.events <- e.Event:
+		default:
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// participants возвращает плательщика и участников подписки из данных события
+func participants(payload json.RawMessage) []string {
+	var sub struct {
+		UserID  string `json:"user_id"`
+		Members []struct {
+			UserID string `json:"user_id"`
+		} `json:"members"`
+	}
+	if err := json.Unmarshal(payload, &sub); err != nil {
+		return nil
+	}
+
+	users := []string{sub.UserID}
+	for _, member := range sub.Members {
+		users = append(users, member.UserID)
+	}
+	return users
+}
@@ -0,0 +1,161 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/notify"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalid   = errors.New("invalid notification settings")
+	ErrForbidden = errors.New("forbidden")
+)
+
+type Config struct {
+	// За сколько дней напоминать о событиях подписок по умолчанию
+	ReminderDays int
+}
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+	config  Config
+}
+
+func NewService(conn *sql.DB, queries *db.Queries, config Config) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+		config:  config,
+	}
+}
+
+// Settings возвращает настройки уведомлений пользователя или настройки по умолчанию
+func (s *Services) Settings(ctx context.Context, userID uuid.UUID) (*dto.NotificationSettings, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	settings := dto.NotificationSettings{
+		UserID:            userID.String(),
+		ReminderDays:      s.config.ReminderDays,
+		Locale:            notify.DefaultLocale,
+		EmailReminders:    true,
+		EmailBudgetAlerts: true,
+	}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		settingsSql, err := q.GetNotificationSettings(ctx, db.GetNotificationSettingsParams{
+			TenantID: tenancy.FromContext(ctx),
+			UserID:   userID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		settings = dto.NotificationSettingsFromSql(settingsSql)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (s *Services) Save(ctx context.Context, userID uuid.UUID, settings dto.NotificationSettings) (*dto.NotificationSettings, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if settings.ReminderDays < 0 {
+		return nil, fmt.Errorf("%w: reminder_days must not be negative", ErrInvalid)
+	}
+
+	if settings.Locale == "" {
+		settings.Locale = notify.DefaultLocale
+	}
+	if !slices.Contains(notify.Locales, settings.Locale) {
+		return nil, fmt.Errorf("%w: locale must be one of %s", ErrInvalid, strings.Join(notify.Locales, ", "))
+	}
+
+	email := sql.NullString{}
+	if settings.Email != nil && *settings.Email != "" {
+		address, err := mail.ParseAddress(*settings.Email)
+		if err != nil || address.Name != "" {
+			return nil, fmt.Errorf("%w: email must be a plain email address", ErrInvalid)
+		}
+		email = sql.NullString{String: address.Address, Valid: true}
+	}
+
+	var saved dto.NotificationSettings
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		settingsSql, err := q.SaveNotificationSettings(ctx, db.SaveNotificationSettingsParams{
+			TenantID:          tenancy.FromContext(ctx),
+			UserID:            userID,
+			ReminderDays:      int32(settings.ReminderDays),
+			Email:             email,
+			Locale:            settings.Locale,
+			EmailReminders:    settings.EmailReminders,
+			EmailBudgetAlerts: settings.EmailBudgetAlerts,
+		})
+		if err != nil {
+			return err
+		}
+
+		saved = dto.NotificationSettingsFromSql(settingsSql)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// Preview собирает письмо вида kind на языке locale с примером данных
+func (s *Services) Preview(kind string, locale string) (*dto.EmailPreview, error) {
+	if !slices.Contains(notify.Kinds, kind) {
+		return nil, fmt.Errorf("%w: kind must be one of %s", ErrInvalid, strings.Join(notify.Kinds, ", "))
+	}
+	if locale == "" {
+		locale = notify.DefaultLocale
+	}
+	if !slices.Contains(notify.Locales, locale) {
+		return nil, fmt.Errorf("%w: locale must be one of %s", ErrInvalid, strings.Join(notify.Locales, ", "))
+	}
+
+	email, err := notify.Render(notify.Sample(kind, locale))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.EmailPreview{
+		Kind:    kind,
+		Locale:  locale,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	}, nil
+}
+
+// checkUser запрещает пользователю без роли администратора работать с чужими настройками
+func checkUser(ctx context.Context, userID uuid.UUID) error {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package rates
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+// Base - валюта, к которой привязаны все курсы
+const Base = dto.DEFAULT_CURRENCY
+
+var (
+	ErrInvalidCurrency = errors.New("currency must be an ISO 4217 code")
+	ErrNoRate          = errors.New("no exchange rate")
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func ValidCurrency(currency string) bool {
+	return currencyPattern.MatchString(currency)
+}
+
+// Round округляет сумму до копеек
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Converter пересчитывает суммы между валютами по курсу, действовавшему в месяц списания
+type Converter struct {
+	rates map[string][]dto.ExchangeRate
+	used  map[string]dto.ExchangeRate
+}
+
+// Load загружает курсы для указанных валют
+func Load(ctx context.Context, q *db.Queries, currencies []string) (*Converter, error) {
+	list, err := q.ExchangeRatesForCurrencies(ctx, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Converter{
+		rates: map[string][]dto.ExchangeRate{},
+		used:  map[string]dto.ExchangeRate{},
+	}
+
+	for _, el := range list {
+		rate, err := dto.ExchangeRateFromSql(el)
+		if err != nil {
+			return nil, err
+		}
+		c.rates[rate.Currency] = append(c.rates[rate.Currency], rate)
+	}
+
+	return c, nil
+}
+
+// Convert пересчитывает amount из from в to. Используется последний курс, известный на конец месяца month.
+func (c *Converter) Convert(amount float64, from string, to string, month time.Time) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, err := c.rate(from, month)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := c.rate(to, month)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * fromRate / toRate, nil
+}
+
+// Used возвращает курсы, которые применялись при пересчёте
+func (c *Converter) Used() []dto.ExchangeRate {
+	used := []dto.ExchangeRate{}
+	for _, rate := range c.used {
+		used = append(used, rate)
+	}
+
+	slices.SortFunc(used, func(a, b dto.ExchangeRate) int {
+		return cmp.Or(strings.Compare(a.Currency, b.Currency), strings.Compare(a.Date, b.Date))
+	})
+	return used
+}
+
+func (c *Converter) rate(currency string, month time.Time) (float64, error) {
+	if currency == Base {
+		return 1, nil
+	}
+
+	monthEnd := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Format(dto.DATE_FORMAT)
+
+	list := c.rates[currency]
+	i, _ := slices.BinarySearchFunc(list, monthEnd, func(rate dto.ExchangeRate, target string) int {
+		if rate.Date <= target {
+			return -1
+		}
+		return 1
+	})
+	if i == 0 {
+		return 0, fmt.Errorf("%w for %s on %s", ErrNoRate, currency, month.Format("01-2006"))
+	}
+
+	rate := list[i-1]
+	c.used[rate.Currency+rate.Date] = rate
+	return rate.Rate, nil
+}
@@ -0,0 +1,137 @@
+package rates
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+func (s *Services) List(ctx context.Context, currency string) (*[]dto.ExchangeRate, error) {
+	list, err := s.queries.ExchangeRatesList(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []dto.ExchangeRate{}
+
+	for _, el := range list {
+		rate, err := dto.ExchangeRateFromSql(el)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rate)
+	}
+	return &result, nil
+}
+
+// Save сохраняет курсы одной транзакцией, существующие курсы на ту же дату перезаписываются
+func (s *Services) Save(ctx context.Context, list []dto.ExchangeRate) error {
+	params := []db.UpsertExchangeRateParams{}
+
+	for i, rate := range list {
+		if !ValidCurrency(rate.Currency) || rate.Currency == Base {
+			return fmt.Errorf("%w: row %d: %w", ErrInvalidRate, i+1, ErrInvalidCurrency)
+		}
+		date, err := time.Parse(dto.DATE_FORMAT, rate.Date)
+		if err != nil {
+			return fmt.Errorf("%w: row %d: %w", ErrInvalidRate, i+1, err)
+		}
+		if rate.Rate <= 0 {
+			return fmt.Errorf("%w: row %d: rate must be positive", ErrInvalidRate, i+1)
+		}
+		if rate.Source == "" {
+			return fmt.Errorf("%w: row %d: source is required", ErrInvalidRate, i+1)
+		}
+
+		params = append(params, db.UpsertExchangeRateParams{
+			Currency: rate.Currency,
+			RateDate: date,
+			Rate:     strconv.FormatFloat(rate.Rate, 'f', -1, 64),
+			Source:   rate.Source,
+		})
+	}
+
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := s.queries.WithTx(tx)
+	for _, p := range params {
+		if err := q.UpsertExchangeRate(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ParseCSV читает курсы из CSV с заголовком currency,date,rate,source
+func ParseCSV(r io.Reader) ([]dto.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRate, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"currency", "date", "rate", "source"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidRate, name)
+		}
+	}
+
+	list := []dto.ExchangeRate{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRate, err)
+		}
+
+		rate, err := strconv.ParseFloat(record[columns["rate"]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidRate, line, err)
+		}
+
+		list = append(list, dto.ExchangeRate{
+			Currency: strings.ToUpper(record[columns["currency"]]),
+			Date:     record[columns["date"]],
+			Rate:     rate,
+			Source:   record[columns["source"]],
+		})
+	}
+
+	return list, nil
+}
@@ -0,0 +1,267 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/categories"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrOverBudget = errors.New("over budget")
+
+// BudgetStatus сравнивает стоимость в месяц действующих подписок пользователя с каждым его бюджетом
+func (s *Services) BudgetStatus(ctx context.Context, userID uuid.UUID) (*[]dto.BudgetStatus, error) {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return nil, ErrForbidden
+	}
+
+	var statuses []dto.BudgetStatus
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		statuses, err = budgetStatuses(ctx, q, userID, s.config.DatePrecision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &statuses, nil
+}
+
+// budgetStatuses считает прогноз расходов пользователя в месяц по каждому его бюджету.
+// Учитываются подписки, действующие сегодня, по цене на сегодня; в совместных - доля пользователя.
+func budgetStatuses(ctx context.Context, q *db.Queries, userID uuid.UUID, precision string) ([]dto.BudgetStatus, error) {
+	statuses := []dto.BudgetStatus{}
+
+	budgets, err := q.UserBudgets(ctx, db.UserBudgetsParams{
+		TenantID: tenancy.FromContext(ctx),
+		UserID:   userID,
+	})
+	if err != nil || len(budgets) == 0 {
+		return statuses, err
+	}
+
+	subs, err := q.UserSubscriptions(ctx, db.UserSubscriptionsParams{
+		UserID:   userID,
+		TenantID: tenancy.FromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := loadDetails(ctx, q, subs, precision)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := []string{}
+	for _, budget := range budgets {
+		currencies = append(currencies, budget.Currency)
+	}
+	for _, sub := range subs {
+		currencies = append(currencies, sub.Currency)
+	}
+	converter, err := rates.Load(ctx, q, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	now := today()
+	for _, budget := range budgets {
+		var scope []string
+		if budget.Category.Valid {
+			scope, err = categories.Expand(ctx, q, budget.Category.String)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		spend := 0.0
+		for _, sub := range subs {
+			if !inBudget(sub, budget, scope) || status(sub, d.pauses[sub.ID], now, precision) != dto.StatusActive {
+				continue
+			}
+
+			monthly := monthlyCost(sub, d, now) * shares(sub, d.members[sub.ID])[userID]
+			amount, err := converter.Convert(monthly, sub.Currency, budget.Currency, now)
+			if err != nil {
+				return nil, err
+			}
+			spend += amount
+		}
+
+		statuses = append(statuses, dto.BudgetStatus{
+			Budget:    dto.BudgetFromSql(budget),
+			Spend:     rates.Round(spend),
+			Remaining: rates.Round(float64(budget.MonthlyLimit) - spend),
+			Exceeded:  spend > float64(budget.MonthlyLimit),
+		})
+	}
+
+	return statuses, nil
+}
+
+// inBudget сообщает, относится ли подписка к бюджету. scope - категория бюджета вместе с дочерними.
+func inBudget(sub db.Subscription, budget db.Budget, scope []string) bool {
+	if budget.ServiceID.Valid {
+		return sub.ServiceID == budget.ServiceID
+	}
+	if budget.Category.Valid {
+		for _, category := range scope {
+			if sub.Category.Valid && sub.Category.String == category {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// budgetSpends возвращает прогноз расходов по бюджетам пользователей по id бюджета.
+// Если для расчёта не хватает курса валюты, бюджеты пользователя не проверяются.
+func budgetSpends(ctx context.Context, q *db.Queries, users []uuid.UUID, precision string) (map[int32]dto.BudgetStatus, error) {
+	spends := map[int32]dto.BudgetStatus{}
+	for _, userID := range users {
+		statuses, err := budgetStatuses(ctx, q, userID, precision)
+		if errors.Is(err, rates.ErrNoRate) {
+			log.Warn().Err(err).Str("user_id", userID.String()).Msg("can't check budgets")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, status := range statuses {
+			spends[status.ID] = status
+		}
+	}
+	return spends, nil
+}
+
+// checkBudgets сравнивает бюджеты пользователей до (before) и после изменения подписки sub.
+// Превышение бюджета, к которому привело изменение, записывается в budget_alerts,
+// а строгий бюджет запрещает изменение.
+func checkBudgets(ctx context.Context, q *db.Queries, sub db.Subscription, users []uuid.UUID, before map[int32]dto.BudgetStatus, precision string) error {
+	after, err := budgetSpends(ctx, q, users, precision)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range exceededBudgets(before, after) {
+		if status.Strict {
+			return overBudget(status)
+		}
+		if err := alertBudget(ctx, q, sub, status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exceededBudgets возвращает бюджеты, превышенные после изменения (after), расход по которым изменение увеличило
+func exceededBudgets(before map[int32]dto.BudgetStatus, after map[int32]dto.BudgetStatus) []dto.BudgetStatus {
+	exceeded := []dto.BudgetStatus{}
+	for id, status := range after {
+		if status.Exceeded && status.Spend > before[id].Spend {
+			exceeded = append(exceeded, status)
+		}
+	}
+	return exceeded
+}
+
+func overBudget(status dto.BudgetStatus) error {
+	return fmt.Errorf("%w: monthly spend %.2f %s exceeds budget %d of user %s", ErrOverBudget, status.Spend, status.Currency, status.MonthlyLimit, status.UserID)
+}
+
+// alertBudget записывает в budget_alerts превышение бюджета, к которому привела подписка sub,
+// и ставит уведомление пользователю в очередь
+func alertBudget(ctx context.Context, q *db.Queries, sub db.Subscription, status dto.BudgetStatus) error {
+	userID, _ := uuid.Parse(status.UserID)
+	_, err := q.CreateBudgetAlert(ctx, db.CreateBudgetAlertParams{
+		TenantID:       sub.TenantID,
+		BudgetID:       status.ID,
+		UserID:         userID,
+		SubscriptionID: sql.NullInt32{Int32: sub.ID, Valid: true},
+		Spend:          status.Spend,
+		MonthlyLimit:   int32(status.MonthlyLimit),
+		Currency:       status.Currency,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.EnqueueBudgetAlert(ctx, db.EnqueueBudgetAlertParams{
+		TenantID:       sub.TenantID,
+		SubscriptionID: sub.ID,
+		UserID:         userID,
+		EventDate:      today(),
+		ServiceName:    sub.ServiceName,
+		Amount:         int32(math.Round(status.Spend)),
+		Currency:       status.Currency,
+		MonthlyLimit:   sql.NullInt32{Int32: int32(status.MonthlyLimit), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Warn().Int32("budget_id", status.ID).Str("user_id", status.UserID).Float64("spend", status.Spend).Msg("budget exceeded")
+	return nil
+}
+
+// budgetScope возвращает проверку, относится ли подписка к бюджету status: категория бюджета
+// раскрывается вместе с дочерними один раз
+func budgetScope(ctx context.Context, q *db.Queries, status dto.BudgetStatus) (func(db.Subscription) bool, error) {
+	budget := db.Budget{}
+	if status.ServiceID != nil {
+		budget.ServiceID = sql.NullInt32{Int32: *status.ServiceID, Valid: true}
+	}
+
+	var scope []string
+	if status.Category != nil {
+		budget.Category = sql.NullString{String: *status.Category, Valid: true}
+		var err error
+		if scope, err = categories.Expand(ctx, q, *status.Category); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(sub db.Subscription) bool {
+		return inBudget(sub, budget, scope)
+	}, nil
+}
+
+// participants возвращает плательщиков и участников подписки до и после изменения без повторов
+func participants(payers []uuid.UUID, members []dto.Member, current []db.SubscriptionMember) []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	users := []uuid.UUID{}
+	add := func(userID uuid.UUID) {
+		if !seen[userID] {
+			seen[userID] = true
+			users = append(users, userID)
+		}
+	}
+
+	for _, payer := range payers {
+		add(payer)
+	}
+	for _, member := range members {
+		if userID, err := uuid.Parse(member.UserID); err == nil {
+			add(userID)
+		}
+	}
+	for _, member := range current {
+		add(member.UserID)
+	}
+	return users
+}
@@ -0,0 +1,223 @@
+package subscriptions
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/billing"
+	"github.com/feproldo/effective-mobile/internal/calendar"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+)
+
+// Час дня, в который календарь напоминает о списании
+const calendarAlarmHour = 9
+
+// CreateCalendarToken создаёт секретный токен календаря пользователя. Прежний токен перестаёт действовать.
+func (s *Services) CreateCalendarToken(ctx context.Context, userID uuid.UUID) (*dto.CalendarToken, error) {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return nil, ErrForbidden
+	}
+
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	token := "cal_" + hex.EncodeToString(key)
+
+	var saved db.CalendarToken
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		saved, err = q.SaveCalendarToken(ctx, db.SaveCalendarTokenParams{
+			TenantID:  tenancy.FromContext(ctx),
+			UserID:    userID,
+			TokenHash: tokenHash(token),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CalendarToken{
+		Token:     token,
+		URL:       fmt.Sprintf("/users/%s/calendar.ics?token=%s", userID, token),
+		CreatedAt: saved.CreatedAt,
+	}, nil
+}
+
+// RevokeCalendarToken удаляет токен календаря пользователя
+func (s *Services) RevokeCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return ErrForbidden
+	}
+
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		deleted, err := q.DeleteCalendarToken(ctx, db.DeleteCalendarTokenParams{
+			TenantID: tenancy.FromContext(ctx),
+			UserID:   userID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// Calendar возвращает события списаний по подпискам пользователя для календаря. Запрос не аутентифицирован:
+// тенант и пользователь определяются по токену, поэтому токен ищется во всех тенантах.
+// Каждая подписка с будущими списаниями - одно повторяющееся событие с ближайшего списания.
+func (s *Services) Calendar(ctx context.Context, userID uuid.UUID, token string) ([]calendar.Event, error) {
+	if token == "" {
+		return nil, ErrForbidden
+	}
+
+	var owner db.CalendarToken
+	err := tenancy.InTx(tenancy.WithTenant(ctx, tenancy.All), s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		owner, err = q.CalendarTokenByHash(ctx, tokenHash(token))
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) || err == nil && owner.UserID != userID {
+		return nil, ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = tenancy.WithTenant(ctx, owner.TenantID)
+
+	var subs []db.Subscription
+	var d details
+	days := s.config.ReminderDays
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		subs, err = q.UserSubscriptions(ctx, db.UserSubscriptionsParams{
+			UserID:   userID,
+			TenantID: owner.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if d, err = loadDetails(ctx, q, subs, s.config.DatePrecision); err != nil {
+			return err
+		}
+
+		settings, err := q.GetNotificationSettings(ctx, db.GetNotificationSettingsParams{
+			TenantID: owner.TenantID,
+			UserID:   userID,
+		})
+		if err == nil {
+			days = int(settings.ReminderDays)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alarm := time.Duration(calendarAlarmHour-24*days) * time.Hour
+	result := []calendar.Event{}
+	for _, sub := range subs {
+		event, ok := calendarEvent(sub, d, today())
+		if !ok {
+			continue
+		}
+		event.UID = fmt.Sprintf("subscription-%d@%s", sub.ID, owner.TenantID)
+		event.Alarm = &alarm
+		result = append(result, event)
+	}
+
+	return result, nil
+}
+
+// calendarEvent описывает списания подписки начиная с ближайшего не раньше from. Списания на паузах
+// исключаются, незавершённая пауза заканчивает повторения. false - будущих списаний нет.
+func calendarEvent(sub db.Subscription, d details, from time.Time) (calendar.Event, bool) {
+	plan := planFromSql(sub, d, billing.PrecisionDay)
+	next, ok := plan.NextCharge(from)
+	if !ok {
+		return calendar.Event{}, false
+	}
+
+	until := plan.End
+	var pausedUntil time.Time
+	for _, pause := range plan.Pauses {
+		if pause.End == nil {
+			if last := pause.Start.AddDate(0, 0, -1); until == nil || last.Before(*until) {
+				until = &last
+			}
+		} else if pause.End.After(pausedUntil) {
+			pausedUntil = *pause.End
+		}
+	}
+
+	var except []time.Time
+	for n := 0; ; n++ {
+		date := plan.Period.Nth(plan.Anchor, n)
+		if !date.Before(pausedUntil) || until != nil && date.After(*until) {
+			break
+		}
+		if !date.Before(next) && plan.Paused(date) {
+			except = append(except, date)
+		}
+	}
+
+	event := calendar.Event{
+		Summary:     fmt.Sprintf("%s: %d %s", sub.ServiceName, plan.PriceAt(next), sub.Currency),
+		Description: priceChanges(plan, next, until),
+		Start:       next,
+		Rule:        calendar.Rule(plan.Period, plan.Anchor, until),
+		Except:      except,
+	}
+	if sub.Category.Valid {
+		event.Categories = []string{sub.Category.String}
+	}
+	return event, true
+}
+
+// priceChanges описывает изменения цены после from из-за окончания ценовых фаз и истории цен,
+// так как в названии события - цена ближайшего списания
+func priceChanges(plan billing.Plan, from time.Time, until *time.Time) string {
+	var dates []time.Time
+	for _, phase := range plan.Phases {
+		dates = append(dates, phase.Start, phase.End.AddDate(0, 0, 1))
+	}
+	for _, point := range plan.Prices {
+		dates = append(dates, point.From)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var lines []string
+	price := plan.PriceAt(from)
+	for _, date := range dates {
+		if !date.After(from) || until != nil && date.After(*until) || plan.PriceAt(date) == price {
+			continue
+		}
+		price = plan.PriceAt(date)
+		lines = append(lines, fmt.Sprintf("Price from %s: %d %s", date.Format(dto.DATE_FORMAT), price, plan.Currency))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func tokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
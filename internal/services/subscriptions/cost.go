@@ -0,0 +1,183 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/google/uuid"
+)
+
+// planFromSql собирает условия оплаты подписки. При помесячной точности
+// подписка действует до конца месяца end_date, при подневной - до самого end_date.
+func planFromSql(sub db.Subscription, d details, precision string) billing.Plan {
+	period, err := billing.ParsePeriod(sub.BillingPeriod, int(sub.BillingMonths.Int32))
+	if err != nil {
+		period, _ = billing.ParsePeriod(billing.Monthly, 0)
+	}
+
+	plan := billing.Plan{
+		Start:    sub.StartDate,
+		Anchor:   sub.StartDate,
+		Period:   period,
+		Price:    int(sub.Price),
+		Currency: sub.Currency,
+	}
+
+	if sub.AnchorDate.Valid {
+		plan.Anchor = sub.AnchorDate.Time
+	}
+
+	if end, ok := endDate(sub, precision); ok {
+		plan.End = &end
+	}
+
+	for _, price := range d.prices[sub.ID] {
+		plan.Prices = append(plan.Prices, billing.PricePoint{
+			From:  price.EffectiveFrom,
+			Price: int(price.Price),
+		})
+	}
+
+	for _, phase := range d.phases[sub.ID] {
+		plan.Phases = append(plan.Phases, billing.Phase{
+			Kind:  phase.Kind,
+			Price: int(phase.Price),
+			Start: phase.StartDate,
+			End:   phase.EndDate,
+		})
+	}
+
+	for _, pause := range d.pauses[sub.ID] {
+		billingPause := billing.Pause{Start: pause.PausedFrom}
+		if pause.ResumedAt.Valid {
+			billingPause.End = &pause.ResumedAt.Time
+		}
+		plan.Pauses = append(plan.Pauses, billingPause)
+	}
+
+	return plan
+}
+
+// endDate возвращает последний день действия подписки: при помесячной точности - конец месяца end_date,
+// при подневной - сам end_date. false - подписка бессрочная
+func endDate(sub db.Subscription, precision string) (time.Time, bool) {
+	if !sub.EndDate.Valid {
+		return time.Time{}, false
+	}
+	if precision != billing.PrecisionDay {
+		return billing.MonthEnd(sub.EndDate.Time), true
+	}
+	return sub.EndDate.Time, true
+}
+
+// monthlyCost возвращает стоимость подписки в месяц по цене на дату date в валюте подписки
+func monthlyCost(sub db.Subscription, d details, date time.Time) float64 {
+	plan := planFromSql(sub, d, billing.PrecisionDay)
+	return plan.Period.MonthlyEquivalent(float64(plan.PriceAt(date)))
+}
+
+// sumWindow возвращает границы периода подсчёта. Без end_date считается по текущий день.
+// end_date в формате MM-YYYY включает весь месяц.
+func sumWindow(filter dto.SumFilter, now time.Time) (time.Time, time.Time, error) {
+	var from time.Time
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if filter.StartDate != "" {
+		start, _, err := dto.ParseDate(filter.StartDate)
+		if err != nil {
+			return from, to, fmt.Errorf("%w: start_date: %w", dto.ErrInvalid, err)
+		}
+		from = start
+	}
+
+	if filter.EndDate != "" {
+		end, wholeMonth, err := dto.ParseDate(filter.EndDate)
+		if err != nil {
+			return from, to, fmt.Errorf("%w: end_date: %w", dto.ErrInvalid, err)
+		}
+		to = end
+		if wholeMonth {
+			to = billing.MonthEnd(end)
+		}
+	}
+
+	return from, to, nil
+}
+
+// costing - параметры подсчёта стоимости списаний
+type costing struct {
+	converter *rates.Converter
+	currency  string
+	from      time.Time
+	to        time.Time
+	precision string
+	details   details
+	// Пользователь, чья доля в совместных подписках учитывается. Без него - полная стоимость
+	user uuid.NullUUID
+}
+
+// prepare загружает дочерние записи подписок и курсы их валют
+func (c *costing) prepare(ctx context.Context, q *db.Queries, subs []db.Subscription) error {
+	var err error
+	c.details, err = loadDetails(ctx, q, subs, c.precision)
+	if err != nil {
+		return err
+	}
+
+	currencies := []string{c.currency}
+	for _, sub := range subs {
+		currencies = append(currencies, sub.Currency)
+	}
+	c.converter, err = rates.Load(ctx, q, currencies)
+	return err
+}
+
+// charges возвращает списания подписки в [from, to], пересчитанные в валюту currency.
+// При подневной точности каждый период оплаты учитывается пропорционально дням внутри [from, to].
+// Если задан user, от каждого списания берётся его доля.
+func (c costing) charges(sub db.Subscription) ([]billing.Charge, error) {
+	plan := planFromSql(sub, c.details, c.precision)
+	charges := plan.Charges(c.from, c.to)
+	if c.precision == billing.PrecisionDay {
+		charges = plan.ProratedCharges(c.from, c.to)
+	}
+
+	share := 1.0
+	if c.user.Valid {
+		share = shares(sub, c.details.members[sub.ID])[c.user.UUID]
+	}
+
+	result := []billing.Charge{}
+	for _, charge := range charges {
+		// Бесплатные списания (пробный период) не требуют курса
+		if charge.Amount == 0 {
+			continue
+		}
+		amount, err := c.converter.Convert(charge.Amount, charge.Currency, c.currency, charge.Date)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, billing.Charge{Date: charge.Date, Amount: amount * share, Currency: c.currency})
+	}
+
+	return result, nil
+}
+
+// cost считает стоимость списаний подписки в [from, to] в валюте currency
+func (c costing) cost(sub db.Subscription) (float64, error) {
+	charges, err := c.charges(sub)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0.0
+	for _, charge := range charges {
+		total += charge.Amount
+	}
+	return total, nil
+}
@@ -0,0 +1,95 @@
+package subscriptions
+
+import (
+	"context"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+// details - дочерние записи подписок по id подписки
+type details struct {
+	phases  map[int32][]db.SubscriptionPhase
+	prices  map[int32][]db.SubscriptionPrice
+	tags    map[int32][]string
+	pauses  map[int32][]db.SubscriptionPause
+	members map[int32][]db.SubscriptionMember
+	// Точность дат, с которой считается окончание подписки, см. endDate
+	precision string
+}
+
+// loadDetails загружает ценовые фазы, историю цен, теги, паузы и участников подписок
+func loadDetails(ctx context.Context, q *db.Queries, subs []db.Subscription, precision string) (details, error) {
+	d := details{precision: precision}
+
+	phases, err := loadPhases(ctx, q, subs)
+	if err != nil {
+		return d, err
+	}
+	d.phases = phases
+
+	prices, err := loadPrices(ctx, q, subs)
+	if err != nil {
+		return d, err
+	}
+	d.prices = prices
+
+	tags, err := loadTags(ctx, q, subs)
+	if err != nil {
+		return d, err
+	}
+	d.tags = tags
+
+	pauses, err := loadPauses(ctx, q, subs)
+	if err != nil {
+		return d, err
+	}
+	d.pauses = pauses
+
+	members, err := loadMembers(ctx, q, subs)
+	if err != nil {
+		return d, err
+	}
+	d.members = members
+
+	return d, nil
+}
+
+// withDetails переводит подписки в dto вместе с их ценовыми фазами и тегами
+func withDetails(ctx context.Context, q *db.Queries, subs []db.Subscription, precision string) ([]dto.Subscription, error) {
+	d, err := loadDetails(ctx, q, subs, precision)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.Subscription
+	for _, sub := range subs {
+		result = append(result, subscriptionFromSql(sub, d))
+	}
+	return result, nil
+}
+
+func subscriptionFromSql(sub db.Subscription, d details) dto.Subscription {
+	result := dto.FromSql(sub)
+	for _, phase := range d.phases[sub.ID] {
+		result.Phases = append(result.Phases, dto.PhaseFromSql(phase))
+	}
+	result.Tags = d.tags[sub.ID]
+	for _, pause := range d.pauses[sub.ID] {
+		result.Pauses = append(result.Pauses, dto.PauseFromSql(pause))
+	}
+	for _, member := range d.members[sub.ID] {
+		result.Members = append(result.Members, dto.MemberFromSql(member))
+	}
+	result.Status = status(sub, d.pauses[sub.ID], today(), d.precision)
+	return result
+}
+
+// reload возвращает подписку в dto после изменения её дочерних записей
+func reload(ctx context.Context, q *db.Queries, sub db.Subscription, precision string) (dto.Subscription, error) {
+	subs, err := withDetails(ctx, q, []db.Subscription{sub}, precision)
+	if err != nil {
+		return dto.Subscription{}, err
+	}
+	return subs[0], nil
+}
@@ -0,0 +1,51 @@
+package subscriptions
+
+import (
+	"context"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/audit"
+	"github.com/feproldo/effective-mobile/internal/services/webhooks"
+)
+
+// eventTypes - события вебхуков для действий аудита
+var eventTypes = map[string]string{
+	audit.ActionCreate:     webhooks.EventCreated,
+	audit.ActionUpdate:     webhooks.EventUpdated,
+	audit.ActionDelete:     webhooks.EventDeleted,
+	audit.ActionRestore:    webhooks.EventRestored,
+	audit.ActionPause:      webhooks.EventPaused,
+	audit.ActionResume:     webhooks.EventResumed,
+	audit.ActionCancel:     webhooks.EventCancelled,
+	audit.ActionReactivate: webhooks.EventReactivated,
+}
+
+// record пишет запись аудита и событие для вебхуков в транзакции изменения.
+// В событие попадает подписка после изменения, для удаления - до него.
+func record(ctx context.Context, q *db.Queries, action string, subscriptionID int32, before any, after any) error {
+	if err := audit.Record(ctx, q, action, subscriptionID, before, after); err != nil {
+		return err
+	}
+
+	data := after
+	if after == nil {
+		data = before
+	}
+	return webhooks.Publish(ctx, q, eventTypes[action], subscriptionID, data)
+}
+
+// recordCreated пишет аудит и события вебхуков о создании подписок subs одной командой на каждую таблицу
+func recordCreated(ctx context.Context, q *db.Queries, subs []dto.Subscription) error {
+	ids := make([]int32, 0, len(subs))
+	data := make([]any, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+		data = append(data, sub)
+	}
+
+	if err := audit.RecordMany(ctx, q, audit.ActionCreate, ids, data); err != nil {
+		return err
+	}
+	return webhooks.PublishMany(ctx, q, webhooks.EventCreated, ids, data)
+}
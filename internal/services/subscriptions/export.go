@@ -0,0 +1,95 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/categories"
+	"github.com/feproldo/effective-mobile/internal/services/tags"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+)
+
+// Сколько подписок читается из курсора за раз
+const exportBatch = 500
+
+// Export передаёт в write подписки с теми же фильтрами, что и List. Подписки читаются из курсора
+// партиями по exportBatch, поэтому выгрузка всей таблицы не загружает её в память.
+func (s *Services) Export(ctx context.Context, filter dto.ListFilter, write func(dto.Subscription) error) error {
+	if filter.Status != "" && !dto.ValidStatus(filter.Status) {
+		return fmt.Errorf("%w: unknown status %q", dto.ErrInvalid, filter.Status)
+	}
+
+	params := db.DeclareSubscriptionsExportParams{
+		TenantID:       tenancy.FromContext(ctx),
+		IncludeDeleted: filter.IncludeDeleted,
+		Tags:           tags.Keys(filter.Tags),
+	}
+
+	if userID, scoped := auth.Scoped(ctx); scoped {
+		if filter.IncludeDeleted {
+			return ErrForbidden
+		}
+		params.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+
+	return s.export(ctx, params, filter, write)
+}
+
+// ExportByUser передаёт в write подписки пользователя, как GetByUserId, читая их из курсора
+func (s *Services) ExportByUser(ctx context.Context, userID uuid.UUID, write func(dto.Subscription) error) error {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return ErrForbidden
+	}
+
+	params := db.DeclareSubscriptionsExportParams{
+		TenantID:      tenancy.FromContext(ctx),
+		ParticipantID: uuid.NullUUID{UUID: userID, Valid: true},
+		Tags:          []string{},
+	}
+
+	return s.export(ctx, params, dto.ListFilter{}, write)
+}
+
+func (s *Services) export(ctx context.Context, params db.DeclareSubscriptionsExportParams, filter dto.ListFilter, write func(dto.Subscription) error) error {
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		params.Categories, err = categories.Expand(ctx, q, filter.Category)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeclareSubscriptionsExport(ctx, params); err != nil {
+			return err
+		}
+
+		for {
+			batch, err := q.FetchSubscriptionsExport(ctx, exportBatch)
+			if err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+
+			d, err := loadDetails(ctx, q, batch, s.config.DatePrecision)
+			if err != nil {
+				return err
+			}
+
+			for _, sub := range batch {
+				el := subscriptionFromSql(sub, d)
+				// Состояние вычисляется вместе с паузами, поэтому фильтр по нему применяется после выборки, как в List
+				if filter.Status != "" && el.Status != filter.Status {
+					continue
+				}
+				if err := write(el); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
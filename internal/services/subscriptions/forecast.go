@@ -0,0 +1,118 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+)
+
+// Ограничение горизонта прогноза в месяцах
+const maxForecastMonths = 36
+
+// Forecast прогнозирует стоимость списаний на months месяцев начиная с текущего с теми же фильтрами и данными,
+// что и Sum: учитываются end_date, периодичность, паузы, ценовые фазы и запланированные изменения цены.
+// groupBy (user или service) добавляет к каждому месяцу разбивку по пользователям или сервисам.
+func (s *Services) Forecast(ctx context.Context, filter dto.SumFilter, months int, groupBy string) (*dto.Forecast, error) {
+	if months < 1 || months > maxForecastMonths {
+		return nil, fmt.Errorf("%w: months must be between 1 and %d", dto.ErrInvalid, maxForecastMonths)
+	}
+	switch groupBy {
+	case "", GroupByUser, GroupByService:
+	default:
+		return nil, fmt.Errorf("%w: group_by must be user or service", dto.ErrInvalid)
+	}
+
+	now := today()
+	start := billing.MonthStart(now)
+	end := billing.MonthEnd(billing.AddMonths(start, months-1))
+	filter.StartDate = start.Format(dto.DATE_FORMAT)
+	filter.EndDate = end.Format(dto.DATE_FORMAT)
+
+	list, calc, err := s.selectForSum(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := dto.Forecast{
+		GroupBy:  groupBy,
+		Currency: calc.currency,
+		Months:   []dto.ForecastMonth{},
+	}
+
+	groups := make([]map[string]float64, months)
+	for i := range months {
+		groups[i] = map[string]float64{}
+		forecast.Months = append(forecast.Months, dto.ForecastMonth{Month: billing.AddMonths(start, i).Format("2006-01")})
+	}
+
+	mrr := 0.0
+	for _, el := range list {
+		// Списания считаются отдельно за каждый месяц: при подневной точности период оплаты, начатый
+		// до месяца, делится между месяцами пропорционально дням
+		for i := range months {
+			month := calc
+			month.from = billing.AddMonths(start, i)
+			month.to = billing.MonthEnd(month.from)
+			charges, err := month.charges(el)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, charge := range charges {
+				forecast.Months[i].Sum += charge.Amount
+				forecast.Total += charge.Amount
+
+				switch groupBy {
+				case GroupByUser:
+					for key, share := range calc.userShares(el) {
+						groups[i][key] += charge.Amount * share
+					}
+				case GroupByService:
+					groups[i][el.ServiceName] += charge.Amount
+				}
+			}
+		}
+
+		if status(el, calc.details.pauses[el.ID], now, calc.precision) != dto.StatusActive {
+			continue
+		}
+		monthly := monthlyCost(el, calc.details, now)
+		if calc.user.Valid {
+			monthly *= shares(el, calc.details.members[el.ID])[calc.user.UUID]
+		}
+		amount, err := calc.converter.Convert(monthly, el.Currency, calc.currency, now)
+		if err != nil {
+			return nil, err
+		}
+		mrr += amount
+	}
+
+	for i := range forecast.Months {
+		forecast.Months[i].Sum = rates.Round(forecast.Months[i].Sum)
+		if groupBy == "" {
+			continue
+		}
+
+		forecast.Months[i].Groups = []dto.ReportRow{}
+		for key, sum := range groups[i] {
+			forecast.Months[i].Groups = append(forecast.Months[i].Groups, dto.ReportRow{Key: key, Sum: rates.Round(sum)})
+		}
+		sort.Slice(forecast.Months[i].Groups, func(a, b int) bool {
+			rows := forecast.Months[i].Groups
+			if rows[a].Sum != rows[b].Sum {
+				return rows[a].Sum > rows[b].Sum
+			}
+			return rows[a].Key < rows[b].Key
+		})
+	}
+
+	forecast.Total = rates.Round(forecast.Total)
+	forecast.MonthlyRecurring = rates.Round(mrr)
+	forecast.AnnualRunRate = rates.Round(mrr * 12)
+
+	return &forecast, nil
+}
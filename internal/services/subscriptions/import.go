@@ -0,0 +1,584 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/services/tags"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const maxImportRows = 10000
+
+// errRollback откатывает транзакцию импорта при dry_run или ошибках в режиме all_or_nothing
+var errRollback = errors.New("rollback import")
+
+// importRow - строка CSV, прошедшая проверки
+type importRow struct {
+	line   int
+	sub    dto.Subscription
+	sqlSub *db.Subscription
+}
+
+// dateFormat - формат дат CSV. Без дня дата означает месяц, как MM-YYYY в API
+type dateFormat struct {
+	layout string
+	day    bool
+}
+
+// Import добавляет подписки из CSV в одной транзакции. Каждая строка проходит те же проверки, что и POST /subscriptions,
+// и сохраняется с историей цены, аудитом и событиями. Сначала проверяются все строки, затем корректные
+// добавляются пакетом, см. importRows, поэтому в ответе есть ошибки всех строк, а не только первой.
+func (s *Services) Import(ctx context.Context, r io.Reader, options dto.ImportOptions) (*dto.ImportResult, error) {
+	if options.Mode == "" {
+		options.Mode = dto.ImportAllOrNothing
+	}
+	if options.Mode != dto.ImportAllOrNothing && options.Mode != dto.ImportBestEffort {
+		return nil, fmt.Errorf("%w: mode must be %s or %s", dto.ErrInvalid, dto.ImportAllOrNothing, dto.ImportBestEffort)
+	}
+	if options.Delimiter == 0 {
+		options.Delimiter = ','
+	}
+	if options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' {
+		return nil, fmt.Errorf("%w: invalid delimiter", dto.ErrInvalid)
+	}
+	for field := range options.Mapping {
+		if !slices.Contains(dto.ImportColumns, field) {
+			return nil, fmt.Errorf("%w: unknown field %q in mapping", dto.ErrInvalid, field)
+		}
+	}
+
+	format, err := parseDateFormat(options.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = options.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: empty csv", dto.ErrInvalid)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", dto.ErrInvalid, err)
+	}
+
+	columns, err := importColumns(header, options.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.ImportResult{
+		Mode:   options.Mode,
+		DryRun: options.DryRun,
+		IDs:    []int32{},
+		Errors: []dto.ImportRowError{},
+	}
+	addError := func(line int, err error) {
+		result.Errors = append(result.Errors, dto.ImportRowError{Row: line, Error: err.Error()})
+	}
+
+	rows := []importRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Total++
+			addError(parseErr.StartLine, parseErr.Err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result.Total++
+		if result.Total > maxImportRows {
+			return nil, fmt.Errorf("%w: csv must have at most %d rows", dto.ErrInvalid, maxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, err := prepareRow(ctx, line, record, columns, format)
+		if err != nil {
+			addError(line, err)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		resolver := newResolver(q)
+		valid := []importRow{}
+		for _, row := range rows {
+			err := resolver.resolve(ctx, row.sqlSub, true)
+			if rowError(err) {
+				addError(row.line, err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			valid = append(valid, row)
+		}
+
+		ids, err := importRows(ctx, q, valid, s.config.DatePrecision, addError)
+		if err != nil {
+			return err
+		}
+		result.IDs = ids
+
+		if options.DryRun || (options.Mode == dto.ImportAllOrNothing && len(result.Errors) > 0) {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+
+	slices.SortFunc(result.Errors, func(a, b dto.ImportRowError) int { return a.Row - b.Row })
+	result.Committed = err == nil
+	result.Imported = len(result.IDs)
+	result.Failed = len(result.Errors)
+	if !result.Committed {
+		result.IDs = []int32{}
+	}
+
+	return &result, nil
+}
+
+// prepareRow разбирает строку CSV и проверяет подписку так же, как POST /subscriptions, до обращения к базе
+func prepareRow(ctx context.Context, line int, record []string, columns map[string]int, format dateFormat) (importRow, error) {
+	sub, err := rowSubscription(record, columns, format)
+	if err != nil {
+		return importRow{}, err
+	}
+
+	sqlSub, err := prepareCreate(ctx, &sub)
+	if err != nil {
+		return importRow{}, err
+	}
+
+	if sub.Tags != nil {
+		if sub.Tags, err = tags.Normalize(sub.Tags); err != nil {
+			return importRow{}, fmt.Errorf("%w: tags: %w", dto.ErrInvalid, err)
+		}
+	}
+
+	return importRow{line: line, sub: sub, sqlSub: sqlSub}, nil
+}
+
+// importRows добавляет подписки строк одной командой вместе с историей цен и тегами, затем один раз
+// проверяет бюджеты их пользователей и пишет аудит и события. Если вставка нарушила ограничения базы
+// или превысила строгий бюджет, она откатывается до точки сохранения, виновные строки получают ошибку,
+// и остальные добавляются заново. Возвращает id добавленных подписок в порядке строк.
+func importRows(ctx context.Context, q *db.Queries, rows []importRow, precision string, addError func(int, error)) ([]int32, error) {
+	payers := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		payers = append(payers, row.sqlSub.UserID)
+	}
+	users := participants(payers, nil, nil)
+
+	before, err := budgetSpends(ctx, q, users, precision)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(rows) > 0 {
+		if err := q.SavepointImportRow(ctx); err != nil {
+			return nil, err
+		}
+
+		created, err := insertRows(ctx, q, rows)
+		if rowError(err) {
+			if err := rollbackRows(ctx, q); err != nil {
+				return nil, err
+			}
+			remaining, probeErr := probeRows(ctx, q, rows, addError)
+			if probeErr != nil {
+				return nil, probeErr
+			}
+			if len(remaining) == len(rows) {
+				// Каждая строка добавляется и по отдельности: ошибка возникает только в пакете,
+				// поэтому она записывается всем его строкам
+				for _, row := range rows {
+					addError(row.line, err)
+				}
+				return []int32{}, nil
+			}
+			rows = remaining
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := budgetSpends(ctx, q, users, precision)
+		if err != nil {
+			return nil, err
+		}
+
+		rejected := map[int]error{}
+		alerts := map[int][]dto.BudgetStatus{}
+		for _, status := range exceededBudgets(before, after) {
+			inScope, err := budgetScope(ctx, q, status)
+			if err != nil {
+				return nil, err
+			}
+
+			last := -1
+			for i, sub := range created {
+				if sub.UserID.String() != status.UserID || !inScope(sub) {
+					continue
+				}
+				if status.Strict {
+					rejected[i] = overBudget(status)
+				}
+				last = i
+			}
+			if !status.Strict && last >= 0 {
+				alerts[last] = append(alerts[last], status)
+			}
+		}
+
+		if len(rejected) > 0 {
+			if err := rollbackRows(ctx, q); err != nil {
+				return nil, err
+			}
+			remaining := []importRow{}
+			for i, row := range rows {
+				if err, ok := rejected[i]; ok {
+					addError(row.line, err)
+					continue
+				}
+				remaining = append(remaining, row)
+			}
+			rows = remaining
+			continue
+		}
+
+		if err := q.ReleaseImportRow(ctx); err != nil {
+			return nil, err
+		}
+
+		// Превышение нестрогого бюджета записывается на последнюю подписку импорта, которая к нему относится
+		for i, statuses := range alerts {
+			for _, status := range statuses {
+				if err := alertBudget(ctx, q, created[i], status); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		subs, err := withDetails(ctx, q, created, precision)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordCreated(ctx, q, subs); err != nil {
+			return nil, err
+		}
+
+		ids := make([]int32, 0, len(created))
+		for _, sub := range created {
+			ids = append(ids, sub.ID)
+		}
+		return ids, nil
+	}
+
+	return []int32{}, nil
+}
+
+// insertRows добавляет подписки строк, их историю цен и теги. Подписки возвращаются в порядке строк.
+func insertRows(ctx context.Context, q *db.Queries, rows []importRow) ([]db.Subscription, error) {
+	params := db.ImportSubscriptionsParams{TenantID: tenancy.FromContext(ctx)}
+	for _, row := range rows {
+		sub := row.sqlSub
+		params.ServiceNames = append(params.ServiceNames, sub.ServiceName)
+		params.Prices = append(params.Prices, sub.Price)
+		params.UserIds = append(params.UserIds, sub.UserID.String())
+		params.StartDates = append(params.StartDates, sub.StartDate.Format(time.DateOnly))
+		params.EndDates = append(params.EndDates, nullDate(sub.EndDate))
+		params.Currencies = append(params.Currencies, sub.Currency)
+		params.BillingPeriods = append(params.BillingPeriods, sub.BillingPeriod)
+		params.BillingMonths = append(params.BillingMonths, sub.BillingMonths.Int32)
+		params.AnchorDates = append(params.AnchorDates, nullDate(sub.AnchorDate))
+		params.ServiceIds = append(params.ServiceIds, sub.ServiceID.Int32)
+		params.Categories = append(params.Categories, sub.Category.String)
+		params.SplitRules = append(params.SplitRules, sub.SplitRule)
+	}
+
+	created, err := q.ImportSubscriptions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(created) != len(rows) {
+		return nil, fmt.Errorf("imported %d subscriptions of %d rows", len(created), len(rows))
+	}
+
+	ids := make([]int32, 0, len(created))
+	for _, sub := range created {
+		ids = append(ids, sub.ID)
+	}
+	if err := q.SaveImportedPrices(ctx, ids); err != nil {
+		return nil, err
+	}
+
+	// Каждый тег создаётся один раз на весь импорт
+	tagIDs := map[string]int32{}
+	pairs := db.AddSubscriptionTagsParams{TenantID: tenancy.FromContext(ctx)}
+	for i, row := range rows {
+		for _, name := range row.sub.Tags {
+			key := strings.ToLower(name)
+			if _, ok := tagIDs[key]; !ok {
+				tag, err := q.UpsertTag(ctx, db.UpsertTagParams{
+					TenantID: pairs.TenantID,
+					Name:     name,
+				})
+				if err != nil {
+					return nil, err
+				}
+				tagIDs[key] = tag.ID
+			}
+			pairs.SubscriptionIds = append(pairs.SubscriptionIds, created[i].ID)
+			pairs.TagIds = append(pairs.TagIds, tagIDs[key])
+		}
+	}
+	if len(pairs.SubscriptionIds) > 0 {
+		if err := q.AddSubscriptionTags(ctx, pairs); err != nil {
+			return nil, err
+		}
+	}
+
+	return created, nil
+}
+
+// probeRows ищет строки, которые база не принимает: каждая строка пробно добавляется с историей цены
+// и тегами и откатывается до точки сохранения. Возвращает остальные строки.
+func probeRows(ctx context.Context, q *db.Queries, rows []importRow, addError func(int, error)) ([]importRow, error) {
+	remaining := []importRow{}
+	for _, row := range rows {
+		if err := q.SavepointImportRow(ctx); err != nil {
+			return nil, err
+		}
+
+		_, err := insertRows(ctx, q, []importRow{row})
+		if err != nil && !rowError(err) {
+			return nil, err
+		}
+		if rollbackErr := rollbackRows(ctx, q); rollbackErr != nil {
+			return nil, rollbackErr
+		}
+
+		if err != nil {
+			addError(row.line, err)
+			continue
+		}
+		remaining = append(remaining, row)
+	}
+	return remaining, nil
+}
+
+// rollbackRows откатывает изменения до точки сохранения импорта и удаляет её
+func rollbackRows(ctx context.Context, q *db.Queries) error {
+	if err := q.RollbackImportRow(ctx); err != nil {
+		return err
+	}
+	return q.ReleaseImportRow(ctx)
+}
+
+func nullDate(date sql.NullTime) string {
+	if !date.Valid {
+		return ""
+	}
+	return date.Time.Format(time.DateOnly)
+}
+
+// rowError - ошибка данных строки, а не сбой импорта
+func rowError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// 22 - некорректные данные, 23 - нарушение ограничений
+		return pqErr.Code.Class() == "22" || pqErr.Code.Class() == "23"
+	}
+
+	return errors.Is(err, dto.ErrInvalid) ||
+		errors.Is(err, rates.ErrInvalidCurrency) ||
+		errors.Is(err, ErrForbidden) ||
+		errors.Is(err, ErrOverBudget)
+}
+
+// importColumns сопоставляет поля подписки с номерами колонок CSV
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns := map[string]int{}
+	for _, field := range dto.ImportColumns {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+
+		i := slices.IndexFunc(header, func(column string) bool {
+			return strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name))
+		})
+		if i >= 0 {
+			columns[field] = i
+		} else if mapped {
+			return nil, fmt.Errorf("%w: column %q not found", dto.ErrInvalid, name)
+		}
+	}
+
+	for _, field := range []string{"price", "start_date"} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("%w: column for %s is required", dto.ErrInvalid, field)
+		}
+	}
+	_, hasName := columns["service_name"]
+	_, hasID := columns["service_id"]
+	if !hasName && !hasID {
+		return nil, fmt.Errorf("%w: column for service_name or service_id is required", dto.ErrInvalid)
+	}
+
+	return columns, nil
+}
+
+// rowSubscription разбирает строку CSV в подписку
+func rowSubscription(record []string, columns map[string]int, format dateFormat) (dto.Subscription, error) {
+	get := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	optional := func(field string) *string {
+		if value := get(field); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	sub := dto.Subscription{
+		ServiceName:   get("service_name"),
+		Currency:      get("currency"),
+		UserID:        get("user_id"),
+		Category:      optional("category"),
+		BillingPeriod: get("billing_period"),
+	}
+
+	if value := get("service_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return sub, fmt.Errorf("%w: service_id must be a number", dto.ErrInvalid)
+		}
+		serviceID := int32(id)
+		sub.ServiceID = &serviceID
+	}
+
+	price, err := strconv.Atoi(get("price"))
+	if err != nil {
+		return sub, fmt.Errorf("%w: price must be a number", dto.ErrInvalid)
+	}
+	sub.Price = price
+
+	if sub.UserID != "" {
+		if _, err := uuid.Parse(sub.UserID); err != nil {
+			return sub, fmt.Errorf("%w: user_id must be a UUID", dto.ErrInvalid)
+		}
+	}
+
+	if sub.StartDate, err = format.convert("start_date", get("start_date")); err != nil {
+		return sub, err
+	}
+	if value := get("end_date"); value != "" {
+		endDate, err := format.convert("end_date", value)
+		if err != nil {
+			return sub, err
+		}
+		sub.EndDate = &endDate
+	}
+	if value := get("anchor_date"); value != "" {
+		anchorDate, err := format.convert("anchor_date", value)
+		if err != nil {
+			return sub, err
+		}
+		sub.AnchorDate = &anchorDate
+	}
+
+	if value := get("billing_months"); value != "" {
+		months, err := strconv.Atoi(value)
+		if err != nil {
+			return sub, fmt.Errorf("%w: billing_months must be a number", dto.ErrInvalid)
+		}
+		sub.BillingMonths = &months
+	}
+
+	if value := get("tags"); value != "" {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				sub.Tags = append(sub.Tags, tag)
+			}
+		}
+	}
+
+	return sub, nil
+}
+
+// parseDateFormat переводит формат из YYYY, MM и DD в формат Go
+func parseDateFormat(format string) (dateFormat, error) {
+	if format == "" {
+		return dateFormat{}, nil
+	}
+	if !strings.Contains(format, "YYYY") || !strings.Contains(format, "MM") {
+		return dateFormat{}, fmt.Errorf("%w: date_format must contain YYYY and MM", dto.ErrInvalid)
+	}
+
+	layout := strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02").Replace(format)
+	if strings.ContainsAny(layout, "YMD") {
+		return dateFormat{}, fmt.Errorf("%w: date_format may contain only YYYY, MM, DD and separators", dto.ErrInvalid)
+	}
+
+	return dateFormat{layout: layout, day: strings.Contains(format, "DD")}, nil
+}
+
+// convert переводит дату из формата CSV в формат API
+func (f dateFormat) convert(field string, value string) (string, error) {
+	if f.layout == "" || value == "" {
+		return value, nil
+	}
+
+	date, err := time.Parse(f.layout, value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s %q does not match date_format", dto.ErrInvalid, field, value)
+	}
+
+	if f.day {
+		return date.Format(time.DateOnly), nil
+	}
+	return date.Format(dto.TIME_FORMAT), nil
+}
@@ -0,0 +1,143 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/audit"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+)
+
+// Cancel отменяет подписку. Без Immediately подписка действует до конца текущего периода оплаты
+// и может быть возобновлена (Reactivate), с Immediately - завершается вчерашним днём.
+// Отменить можно только действующую или приостановленную подписку, отменённую - только сразу.
+func (s *Services) Cancel(ctx context.Context, id int32, req dto.CancelRequest) (*dto.Subscription, error) {
+	var cancelled dto.Subscription
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		sub, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		before, err := reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		switch before.Status {
+		case dto.StatusActive, dto.StatusPaused:
+		case dto.StatusCancelled:
+			if !req.Immediately {
+				return fmt.Errorf("%w: subscription is already cancelled", ErrConflict)
+			}
+		default:
+			return fmt.Errorf("%w: can't cancel %s subscription", ErrConflict, before.Status)
+		}
+
+		now := today()
+		end := now.AddDate(0, 0, -1)
+		if !req.Immediately {
+			end, err = periodEnd(ctx, q, *sub)
+			if err != nil {
+				return err
+			}
+		}
+		if end.Before(sub.StartDate) {
+			end = sub.StartDate
+		}
+		// Отмена не продлевает подписку дальше её end_date
+		if sub.EndDate.Valid && sub.EndDate.Time.Before(end) {
+			end = sub.EndDate.Time
+		}
+
+		after, err := q.CancelSubscription(ctx, db.CancelSubscriptionParams{
+			ID:       id,
+			TenantID: sub.TenantID,
+			EndDate:  sql.NullTime{Time: end, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		cancelled, err = reload(ctx, q, after, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionCancel, id, before, cancelled)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cancelled, nil
+}
+
+// Reactivate отменяет отмену подписки, которая ещё не завершилась, и восстанавливает её end_date
+func (s *Services) Reactivate(ctx context.Context, id int32) (*dto.Subscription, error) {
+	var reactivated dto.Subscription
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		sub, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		before, err := reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		if before.Status != dto.StatusCancelled {
+			return fmt.Errorf("%w: can't reactivate %s subscription", ErrConflict, before.Status)
+		}
+
+		after, err := q.ReactivateSubscription(ctx, db.ReactivateSubscriptionParams{
+			ID:       id,
+			TenantID: sub.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+
+		reactivated, err = reload(ctx, q, after, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionReactivate, id, before, reactivated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reactivated, nil
+}
+
+// sameDate сравнивает необязательные даты
+func sameDate(a, b sql.NullTime) bool {
+	if a.Valid != b.Valid {
+		return false
+	}
+	return !a.Valid || a.Time.Equal(b.Time)
+}
+
+// periodEnd возвращает последний день текущего периода оплаты подписки - день перед следующим списанием
+func periodEnd(ctx context.Context, q *db.Queries, sub db.Subscription) (time.Time, error) {
+	d, err := loadDetails(ctx, q, []db.Subscription{sub}, billing.PrecisionDay)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Паузы и end_date не сдвигают границы периода оплаты
+	plan := planFromSql(sub, d, billing.PrecisionDay)
+	plan.Pauses = nil
+	plan.End = nil
+
+	next, _ := plan.NextCharge(today().AddDate(0, 0, 1))
+	return next.AddDate(0, 0, -1), nil
+}
@@ -0,0 +1,165 @@
+package subscriptions
+
+import (
+	"context"
+	"sort"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/google/uuid"
+)
+
+// Settlement считает, сколько участники совместных подписок должны их плательщикам за период.
+// Принимает те же фильтры, что и Sum; с user_id остаются только долги этого пользователя и перед ним.
+func (s *Services) Settlement(ctx context.Context, filter dto.SumFilter) (*dto.Settlement, error) {
+	list, calc, err := s.selectForSum(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Долги считаются от полной стоимости подписки
+	user := calc.user
+	calc.user = uuid.NullUUID{}
+
+	type pair struct{ from, to uuid.UUID }
+	owed := map[pair]float64{}
+
+	for _, el := range list {
+		members := calc.details.members[el.ID]
+		if len(members) == 0 {
+			continue
+		}
+
+		total, err := calc.cost(el)
+		if err != nil {
+			return nil, err
+		}
+
+		for userID, share := range shares(el, members) {
+			if userID == el.UserID {
+				continue
+			}
+			if user.Valid && user.UUID != userID && user.UUID != el.UserID {
+				continue
+			}
+			owed[pair{from: userID, to: el.UserID}] += total * share
+		}
+	}
+
+	settlement := dto.Settlement{
+		Currency: calc.currency,
+		Debts:    []dto.Debt{},
+	}
+	for p, amount := range owed {
+		// Встречный долг вычитается, пара учитывается один раз - со стороны большего долга
+		net := amount - owed[pair{from: p.to, to: p.from}]
+		if net <= 0 {
+			continue
+		}
+		settlement.Debts = append(settlement.Debts, dto.Debt{
+			From:   p.from.String(),
+			To:     p.to.String(),
+			Amount: rates.Round(net),
+		})
+	}
+
+	sort.Slice(settlement.Debts, func(i, j int) bool {
+		if settlement.Debts[i].Amount != settlement.Debts[j].Amount {
+			return settlement.Debts[i].Amount > settlement.Debts[j].Amount
+		}
+		return settlement.Debts[i].From < settlement.Debts[j].From
+	})
+
+	return &settlement, nil
+}
+
+// shares возвращает доли плательщика и участников в стоимости подписки, в сумме - 1.
+// Фиксированные суммы участников считаются от текущей цены подписки, остаток платит плательщик.
+func shares(sub db.Subscription, members []db.SubscriptionMember) map[uuid.UUID]float64 {
+	result := map[uuid.UUID]float64{}
+	payer := 1.0
+
+	for _, member := range members {
+		share := 0.0
+		switch sub.SplitRule {
+		case dto.SplitPercent:
+			share = member.Share.Float64 / 100
+		case dto.SplitFixed:
+			if sub.Price > 0 {
+				share = member.Share.Float64 / float64(sub.Price)
+			}
+		default:
+			share = 1 / float64(len(members)+1)
+		}
+		result[member.UserID] = share
+		payer -= share
+	}
+
+	result[sub.UserID] = max(payer, 0)
+	return result
+}
+
+// loadMembers возвращает участников подписок по их id
+func loadMembers(ctx context.Context, q *db.Queries, subs []db.Subscription) (map[int32][]db.SubscriptionMember, error) {
+	ids := make([]int32, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	rows, err := q.MembersForSubscriptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[int32][]db.SubscriptionMember{}
+	for _, row := range rows {
+		members[row.SubscriptionID] = append(members[row.SubscriptionID], row)
+	}
+	return members, nil
+}
+
+// saveMembers заменяет участников подписки. Если members == nil, текущие участники сохраняются
+// и проверяются заново, так как правило разделения или цена могли измениться.
+func saveMembers(ctx context.Context, q *db.Queries, sub db.Subscription, members []dto.Member) error {
+	if members == nil {
+		current, err := loadMembers(ctx, q, []db.Subscription{sub})
+		if err != nil {
+			return err
+		}
+		if len(current[sub.ID]) == 0 {
+			return nil
+		}
+
+		members = []dto.Member{}
+		for _, member := range current[sub.ID] {
+			members = append(members, dto.MemberFromSql(member))
+		}
+	}
+
+	rows, err := dto.MembersToSql(members, sub)
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteSubscriptionMembers(ctx, db.DeleteSubscriptionMembersParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = q.AddSubscriptionMember(ctx, db.AddSubscriptionMemberParams{
+			SubscriptionID: row.SubscriptionID,
+			TenantID:       row.TenantID,
+			UserID:         row.UserID,
+			Share:          row.Share,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/audit"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+)
+
+var ErrConflict = errors.New("conflict")
+
+// Pause приостанавливает подписку с req.From (по умолчанию сегодня) до req.Until.
+// Без Until пауза длится до вызова Resume.
+func (s *Services) Pause(ctx context.Context, id int32, req dto.PauseRequest) (*dto.Subscription, error) {
+	from, err := requestDate(req.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from: %w", dto.ErrInvalid, err)
+	}
+
+	resumedAt := sql.NullTime{}
+	if req.Until != nil {
+		until, _, err := dto.ParseDate(*req.Until)
+		if err != nil {
+			return nil, fmt.Errorf("%w: until: %w", dto.ErrInvalid, err)
+		}
+		if !until.After(from) {
+			return nil, fmt.Errorf("%w: until must be after from", dto.ErrInvalid)
+		}
+		resumedAt = sql.NullTime{Time: until, Valid: true}
+	}
+
+	var paused dto.Subscription
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		sub, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		if from.Before(sub.StartDate) {
+			return fmt.Errorf("%w: pause can't start before start_date", dto.ErrInvalid)
+		}
+		if sub.EndDate.Valid && from.After(sub.EndDate.Time) {
+			return fmt.Errorf("%w: subscription ends before the pause", ErrConflict)
+		}
+
+		before, err := reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		pauses, err := q.SubscriptionPauses(ctx, db.SubscriptionPausesParams{
+			SubscriptionID: id,
+			TenantID:       sub.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+		if current := status(*sub, pauses, today(), s.config.DatePrecision); current == dto.StatusCancelled || current == dto.StatusEnded {
+			return fmt.Errorf("%w: can't pause %s subscription", ErrConflict, current)
+		}
+		for _, pause := range pauses {
+			if overlaps(pause, from, resumedAt) {
+				return fmt.Errorf("%w: subscription is already paused from %s", ErrConflict, pause.PausedFrom.Format(dto.DATE_FORMAT))
+			}
+		}
+
+		_, err = q.CreatePause(ctx, db.CreatePauseParams{
+			SubscriptionID: id,
+			TenantID:       sub.TenantID,
+			PausedFrom:     from,
+			ResumedAt:      resumedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		paused, err = reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionPause, id, before, paused)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &paused, nil
+}
+
+// Resume возобновляет подписку с req.Date (по умолчанию сегодня). Пауза, которая
+// ещё не началась к этой дате, отменяется.
+func (s *Services) Resume(ctx context.Context, id int32, req dto.ResumeRequest) (*dto.Subscription, error) {
+	date, err := requestDate(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date: %w", dto.ErrInvalid, err)
+	}
+
+	var resumed dto.Subscription
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		sub, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		before, err := reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		pauses, err := q.SubscriptionPauses(ctx, db.SubscriptionPausesParams{
+			SubscriptionID: id,
+			TenantID:       sub.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Последняя пауза, которая не закончилась к date
+		var pause *db.SubscriptionPause
+		for i := range pauses {
+			if !pauses[i].ResumedAt.Valid || pauses[i].ResumedAt.Time.After(date) {
+				pause = &pauses[i]
+			}
+		}
+		if pause == nil {
+			return fmt.Errorf("%w: subscription is not paused", ErrConflict)
+		}
+
+		if date.After(pause.PausedFrom) {
+			_, err = q.ResumePause(ctx, db.ResumePauseParams{
+				ID:        pause.ID,
+				TenantID:  sub.TenantID,
+				ResumedAt: sql.NullTime{Time: date, Valid: true},
+			})
+		} else {
+			err = q.DeletePause(ctx, db.DeletePauseParams{
+				ID:       pause.ID,
+				TenantID: sub.TenantID,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		resumed, err = reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionResume, id, before, resumed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resumed, nil
+}
+
+// loadPauses возвращает паузы подписок по их id
+func loadPauses(ctx context.Context, q *db.Queries, subs []db.Subscription) (map[int32][]db.SubscriptionPause, error) {
+	ids := make([]int32, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	rows, err := q.PausesForSubscriptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	pauses := map[int32][]db.SubscriptionPause{}
+	for _, row := range rows {
+		pauses[row.SubscriptionID] = append(pauses[row.SubscriptionID], row)
+	}
+	return pauses, nil
+}
+
+// overlaps сообщает, пересекается ли пауза с периодом [from, until)
+func overlaps(pause db.SubscriptionPause, from time.Time, until sql.NullTime) bool {
+	if pause.ResumedAt.Valid && !from.Before(pause.ResumedAt.Time) {
+		return false
+	}
+	if until.Valid && !pause.PausedFrom.Before(until.Time) {
+		return false
+	}
+	return true
+}
+
+// status возвращает состояние подписки на дату today. Подписка заканчивается по endDate с той же точностью,
+// что и подсчёт стоимости
+func status(sub db.Subscription, pauses []db.SubscriptionPause, today time.Time, precision string) string {
+	if end, ok := endDate(sub, precision); ok && today.After(end) {
+		return dto.StatusEnded
+	}
+	if sub.CancelledAt.Valid {
+		return dto.StatusCancelled
+	}
+	if today.Before(sub.StartDate) {
+		return dto.StatusScheduled
+	}
+	for _, pause := range pauses {
+		if !today.Before(pause.PausedFrom) && (!pause.ResumedAt.Valid || today.Before(pause.ResumedAt.Time)) {
+			return dto.StatusPaused
+		}
+	}
+	return dto.StatusActive
+}
+
+// withStatus оставляет подписки в состоянии status
+func withStatus(subs []dto.Subscription, status string) []dto.Subscription {
+	result := []dto.Subscription{}
+	for _, sub := range subs {
+		if sub.Status == status {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
+// requestDate разбирает дату из запроса, по умолчанию возвращает сегодняшнюю
+func requestDate(value *string) (time.Time, error) {
+	if value == nil || *value == "" {
+		return today(), nil
+	}
+	date, _, err := dto.ParseDate(*value)
+	return date, err
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,101 @@
+package subscriptions
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStatus(t *testing.T) {
+	ended := sql.NullTime{Time: date(2025, time.March, 1), Valid: true}
+	cases := []struct {
+		name      string
+		sub       db.Subscription
+		pauses    []db.SubscriptionPause
+		today     time.Time
+		precision string
+		want      string
+	}{
+		{
+			name:      "active",
+			sub:       db.Subscription{StartDate: date(2025, time.January, 1)},
+			today:     date(2025, time.March, 15),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusActive,
+		},
+		{
+			name:      "scheduled",
+			sub:       db.Subscription{StartDate: date(2025, time.April, 1)},
+			today:     date(2025, time.March, 15),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusScheduled,
+		},
+		{
+			name:      "end month still paid",
+			sub:       db.Subscription{StartDate: date(2025, time.January, 1), EndDate: ended},
+			today:     date(2025, time.March, 31),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusActive,
+		},
+		{
+			name:      "after end month",
+			sub:       db.Subscription{StartDate: date(2025, time.January, 1), EndDate: ended},
+			today:     date(2025, time.April, 1),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusEnded,
+		},
+		{
+			name:      "after end day",
+			sub:       db.Subscription{StartDate: date(2025, time.January, 1), EndDate: ended},
+			today:     date(2025, time.March, 2),
+			precision: billing.PrecisionDay,
+			want:      dto.StatusEnded,
+		},
+		{
+			name: "cancelled before end",
+			sub: db.Subscription{
+				StartDate:   date(2025, time.January, 1),
+				EndDate:     ended,
+				CancelledAt: sql.NullTime{Time: date(2025, time.February, 10), Valid: true},
+			},
+			today:     date(2025, time.March, 15),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusCancelled,
+		},
+		{
+			name:      "paused",
+			sub:       db.Subscription{StartDate: date(2025, time.January, 1)},
+			pauses:    []db.SubscriptionPause{{PausedFrom: date(2025, time.March, 1)}},
+			today:     date(2025, time.March, 15),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusPaused,
+		},
+		{
+			name: "resumed",
+			sub:  db.Subscription{StartDate: date(2025, time.January, 1)},
+			pauses: []db.SubscriptionPause{{
+				PausedFrom: date(2025, time.February, 1),
+				ResumedAt:  sql.NullTime{Time: date(2025, time.March, 1), Valid: true},
+			}},
+			today:     date(2025, time.March, 15),
+			precision: billing.PrecisionMonth,
+			want:      dto.StatusActive,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := status(c.sub, c.pauses, c.today, c.precision); got != c.want {
+				t.Errorf("status = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
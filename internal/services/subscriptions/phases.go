@@ -0,0 +1,145 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+)
+
+// TrialsEnding возвращает подписки, пробный период которых заканчивается в ближайшие days дней
+func (s *Services) TrialsEnding(ctx context.Context, days int, userId string) (*[]dto.TrialEnding, error) {
+	if days < 0 {
+		return nil, fmt.Errorf("%w: days must not be negative", dto.ErrInvalid)
+	}
+
+	userId, err := scopeFilter(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.TrialsEndingParams{
+		TenantID: tenancy.FromContext(ctx),
+	}
+	if userId != "" {
+		parsed, err := uuid.Parse(userId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: user_id: %w", dto.ErrInvalid, err)
+		}
+		params.UserID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+
+	now := time.Now()
+	params.FromDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	params.ToDate = params.FromDate.AddDate(0, 0, days)
+
+	var rows []db.TrialsEndingRow
+	var subDetails details
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		rows, err = q.TrialsEnding(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		subs := []db.Subscription{}
+		for _, row := range rows {
+			subs = append(subs, row.Subscription)
+		}
+		subDetails, err = loadDetails(ctx, q, subs, s.config.DatePrecision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trials := []dto.TrialEnding{}
+	for _, row := range rows {
+		trials = append(trials, dto.TrialEnding{
+			Subscription: subscriptionFromSql(row.Subscription, subDetails),
+			TrialEndDate: row.TrialEndDate.Format(dto.DATE_FORMAT),
+		})
+	}
+
+	return &trials, nil
+}
+
+// loadPhases возвращает ценовые фазы подписок по их id
+func loadPhases(ctx context.Context, q *db.Queries, subs []db.Subscription) (map[int32][]db.SubscriptionPhase, error) {
+	ids := make([]int32, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	rows, err := q.PhasesForSubscriptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	phases := map[int32][]db.SubscriptionPhase{}
+	for _, row := range rows {
+		phases[row.SubscriptionID] = append(phases[row.SubscriptionID], row)
+	}
+	return phases, nil
+}
+
+// savePhases заменяет ценовые фазы подписки. Если phases == nil, текущие фазы сохраняются
+// и пересчитываются от нового start_date.
+func savePhases(ctx context.Context, q *db.Queries, sub db.Subscription, phases []dto.Phase) ([]db.SubscriptionPhase, error) {
+	if phases == nil {
+		current, err := q.SubscriptionPhases(ctx, db.SubscriptionPhasesParams{
+			SubscriptionID: sub.ID,
+			TenantID:       sub.TenantID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(current) == 0 {
+			return nil, nil
+		}
+
+		phases = []dto.Phase{}
+		for _, phase := range current {
+			phases = append(phases, dto.PhaseFromSql(phase))
+		}
+	}
+
+	rows, err := dto.PhasesToSql(phases, sub.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.DeletePhases(ctx, db.DeletePhasesParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		rows[i].SubscriptionID = sub.ID
+		rows[i].TenantID = sub.TenantID
+
+		err := q.CreatePhase(ctx, db.CreatePhaseParams{
+			SubscriptionID: rows[i].SubscriptionID,
+			TenantID:       rows[i].TenantID,
+			Position:       rows[i].Position,
+			Kind:           rows[i].Kind,
+			Price:          rows[i].Price,
+			Months:         rows[i].Months,
+			Days:           rows[i].Days,
+			StartDate:      rows[i].StartDate,
+			EndDate:        rows[i].EndDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rows, nil
+}
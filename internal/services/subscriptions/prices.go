@@ -0,0 +1,258 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/audit"
+	"github.com/feproldo/effective-mobile/internal/services/catalog"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+)
+
+// Prices возвращает историю цены подписки
+func (s *Services) Prices(ctx context.Context, id int32) (*[]dto.SubscriptionPrice, error) {
+	prices := []dto.SubscriptionPrice{}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		sub, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		rows, err := q.SubscriptionPrices(ctx, db.SubscriptionPricesParams{
+			SubscriptionID: sub.ID,
+			TenantID:       sub.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			prices = append(prices, dto.PriceFromSql(row))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &prices, nil
+}
+
+// ChangePrice меняет цену всех активных подписок сервиса начиная с change.EffectiveFrom.
+// Запланированные на более позднее время изменения цены этих подписок удаляются.
+// С DryRun только возвращает затронутые подписки и изменение стоимости. Доступно только администраторам.
+func (s *Services) ChangePrice(ctx context.Context, change dto.PriceChange) (*dto.PriceChangeResult, error) {
+	if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		return nil, ErrForbidden
+	}
+
+	if change.ServiceName == "" {
+		return nil, fmt.Errorf("%w: service_name is required", dto.ErrInvalid)
+	}
+	if change.Price < 0 {
+		return nil, fmt.Errorf("%w: price must not be negative", dto.ErrInvalid)
+	}
+	effectiveFrom, _, err := dto.ParseDate(change.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: effective_from: %w", dto.ErrInvalid, err)
+	}
+
+	result := dto.PriceChangeResult{
+		ServiceName:   change.ServiceName,
+		Price:         change.Price,
+		EffectiveFrom: effectiveFrom.Format(dto.DATE_FORMAT),
+		DryRun:        change.DryRun,
+		Rows:          []dto.PriceChangeRow{},
+		Currency:      rates.Base,
+	}
+
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		service, err := catalog.Resolve(ctx, q, change.ServiceName)
+		if err != nil {
+			return err
+		}
+		if service != nil {
+			result.ServiceName = service.Name
+		}
+
+		subs, err := q.ActiveServiceSubscriptions(ctx, db.ActiveServiceSubscriptionsParams{
+			TenantID:      tenancy.FromContext(ctx),
+			ServiceName:   result.ServiceName,
+			EffectiveFrom: effectiveFrom,
+		})
+		if err != nil {
+			return err
+		}
+
+		prices, err := loadPrices(ctx, q, subs)
+		if err != nil {
+			return err
+		}
+
+		currencies := []string{rates.Base}
+		for _, sub := range subs {
+			currencies = append(currencies, sub.Currency)
+		}
+		converter, err := rates.Load(ctx, q, currencies)
+		if err != nil {
+			return err
+		}
+
+		// Подписки до и после изменения попадают в аудит и события целиком, вместе с участниками
+		var before []dto.Subscription
+		changed := []db.Subscription{}
+		if !change.DryRun {
+			if before, err = withDetails(ctx, q, subs, s.config.DatePrecision); err != nil {
+				return err
+			}
+		}
+
+		for _, sub := range subs {
+			from := effectiveFrom
+			if from.Before(sub.StartDate) {
+				from = sub.StartDate
+			}
+
+			plan := planFromSql(sub, details{prices: prices}, billing.PrecisionMonth)
+			oldPrice := plan.PriceAt(from)
+			delta := plan.Period.MonthlyEquivalent(float64(change.Price - oldPrice))
+
+			impact := 0.0
+			if delta != 0 {
+				impact, err = converter.Convert(delta, sub.Currency, rates.Base, from)
+				if err != nil {
+					return err
+				}
+			}
+
+			result.Rows = append(result.Rows, dto.PriceChangeRow{
+				SubscriptionID: sub.ID,
+				UserID:         sub.UserID.String(),
+				Currency:       sub.Currency,
+				OldPrice:       oldPrice,
+				NewPrice:       change.Price,
+				MonthlyDelta:   rates.Round(delta),
+			})
+			result.MonthlyImpact += impact
+
+			if change.DryRun {
+				continue
+			}
+
+			if err := savePrice(ctx, q, sub, from, int32(change.Price)); err != nil {
+				return err
+			}
+
+			after := sub
+			after.Price = int32(change.Price)
+			changed = append(changed, after)
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+		after, err := withDetails(ctx, q, changed, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+		for i := range after {
+			if err := record(ctx, q, audit.ActionUpdate, after[i].ID, before[i], after[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Affected = len(result.Rows)
+	result.MonthlyImpact = rates.Round(result.MonthlyImpact)
+
+	return &result, nil
+}
+
+// loadPrices возвращает историю цен подписок по их id
+func loadPrices(ctx context.Context, q *db.Queries, subs []db.Subscription) (map[int32][]db.SubscriptionPrice, error) {
+	ids := make([]int32, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	rows, err := q.PricesForSubscriptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := map[int32][]db.SubscriptionPrice{}
+	for _, row := range rows {
+		prices[row.SubscriptionID] = append(prices[row.SubscriptionID], row)
+	}
+	return prices, nil
+}
+
+// updatePrice записывает в историю цену обновлённой подписки, если она изменилась.
+// Новая цена действует с effectiveFrom, по умолчанию - с начала текущего месяца.
+func updatePrice(ctx context.Context, q *db.Queries, sub db.Subscription, effectiveFrom *string) error {
+	from := billing.MonthStart(time.Now())
+	if effectiveFrom != nil && *effectiveFrom != "" {
+		parsed, _, err := dto.ParseDate(*effectiveFrom)
+		if err != nil {
+			return fmt.Errorf("%w: price_effective_from: %w", dto.ErrInvalid, err)
+		}
+		from = parsed
+	}
+	if from.Before(sub.StartDate) {
+		from = sub.StartDate
+	}
+
+	prices, err := q.SubscriptionPrices(ctx, db.SubscriptionPricesParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+	})
+	if err != nil {
+		return err
+	}
+
+	current := planFromSql(sub, details{prices: map[int32][]db.SubscriptionPrice{sub.ID: prices}}, billing.PrecisionMonth)
+	if len(prices) > 0 && current.PriceAt(from) == int(sub.Price) && prices[len(prices)-1].Price == sub.Price {
+		return nil
+	}
+
+	return savePrice(ctx, q, sub, from, sub.Price)
+}
+
+// savePrice устанавливает цену подписки начиная с from и удаляет более поздние изменения,
+// так что subscriptions.price остаётся равной последней цене в истории
+func savePrice(ctx context.Context, q *db.Queries, sub db.Subscription, from time.Time, price int32) error {
+	err := q.SavePrice(ctx, db.SavePriceParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+		EffectiveFrom:  from,
+		Price:          price,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.DeletePricesAfter(ctx, db.DeletePricesAfterParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+		EffectiveFrom:  from,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.SetSubscriptionPrice(ctx, db.SetSubscriptionPriceParams{
+		ID:       sub.ID,
+		TenantID: sub.TenantID,
+		Price:    price,
+	})
+}
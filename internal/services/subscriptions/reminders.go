@@ -0,0 +1,339 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/notify"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Состояния напоминаний в reminder_jobs
+const (
+	reminderPending = "pending"
+	reminderFailed  = "failed"
+)
+
+const (
+	reminderBatch = 100
+	// Время, на которое напоминание закрепляется за отправителем. Если процесс упадёт
+	// до отметки об отправке, напоминание будет отправлено повторно.
+	reminderLease = 5 * time.Minute
+	// Предельная задержка перед повторной отправкой
+	maxRetryDelay = 24 * time.Hour
+)
+
+// ReminderConfig - настройки планировщика напоминаний
+type ReminderConfig struct {
+	// Как часто искать события и отправлять напоминания
+	Interval time.Duration
+	// За сколько дней напоминать, если пользователь не задал своё значение
+	Days int
+	// Количество попыток отправки, после которого напоминание помечается failed
+	MaxAttempts int
+	// Задержка перед первой повторной попыткой, дальше удваивается
+	RetryDelay time.Duration
+}
+
+// event - событие подписки, о котором напоминают
+type event struct {
+	kind   string
+	date   time.Time
+	amount int
+}
+
+// Upcoming возвращает события подписок в ближайшие days дней (включая сегодня) по дате
+func (s *Services) Upcoming(ctx context.Context, days int, userId string) (*[]dto.UpcomingEvent, error) {
+	if days < 0 {
+		return nil, fmt.Errorf("%w: days must not be negative", dto.ErrInvalid)
+	}
+
+	userId, err := scopeFilter(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	from := today()
+	to := from.AddDate(0, 0, days)
+	params := db.GetSubscriptionsWithFilterParams{
+		TenantID:   tenancy.FromContext(ctx),
+		FromDate:   sql.NullTime{Time: from, Valid: true},
+		ToDate:     sql.NullTime{Time: to, Valid: true},
+		Categories: []string{},
+		Tags:       []string{},
+	}
+	if userId != "" {
+		parsed, err := uuid.Parse(userId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: user_id: %w", dto.ErrInvalid, err)
+		}
+		params.UserID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+
+	var subs []db.Subscription
+	var subDetails details
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		subs, err = q.GetSubscriptionsWithFilter(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		subDetails, err = loadDetails(ctx, q, subs, s.config.DatePrecision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []dto.UpcomingEvent{}
+	for _, sub := range subs {
+		for _, ev := range events(sub, subDetails, from, to) {
+			upcoming = append(upcoming, dto.UpcomingEvent{
+				Subscription: subscriptionFromSql(sub, subDetails),
+				Kind:         ev.kind,
+				Date:         ev.date.Format(dto.DATE_FORMAT),
+				Amount:       ev.amount,
+			})
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date < upcoming[j].Date
+	})
+
+	return &upcoming, nil
+}
+
+// RunReminders раз в Interval планирует напоминания о событиях подписок всех тенантов
+// и отправляет их через notifier, пока не отменён ctx
+func (s *Services) RunReminders(ctx context.Context, config ReminderConfig, notifier notify.Notifier) {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ScheduleReminders(ctx, config.Days); err != nil {
+			log.Error().Err(err).Msg("can't schedule reminders")
+		}
+		if err := s.DeliverReminders(ctx, config, notifier); err != nil {
+			log.Error().Err(err).Msg("can't deliver reminders")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ScheduleReminders записывает в reminder_jobs напоминания о событиях подписок всех тенантов,
+// которые наступят в ближайшие reminder_days дней плательщика (по умолчанию defaultDays).
+// Напоминание о каждом событии создаётся один раз.
+func (s *Services) ScheduleReminders(ctx context.Context, defaultDays int) error {
+	return tenancy.InTx(tenancy.WithTenant(ctx, tenancy.All), s.conn, s.queries, func(q *db.Queries) error {
+		settings, err := q.AllNotificationSettings(ctx)
+		if err != nil {
+			return err
+		}
+
+		type userKey struct {
+			tenantID string
+			userID   uuid.UUID
+		}
+		userDays := map[userKey]int{}
+		maxDays := defaultDays
+		for _, el := range settings {
+			userDays[userKey{el.TenantID, el.UserID}] = int(el.ReminderDays)
+			maxDays = max(maxDays, int(el.ReminderDays))
+		}
+
+		from := today()
+		subs, err := q.GetAllTenantsSubscriptionsWithFilter(ctx, db.GetAllTenantsSubscriptionsWithFilterParams{
+			FromDate:   sql.NullTime{Time: from, Valid: true},
+			ToDate:     sql.NullTime{Time: from.AddDate(0, 0, maxDays), Valid: true},
+			Categories: []string{},
+			Tags:       []string{},
+		})
+		if err != nil {
+			return err
+		}
+
+		d, err := loadDetails(ctx, q, subs, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		for _, sub := range subs {
+			days, ok := userDays[userKey{sub.TenantID, sub.UserID}]
+			if !ok {
+				days = defaultDays
+			}
+
+			for _, ev := range events(sub, d, from, from.AddDate(0, 0, days)) {
+				err := q.EnqueueReminder(ctx, db.EnqueueReminderParams{
+					TenantID:       sub.TenantID,
+					SubscriptionID: sub.ID,
+					UserID:         sub.UserID,
+					Kind:           ev.kind,
+					EventDate:      ev.date,
+					ServiceName:    sub.ServiceName,
+					Amount:         int32(ev.amount),
+					Currency:       sub.Currency,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeliverReminders отправляет напоминания, время отправки которых наступило. Неудачная отправка
+// повторяется с экспоненциальной задержкой, после MaxAttempts попыток напоминание помечается failed.
+func (s *Services) DeliverReminders(ctx context.Context, config ReminderConfig, notifier notify.Notifier) error {
+	ctx = tenancy.WithTenant(ctx, tenancy.All)
+
+	for {
+		var jobs []db.ReminderJob
+		messages := map[int32]notify.Message{}
+		err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+			var err error
+			jobs, err = q.ClaimReminders(ctx, db.ClaimRemindersParams{
+				LeaseUntil: time.Now().Add(reminderLease),
+				Batch:      reminderBatch,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, job := range jobs {
+				if messages[job.ID], err = reminderMessage(ctx, q, job); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			sendErr := notifier.Notify(ctx, messages[job.ID])
+
+			err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+				if sendErr == nil {
+					return q.MarkReminderSent(ctx, job.ID)
+				}
+
+				log.Warn().Err(sendErr).Int32("reminder_id", job.ID).Int32("attempts", job.Attempts).Msg("can't send reminder")
+
+				status := reminderPending
+				if int(job.Attempts) >= config.MaxAttempts {
+					status = reminderFailed
+				}
+				return q.MarkReminderFailed(ctx, db.MarkReminderFailedParams{
+					ID:            job.ID,
+					Status:        status,
+					LastError:     sql.NullString{String: sendErr.Error(), Valid: true},
+					NextAttemptAt: time.Now().Add(retryDelay(config.RetryDelay, job.Attempts)),
+				})
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(jobs) < reminderBatch {
+			return nil
+		}
+	}
+}
+
+// reminderMessage собирает уведомление по напоминанию. Адрес для писем заполняется,
+// если пользователь указал его и не отключил письма этого вида.
+func reminderMessage(ctx context.Context, q *db.Queries, job db.ReminderJob) (notify.Message, error) {
+	message := notify.Message{
+		Kind:           job.Kind,
+		TenantID:       job.TenantID,
+		UserID:         job.UserID,
+		SubscriptionID: job.SubscriptionID,
+		ServiceName:    job.ServiceName,
+		Date:           job.EventDate,
+		Amount:         int(job.Amount),
+		Currency:       job.Currency,
+		MonthlyLimit:   int(job.MonthlyLimit.Int32),
+		Locale:         notify.DefaultLocale,
+	}
+
+	settings, err := q.GetNotificationSettings(ctx, db.GetNotificationSettingsParams{
+		TenantID: job.TenantID,
+		UserID:   job.UserID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return message, nil
+	}
+	if err != nil {
+		return message, err
+	}
+
+	message.Locale = settings.Locale
+	wanted := settings.EmailReminders
+	if job.Kind == notify.KindBudgetExceeded {
+		wanted = settings.EmailBudgetAlerts
+	}
+	if wanted && settings.Email.Valid {
+		message.Email = settings.Email.String
+	}
+
+	return message, nil
+}
+
+// events возвращает события подписки в [from, to]: платные списания, окончание подписки
+// и окончание пробного периода с ценой после него
+func events(sub db.Subscription, d details, from time.Time, to time.Time) []event {
+	plan := planFromSql(sub, d, billing.PrecisionDay)
+	result := []event{}
+
+	for _, charge := range plan.Charges(from, to) {
+		if charge.Amount > 0 {
+			result = append(result, event{kind: notify.KindRenewal, date: charge.Date, amount: int(charge.Amount)})
+		}
+	}
+
+	if sub.EndDate.Valid && !sub.EndDate.Time.Before(from) && !sub.EndDate.Time.After(to) {
+		result = append(result, event{kind: notify.KindEnd, date: sub.EndDate.Time})
+	}
+
+	for _, phase := range d.phases[sub.ID] {
+		if phase.Kind == billing.PhaseTrial && !phase.EndDate.Before(from) && !phase.EndDate.After(to) {
+			result = append(result, event{
+				kind:   notify.KindTrialEnd,
+				date:   phase.EndDate,
+				amount: plan.PriceAt(phase.EndDate.AddDate(0, 0, 1)),
+			})
+		}
+	}
+
+	return result
+}
+
+// retryDelay возвращает задержку перед следующей попыткой: base, удваиваемая после каждой
+// неудачной попытки, но не больше maxRetryDelay
+func retryDelay(base time.Duration, attempts int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
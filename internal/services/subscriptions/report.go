@@ -0,0 +1,141 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+)
+
+const (
+	GroupByCategory = "category"
+	GroupByService  = "service"
+	GroupByUser     = "user"
+	GroupByMonth    = "month"
+	GroupByTag      = "tag"
+)
+
+// Ключи отчёта для подписок без категории или тегов
+const (
+	uncategorized = "uncategorized"
+	untagged      = "untagged"
+)
+
+// Report считает стоимость списаний за период в разрезе groupBy с теми же фильтрами, что и Sum
+func (s *Services) Report(ctx context.Context, filter dto.SumFilter, groupBy string) (*dto.Report, error) {
+	switch groupBy {
+	case GroupByCategory, GroupByService, GroupByUser, GroupByMonth, GroupByTag:
+	default:
+		return nil, fmt.Errorf("%w: group_by must be one of category, service, user, month, tag", dto.ErrInvalid)
+	}
+
+	list, calc, err := s.selectForSum(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	if groupBy == GroupByCategory {
+		categories, err := s.queries.CategoriesList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			names[category.Slug] = category.Name
+		}
+	}
+
+	sums := map[string]float64{}
+	total := 0.0
+
+	for _, el := range list {
+		charges, err := calc.charges(el)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, charge := range charges {
+			total += charge.Amount
+
+			// Стоимость совместной подписки делится между пользователями по их долям
+			if groupBy == GroupByUser {
+				for key, share := range calc.userShares(el) {
+					sums[key] += charge.Amount * share
+				}
+				continue
+			}
+
+			keys := reportKeys(el, calc.details, groupBy)
+			if groupBy == GroupByMonth {
+				keys = []string{charge.Date.Format("2006-01")}
+			}
+
+			for _, key := range keys {
+				sums[key] += charge.Amount
+			}
+		}
+	}
+
+	report := dto.Report{
+		GroupBy:  groupBy,
+		Currency: calc.currency,
+		Total:    rates.Round(total),
+		Rows:     []dto.ReportRow{},
+	}
+	for key, sum := range sums {
+		report.Rows = append(report.Rows, dto.ReportRow{
+			Key:  key,
+			Name: names[key],
+			Sum:  rates.Round(sum),
+		})
+	}
+
+	// Месяцы - по порядку, остальные разрезы - по убыванию суммы
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if groupBy == GroupByMonth {
+			return report.Rows[i].Key < report.Rows[j].Key
+		}
+		if report.Rows[i].Sum != report.Rows[j].Sum {
+			return report.Rows[i].Sum > report.Rows[j].Sum
+		}
+		return report.Rows[i].Key < report.Rows[j].Key
+	})
+
+	return &report, nil
+}
+
+// reportKeys возвращает ключи строк отчёта, в которые попадает подписка
+func reportKeys(sub db.Subscription, d details, groupBy string) []string {
+	switch groupBy {
+	case GroupByCategory:
+		if sub.Category.Valid {
+			return []string{sub.Category.String}
+		}
+		return []string{uncategorized}
+	case GroupByService:
+		return []string{sub.ServiceName}
+	case GroupByTag:
+		if tags := d.tags[sub.ID]; len(tags) > 0 {
+			return tags
+		}
+		return []string{untagged}
+	}
+	return nil
+}
+
+// userShares возвращает доли пользователей в списаниях подписки. Если подсчёт ведётся для одного
+// пользователя, списания уже содержат только его долю.
+func (c costing) userShares(sub db.Subscription) map[string]float64 {
+	if c.user.Valid {
+		return map[string]float64{c.user.UUID.String(): 1}
+	}
+
+	result := map[string]float64{}
+	for userID, share := range shares(sub, c.details.members[sub.ID]) {
+		result[userID.String()] = share
+	}
+	return result
+}
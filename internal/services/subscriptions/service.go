@@ -3,134 +3,793 @@ package subscriptions
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/billing"
 	db "github.com/feproldo/effective-mobile/internal/db/generated"
 	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/audit"
+	"github.com/feproldo/effective-mobile/internal/services/catalog"
+	"github.com/feproldo/effective-mobile/internal/services/categories"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/services/tags"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
+var ErrForbidden = errors.New("forbidden")
+
+type Config struct {
+	// Точность подсчёта стоимости по умолчанию: billing.PrecisionMonth или billing.PrecisionDay
+	DatePrecision string
+	// За сколько дней напоминать о списании в календаре, если пользователь не задал своё значение
+	ReminderDays int
+}
+
 type Services struct {
+	conn    *sql.DB
 	queries *db.Queries
+	config  Config
 }
 
-func NewService(queries *db.Queries) *Services {
+func NewService(conn *sql.DB, queries *db.Queries, config Config) *Services {
+	if config.DatePrecision == "" {
+		config.DatePrecision = billing.PrecisionMonth
+	}
+
 	return &Services{
+		conn:    conn,
 		queries: queries,
+		config:  config,
 	}
 }
 
-func (s *Services) List(ctx context.Context) (*[]dto.Subscription, error) {
-	list, err := s.queries.SubscriptionsList(ctx)
-	if err != nil {
-		return nil, err
+// List возвращает подписки тенанта. Удалённые подписки (IncludeDeleted) доступны только администратору,
+// остальные пользователи видят только свои подписки. Состояние на сегодня (Status) вычисляется
+// вместе с паузами, поэтому фильтр по нему применяется после выборки.
+func (s *Services) List(ctx context.Context, filter dto.ListFilter) (*[]dto.Subscription, error) {
+	if filter.Status != "" && !dto.ValidStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q", dto.ErrInvalid, filter.Status)
+	}
+
+	params := db.SubscriptionsListParams{
+		TenantID:       tenancy.FromContext(ctx),
+		IncludeDeleted: filter.IncludeDeleted,
+		Tags:           tags.Keys(filter.Tags),
+	}
+
+	if userID, scoped := auth.Scoped(ctx); scoped {
+		if filter.IncludeDeleted {
+			return nil, ErrForbidden
+		}
+		params.UserID = uuid.NullUUID{UUID: userID, Valid: true}
 	}
 
 	var subs []dto.Subscription
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		params.Categories, err = categories.Expand(ctx, q, filter.Category)
+		if err != nil {
+			return err
+		}
 
-	for _, el := range list {
-		subs = append(subs, dto.FromSql(el))
+		list, err := q.SubscriptionsList(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		subs, err = withDetails(ctx, q, list, s.config.DatePrecision)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	if filter.Status != "" {
+		subs = withStatus(subs, filter.Status)
+	}
+
 	return &subs, nil
 }
 
 func (s *Services) Create(ctx context.Context, sub dto.Subscription) error {
-	userUUID, err := uuid.Parse(sub.UserID)
+	sqlSub, err := prepareCreate(ctx, &sub)
 	if err != nil {
 		return err
 	}
 
-	startDate, err := time.Parse("01-2006", sub.StartDate)
-	if err != nil {
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		_, err := create(ctx, q, sub, sqlSub, s.config.DatePrecision)
 		return err
+	})
+}
+
+// prepareCreate проверяет новую подписку до обращения к базе
+func prepareCreate(ctx context.Context, sub *dto.Subscription) (*db.Subscription, error) {
+	if err := scopeUser(ctx, sub); err != nil {
+		return nil, err
 	}
 
-	endDate := sql.NullTime{
-		Time:  time.Now(),
-		Valid: false,
+	sqlSub, err := sub.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if !rates.ValidCurrency(sqlSub.Currency) {
+		return nil, rates.ErrInvalidCurrency
 	}
 
-	if sub.EndDate != nil && *sub.EndDate != "" {
-		endDateParsed, err := time.Parse("01-2006", *sub.EndDate)
-		if err == nil {
-			endDate.Time = endDateParsed
-			endDate.Valid = true
-		}
+	if sqlSub.SplitRule == "" {
+		sqlSub.SplitRule = dto.SplitEqual
 	}
 
-	s.queries.CreateSubscription(ctx, db.CreateSubscriptionParams{
-		ServiceName: sub.ServiceName,
-		Price:       int32(sub.Price),
-		UserID:      userUUID,
-		StartDate:   startDate,
-		EndDate:     endDate,
+	return sqlSub, nil
+}
+
+// create добавляет подписку с историей цены, фазами, тегами и участниками, проверяет бюджеты
+// и пишет аудит в транзакции q
+func create(ctx context.Context, q *db.Queries, sub dto.Subscription, sqlSub *db.Subscription, precision string) (int32, error) {
+	if err := resolveService(ctx, q, sqlSub, true); err != nil {
+		return 0, err
+	}
+
+	users := participants([]uuid.UUID{sqlSub.UserID}, sub.Members, nil)
+	budgetsBefore, err := budgetSpends(ctx, q, users, precision)
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := q.CreateSubscription(ctx, db.CreateSubscriptionParams{
+		ServiceName: sqlSub.ServiceName,
+		Price:       sqlSub.Price,
+		UserID:      sqlSub.UserID,
+		StartDate:   sqlSub.StartDate,
+		EndDate:     sqlSub.EndDate,
+		TenantID:    tenancy.FromContext(ctx),
+		Currency:    sqlSub.Currency,
+
+		BillingPeriod: sqlSub.BillingPeriod,
+		BillingMonths: sqlSub.BillingMonths,
+		AnchorDate:    sqlSub.AnchorDate,
+		ServiceID:     sqlSub.ServiceID,
+		Category:      sqlSub.Category,
+		SplitRule:     sqlSub.SplitRule,
 	})
-	return nil
+	if err != nil {
+		return 0, err
+	}
+
+	err = q.SavePrice(ctx, db.SavePriceParams{
+		SubscriptionID: created.ID,
+		TenantID:       created.TenantID,
+		EffectiveFrom:  created.StartDate,
+		Price:          created.Price,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := savePhases(ctx, q, created, sub.Phases); err != nil {
+		return 0, err
+	}
+	if err := saveTags(ctx, q, created, sub.Tags); err != nil {
+		return 0, err
+	}
+	if err := saveMembers(ctx, q, created, sub.Members); err != nil {
+		return 0, err
+	}
+	if err := checkBudgets(ctx, q, created, users, budgetsBefore, precision); err != nil {
+		return 0, err
+	}
+
+	after, err := reload(ctx, q, created, precision)
+	if err != nil {
+		return 0, err
+	}
+
+	return created.ID, record(ctx, q, audit.ActionCreate, created.ID, nil, after)
 }
 
 func (s *Services) GetByUserId(ctx context.Context, user_id uuid.UUID) (*[]dto.Subscription, error) {
-	userUUID := user_id
-	subsSql, err := s.queries.UserSubscriptions(ctx, userUUID)
-	if err != nil {
-		return nil, err
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != user_id {
+		return nil, ErrForbidden
 	}
 
+	userUUID := user_id
 	var subs []dto.Subscription
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		subsSql, err := q.UserSubscriptions(ctx, db.UserSubscriptionsParams{
+			UserID:   userUUID,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
 
-	for _, el := range subsSql {
-		subs = append(subs, dto.FromSql(el))
+		subs, err = withDetails(ctx, q, subsSql, s.config.DatePrecision)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &subs, nil
 }
 
 func (s *Services) Get(ctx context.Context, id int32) (*dto.Subscription, error) {
-	subSql, err := s.queries.GetSubscription(ctx, id)
+	var sub dto.Subscription
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		subSql, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		sub, err = reload(ctx, q, *subSql, s.config.DatePrecision)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	sub := dto.FromSql(subSql)
-
 	return &sub, nil
 }
 
 func (s *Services) Delete(ctx context.Context, id int32) error {
-	err := s.queries.DeleteSubscription(ctx, id)
-	return err
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		sub, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		// Подписка загружается до удаления вместе с участниками: по ним поток событий находит получателей
+		before, err := reload(ctx, q, *sub, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteSubscription(ctx, db.DeleteSubscriptionParams{
+			ID:       id,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionDelete, id, before, nil)
+	})
 }
 
 func (s *Services) Update(ctx context.Context, id int32, sub dto.Subscription) error {
+	if err := scopeUser(ctx, &sub); err != nil {
+		return err
+	}
+
 	sqlSub, err := sub.ToSql()
 	if err != nil {
 		return err
 	}
+	if !rates.ValidCurrency(sqlSub.Currency) {
+		return rates.ErrInvalidCurrency
+	}
+
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		before, err := owned(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		// end_date отменённой подписки меняется только отменой и возобновлением
+		if before.CancelledAt.Valid && !sameDate(before.EndDate, sqlSub.EndDate) {
+			return fmt.Errorf("%w: end_date of a cancelled subscription is changed by reactivate", ErrConflict)
+		}
+
+		if err := resolveService(ctx, q, sqlSub, false); err != nil {
+			return err
+		}
+
+		if sqlSub.SplitRule == "" {
+			sqlSub.SplitRule = before.SplitRule
+		}
+
+		members, err := loadMembers(ctx, q, []db.Subscription{*before})
+		if err != nil {
+			return err
+		}
+		users := participants([]uuid.UUID{before.UserID, sqlSub.UserID}, sub.Members, members[id])
+		budgetsBefore, err := budgetSpends(ctx, q, users, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		updateSql := db.UpdateSubscriptionParams{
+			ID:          id,
+			ServiceName: sqlSub.ServiceName,
+			Price:       sqlSub.Price,
+			UserID:      sqlSub.UserID,
+			StartDate:   sqlSub.StartDate,
+			EndDate:     sqlSub.EndDate,
+			TenantID:    tenancy.FromContext(ctx),
+			Currency:    sqlSub.Currency,
+
+			BillingPeriod: sqlSub.BillingPeriod,
+			BillingMonths: sqlSub.BillingMonths,
+			AnchorDate:    sqlSub.AnchorDate,
+			ServiceID:     sqlSub.ServiceID,
+			Category:      sqlSub.Category,
+			SplitRule:     sqlSub.SplitRule,
+		}
+
+		beforeSub, err := reload(ctx, q, *before, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		after, err := q.UpdateSubscription(ctx, updateSql)
+		if err != nil {
+			return err
+		}
+
+		if err := updatePrice(ctx, q, after, sub.PriceEffectiveFrom); err != nil {
+			return err
+		}
+
+		if _, err := savePhases(ctx, q, after, sub.Phases); err != nil {
+			return err
+		}
+		if err := saveTags(ctx, q, after, sub.Tags); err != nil {
+			return err
+		}
+		if err := saveMembers(ctx, q, after, sub.Members); err != nil {
+			return err
+		}
+		if err := checkBudgets(ctx, q, after, users, budgetsBefore, s.config.DatePrecision); err != nil {
+			return err
+		}
+
+		afterSub, err := reload(ctx, q, after, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionUpdate, id, beforeSub, afterSub)
+	})
+}
+
+// Sum считает стоимость всех списаний за период в валюте filter.Currency.
+// Каждое списание пересчитывается по курсу месяца, в котором оно произошло.
+func (s *Services) Sum(ctx context.Context, filter dto.SumFilter) (*dto.SumResult, error) {
+	list, calc, err := s.selectForSum(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := 0.0
+
+	for _, el := range list {
+		amount, err := calc.cost(el)
+		if err != nil {
+			return nil, err
+		}
+		sum += amount
+	}
+
+	return &dto.SumResult{
+		Sum:      rates.Round(sum),
+		Currency: calc.currency,
+		Rates:    calc.converter.Used(),
+	}, nil
+}
+
+// selectForSum выбирает подписки тенанта по фильтру и готовит подсчёт их стоимости
+func (s *Services) selectForSum(ctx context.Context, filter dto.SumFilter) ([]db.Subscription, costing, error) {
+	userId, err := scopeFilter(ctx, filter.UserID)
+	if err != nil {
+		return nil, costing{}, err
+	}
+
+	params, calc, err := s.sumParams(filter, userId)
+	if err != nil {
+		return nil, costing{}, err
+	}
+	params.TenantID = tenancy.FromContext(ctx)
+
+	var list []db.Subscription
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		service, err := catalog.Resolve(ctx, q, filter.ServiceName)
+		if err != nil {
+			return err
+		}
+		if service != nil {
+			params.ServiceName = service.Name
+		}
+
+		params.Categories, err = categories.Expand(ctx, q, filter.Category)
+		if err != nil {
+			return err
+		}
+
+		list, err = q.GetSubscriptionsWithFilter(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		return calc.prepare(ctx, q, list)
+	})
+	if err != nil {
+		return nil, costing{}, err
+	}
+
+	return list, calc, nil
+}
+
+// sumParams проверяет фильтр и возвращает параметры выборки подписок и подсчёта стоимости
+func (s *Services) sumParams(filter dto.SumFilter, userId string) (db.GetSubscriptionsWithFilterParams, costing, error) {
+	params := db.GetSubscriptionsWithFilterParams{
+		ServiceName: filter.ServiceName,
+		Tags:        tags.Keys(filter.Tags),
+	}
+
+	currency, err := targetCurrency(filter.Currency)
+	if err != nil {
+		return params, costing{}, err
+	}
+
+	from, to, err := sumWindow(filter, time.Now())
+	if err != nil {
+		return params, costing{}, err
+	}
+
+	precision, err := s.precision(filter.DatePrecision)
+	if err != nil {
+		return params, costing{}, err
+	}
+
+	if userId != "" {
+		parsed, err := uuid.Parse(userId)
+		if err != nil {
+			return params, costing{}, fmt.Errorf("%w: user_id: %w", dto.ErrInvalid, err)
+		}
+		params.UserID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+
+	// При помесячной точности подписка действует до конца месяца end_date,
+	// поэтому предварительная выборка в БД идёт с начала месяца
+	if filter.StartDate != "" {
+		params.FromDate = sql.NullTime{Time: billing.MonthStart(from), Valid: true}
+	}
+	if filter.EndDate != "" {
+		params.ToDate = sql.NullTime{Time: to, Valid: true}
+	}
+
+	return params, costing{
+		currency:  currency,
+		from:      from,
+		to:        to,
+		precision: precision,
+		user:      params.UserID,
+	}, nil
+}
+
+// Restore восстанавливает удалённую подписку
+func (s *Services) Restore(ctx context.Context, id int32) (*dto.Subscription, error) {
+	var restored dto.Subscription
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		restoredSql, err := q.RestoreSubscription(ctx, db.RestoreSubscriptionParams{
+			ID:       id,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		if userID, scoped := auth.Scoped(ctx); scoped && restoredSql.UserID != userID {
+			return ErrForbidden
+		}
+
+		restored, err = reload(ctx, q, restoredSql, s.config.DatePrecision)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.ActionRestore, id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
+
+// PurgeDeleted окончательно удаляет подписки всех тенантов, удалённые раньше olderThan
+func (s *Services) PurgeDeleted(ctx context.Context, olderThan time.Time) (int64, error) {
+	var purged int64
+	err := tenancy.InTx(tenancy.WithTenant(ctx, tenancy.All), s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		purged, err = q.PurgeDeletedSubscriptions(ctx, sql.NullTime{Time: olderThan, Valid: true})
+		return err
+	})
+	return purged, err
+}
+
+// RunPurge раз в interval удаляет подписки, пролежавшие в корзине дольше retention, пока не отменён ctx
+func (s *Services) RunPurge(ctx context.Context, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Error().Err(err).Msg("can't purge deleted subscriptions")
+		} else if purged > 0 {
+			log.Info().Int64("count", purged).Msg("purged deleted subscriptions")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// History возвращает журнал изменений подписки. История удалённой подписки доступна только администратору.
+func (s *Services) History(ctx context.Context, id int32) (*[]dto.AuditRecord, error) {
+	var history *[]dto.AuditRecord
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		_, err := owned(ctx, q, id)
+		if _, scoped := auth.Scoped(ctx); err != nil && (scoped || !errors.Is(err, sql.ErrNoRows)) {
+			return err
+		}
+
+		history, err = audit.History(ctx, q, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*history) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return history, nil
+}
+
+// SumByTenant считает сумму по каждому тенанту. Доступно только администраторам.
+func (s *Services) SumByTenant(ctx context.Context, filter dto.SumFilter) (*[]dto.TenantSum, error) {
+	if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		return nil, ErrForbidden
+	}
+
+	params, calc, err := s.sumParams(filter, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []db.Subscription
+	err = tenancy.InTx(tenancy.WithTenant(ctx, tenancy.All), s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		params.Categories, err = categories.Expand(ctx, q, filter.Category)
+		if err != nil {
+			return err
+		}
+
+		rows, err = q.GetAllTenantsSubscriptionsWithFilter(ctx, db.GetAllTenantsSubscriptionsWithFilterParams{
+			UserID:      params.UserID,
+			ServiceName: params.ServiceName,
+			FromDate:    params.FromDate,
+			ToDate:      params.ToDate,
+			Categories:  params.Categories,
+			Tags:        params.Tags,
+		})
+		if err != nil {
+			return err
+		}
+
+		return calc.prepare(ctx, q, rows)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	updateSql := db.UpdateSubscriptionParams(*sqlSub)
-	updateSql.ID = id
+	sums := []dto.TenantSum{}
 
-	err = s.queries.UpdateSubscription(ctx, updateSql)
+	for _, el := range rows {
+		amount, err := calc.cost(el)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(sums) == 0 || sums[len(sums)-1].TenantID != el.TenantID {
+			sums = append(sums, dto.TenantSum{TenantID: el.TenantID, Currency: calc.currency})
+		}
+		sums[len(sums)-1].Sum += amount
+	}
+
+	for i := range sums {
+		sums[i].Sum = rates.Round(sums[i].Sum)
+	}
+
+	return &sums, nil
+}
+
+// resolveService сопоставляет подписку с каталогом по service_id или по названию и псевдонимам
+// и подставляет каноническое название и категорию сервиса. Название, которого нет в каталоге, сохраняется как есть.
+// При создании (defaults) подписка без цены получает цену и валюту сервиса по умолчанию.
+func resolveService(ctx context.Context, q *db.Queries, sub *db.Subscription, defaults bool) error {
+	return newResolver(q).resolve(ctx, sub, defaults)
+}
+
+// resolver сопоставляет подписки с каталогом, запоминая найденные категории и сервисы,
+// чтобы импорт не искал один и тот же сервис для каждой строки
+type resolver struct {
+	q          *db.Queries
+	categories map[string]error
+	byID       map[int32]lookup
+	byName     map[string]lookup
+}
+
+type lookup struct {
+	service *db.Service
+	err     error
+}
+
+func newResolver(q *db.Queries) *resolver {
+	return &resolver{
+		q:          q,
+		categories: map[string]error{},
+		byID:       map[int32]lookup{},
+		byName:     map[string]lookup{},
+	}
+}
+
+// resolve - resolveService с запомненными результатами поиска
+func (r *resolver) resolve(ctx context.Context, sub *db.Subscription, defaults bool) error {
+	if sub.Category.Valid {
+		if err := r.category(ctx, sub.Category.String); err != nil {
+			return err
+		}
+	}
+
+	var found lookup
+	if sub.ServiceID.Valid {
+		found = r.service(ctx, sub.ServiceID.Int32)
+	} else {
+		found = r.named(ctx, sub.ServiceName)
+	}
+	if found.err != nil || found.service == nil {
+		return found.err
+	}
+	service := found.service
+
+	sub.ServiceID = sql.NullInt32{Int32: service.ID, Valid: true}
+	sub.ServiceName = service.Name
+
+	if !sub.Category.Valid {
+		sub.Category = service.Category
+	}
+
+	if defaults && sub.Price == 0 && service.DefaultPrice.Valid {
+		sub.Price = service.DefaultPrice.Int32
+		sub.Currency = service.Currency
+	}
+
+	return nil
+}
+
+func (r *resolver) category(ctx context.Context, slug string) error {
+	if err, ok := r.categories[slug]; ok {
+		return err
+	}
+
+	_, err := r.q.GetCategory(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: unknown category %q", dto.ErrInvalid, slug)
+	}
+	r.categories[slug] = err
 	return err
 }
 
-func (s *Services) Sum(ctx context.Context, startDate string, endDate string, userId string, serviceName string) (*int, error) {
-	sql := db.GetSubscriptionsWithFilterParams{
-		Column1: userId,
-		Column2: serviceName,
-		Column3: startDate,
-		Column4: endDate,
+func (r *resolver) service(ctx context.Context, id int32) lookup {
+	if found, ok := r.byID[id]; ok {
+		return found
 	}
 
-	list, err := s.queries.GetSubscriptionsWithFilter(ctx, sql)
+	service, err := r.q.GetService(ctx, db.GetServiceParams{
+		ID:       id,
+		TenantID: tenancy.FromContext(ctx),
+	})
+	found := lookup{service: &service, err: err}
+	if errors.Is(err, sql.ErrNoRows) {
+		found.err = fmt.Errorf("%w: service_id %d not found in catalog", dto.ErrInvalid, id)
+	}
+	r.byID[id] = found
+	return found
+}
+
+func (r *resolver) named(ctx context.Context, name string) lookup {
+	if found, ok := r.byName[name]; ok {
+		return found
+	}
+
+	service, err := catalog.Resolve(ctx, r.q, name)
+	found := lookup{service: service, err: err}
+	r.byName[name] = found
+	return found
+}
+
+// precision возвращает точность подсчёта из запроса или настроек сервиса
+func (s *Services) precision(requested string) (string, error) {
+	if requested == "" {
+		return s.config.DatePrecision, nil
+	}
+	if !billing.ValidPrecision(requested) {
+		return "", fmt.Errorf("%w: %w", dto.ErrInvalid, billing.ErrInvalidPrecision)
+	}
+	return requested, nil
+}
+
+// owned возвращает подписку, если вызывающий имеет к ней доступ
+func owned(ctx context.Context, q *db.Queries, id int32) (*db.Subscription, error) {
+	sub, err := q.GetSubscription(ctx, db.GetSubscriptionParams{
+		ID:       id,
+		TenantID: tenancy.FromContext(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	sum := 0
+	if userID, scoped := auth.Scoped(ctx); scoped && sub.UserID != userID {
+		return nil, ErrForbidden
+	}
 
-	for _, el := range list {
-		sum += int(el)
+	return &sub, nil
+}
+
+// scopeUser подставляет user_id вызывающего и запрещает работу с чужими подписками
+func scopeUser(ctx context.Context, sub *dto.Subscription) error {
+	userID, err := scopeFilter(ctx, sub.UserID)
+	if err != nil {
+		return err
+	}
+
+	sub.UserID = userID
+	return nil
+}
+
+// scopeFilter возвращает user_id, которым нужно ограничить запрос
+func scopeFilter(ctx context.Context, userId string) (string, error) {
+	scopedID, scoped := auth.Scoped(ctx)
+	if !scoped {
+		return userId, nil
+	}
+
+	if userId == "" {
+		return scopedID.String(), nil
 	}
 
-	return &sum, nil
+	parsed, err := uuid.Parse(userId)
+	if err != nil {
+		return "", err
+	}
+	if parsed != scopedID {
+		return "", ErrForbidden
+	}
+	return userId, nil
+}
+
+func targetCurrency(currency string) (string, error) {
+	if currency == "" {
+		return rates.Base, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if !rates.ValidCurrency(currency) {
+		return "", rates.ErrInvalidCurrency
+	}
+	return currency, nil
 }
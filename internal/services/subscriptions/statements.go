@@ -0,0 +1,221 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/feproldo/effective-mobile/internal/auth"
+	"github.com/feproldo/effective-mobile/internal/billing"
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/rates"
+	"github.com/feproldo/effective-mobile/internal/statements"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/google/uuid"
+)
+
+const (
+	// Допустимое отклонение суммы списания по умолчанию, в процентах
+	defaultAmountTolerance = 10
+	// Наименьшая уверенность, с которой списания предлагаются как подписка
+	minConfidence = 0.5
+)
+
+// AnalyzeStatement ищет в банковской выписке повторяющиеся списания и предлагает их как подписки пользователя.
+// Продавцы сопоставляются с каталогом сервисов, а подсказки отмечаются, если такая подписка уже есть.
+func (s *Services) AnalyzeStatement(ctx context.Context, userID uuid.UUID, r io.Reader, options dto.StatementOptions) (*dto.StatementAnalysis, error) {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return nil, ErrForbidden
+	}
+
+	if options.Currency == "" {
+		options.Currency = rates.Base
+	}
+	if !rates.ValidCurrency(options.Currency) {
+		return nil, rates.ErrInvalidCurrency
+	}
+	if options.AmountTolerance == 0 {
+		options.AmountTolerance = defaultAmountTolerance
+	}
+	if options.AmountTolerance < 0 || options.AmountTolerance > 100 {
+		return nil, fmt.Errorf("%w: amount_tolerance must be between 0 and 100", dto.ErrInvalid)
+	}
+
+	format, err := parseDateFormat(options.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+	if format.layout != "" && !format.day {
+		return nil, fmt.Errorf("%w: date_format must contain DD", dto.ErrInvalid)
+	}
+
+	statement, err := statements.Parse(r, statements.Options{
+		Format:     options.Format,
+		Mapping:    options.Mapping,
+		Delimiter:  options.Delimiter,
+		DateLayout: format.layout,
+		Currency:   options.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recurring := statements.Detect(statement.Transactions, statements.DetectOptions{
+		AmountTolerance: options.AmountTolerance / 100,
+		MinConfidence:   minConfidence,
+	})
+
+	var end time.Time
+	for _, transaction := range statement.Transactions {
+		if transaction.Date.After(end) {
+			end = transaction.Date
+		}
+	}
+
+	result := dto.StatementAnalysis{
+		Format:       statement.Format,
+		Transactions: len(statement.Transactions),
+		Suggestions:  []dto.StatementSuggestion{},
+		Errors:       statement.Errors,
+	}
+
+	err = tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		services, err := q.ServicesList(ctx, tenancy.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+		subs, err := q.UserSubscriptions(ctx, db.UserSubscriptionsParams{
+			UserID:   userID,
+			TenantID: tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, el := range recurring {
+			result.Suggestions = append(result.Suggestions, suggestion(el, end, services, subs))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ConfirmStatement создаёт подписки из подтверждённых подсказок в одной транзакции: при ошибке в любой из них
+// не создаётся ни одна
+func (s *Services) ConfirmStatement(ctx context.Context, userID uuid.UUID, confirm dto.StatementConfirm) (*dto.StatementConfirmResult, error) {
+	if scopedID, scoped := auth.Scoped(ctx); scoped && scopedID != userID {
+		return nil, ErrForbidden
+	}
+	if len(confirm.Suggestions) == 0 {
+		return nil, fmt.Errorf("%w: suggestions must not be empty", dto.ErrInvalid)
+	}
+
+	subs := make([]dto.Subscription, len(confirm.Suggestions))
+	sqlSubs := make([]*db.Subscription, len(confirm.Suggestions))
+	for i, el := range confirm.Suggestions {
+		if el.BillingPeriod != billing.Monthly && el.BillingPeriod != billing.Yearly {
+			return nil, fmt.Errorf("%w: suggestion %d: billing_period must be monthly or yearly", dto.ErrInvalid, i)
+		}
+
+		subs[i] = el.ToSubscription(userID.String())
+		sqlSub, err := prepareCreate(ctx, &subs[i])
+		if err != nil {
+			return nil, fmt.Errorf("suggestion %d: %w", i, err)
+		}
+		sqlSubs[i] = sqlSub
+	}
+
+	result := dto.StatementConfirmResult{IDs: []int32{}}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		for i := range subs {
+			id, err := create(ctx, q, subs[i], sqlSubs[i], s.config.DatePrecision)
+			if err != nil {
+				return fmt.Errorf("suggestion %d: %w", i, err)
+			}
+			result.IDs = append(result.IDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// suggestion переводит повторяющиеся списания в подсказку. end - дата последней операции выписки
+func suggestion(recurring statements.Recurring, end time.Time, services []db.Service, subs []db.Subscription) dto.StatementSuggestion {
+	result := dto.StatementSuggestion{
+		Merchant:      recurring.Merchant,
+		ServiceName:   title(recurring.Key),
+		Price:         int(math.Round(recurring.Amount)),
+		Currency:      recurring.Currency,
+		BillingPeriod: recurring.Period,
+		StartDate:     recurring.First().Format(time.DateOnly),
+		LastCharge:    recurring.Last().Format(time.DateOnly),
+		NextCharge:    recurring.Next().Format(time.DateOnly),
+		Charges:       len(recurring.Charges),
+		Confidence:    recurring.Confidence,
+	}
+
+	// Подписка активна, пока ожидаемое следующее списание не пропущено больше чем на полпериода
+	grace := recurring.Next().AddDate(0, 0, 15)
+	if recurring.Period == billing.Yearly {
+		grace = recurring.Next().AddDate(0, 6, 0)
+	}
+	result.Active = end.Before(grace)
+
+	service := matchService(recurring.Key, services)
+	if service != nil {
+		result.ServiceID = &service.ID
+		result.ServiceName = service.Name
+		if service.Category.Valid {
+			result.Category = &service.Category.String
+		}
+	}
+
+	for _, sub := range subs {
+		sameService := service != nil && sub.ServiceID.Valid && sub.ServiceID.Int32 == service.ID
+		if sameService || statements.Similarity(statements.Normalize(sub.ServiceName), recurring.Key) >= statements.SimilarNames {
+			result.SubscriptionID = &sub.ID
+			break
+		}
+	}
+
+	return result
+}
+
+// matchService ищет в каталоге сервис, название или псевдоним которого похожи на продавца
+func matchService(key string, services []db.Service) *db.Service {
+	var best *db.Service
+	bestScore := 0.0
+	for i, service := range services {
+		for _, name := range append([]string{service.Name}, service.Aliases...) {
+			score := statements.Similarity(statements.Normalize(name), key)
+			if score >= statements.SimilarNames && score > bestScore {
+				best, bestScore = &services[i], score
+			}
+		}
+	}
+	return best
+}
+
+// title делает заглавной первую букву каждого слова
+func title(value string) string {
+	words := strings.Fields(value)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
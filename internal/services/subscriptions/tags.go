@@ -0,0 +1,69 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/services/tags"
+)
+
+// loadTags возвращает теги подписок по их id
+func loadTags(ctx context.Context, q *db.Queries, subs []db.Subscription) (map[int32][]string, error) {
+	ids := make([]int32, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	rows, err := q.TagsForSubscriptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[int32][]string{}
+	for _, row := range rows {
+		result[row.SubscriptionID] = append(result[row.SubscriptionID], row.Name)
+	}
+	return result, nil
+}
+
+// saveTags заменяет теги подписки, создавая недостающие. Если names == nil, теги не меняются.
+func saveTags(ctx context.Context, q *db.Queries, sub db.Subscription, names []string) error {
+	if names == nil {
+		return nil
+	}
+
+	names, err := tags.Normalize(names)
+	if err != nil {
+		return fmt.Errorf("%w: tags: %w", dto.ErrInvalid, err)
+	}
+
+	err = q.DeleteSubscriptionTags(ctx, db.DeleteSubscriptionTagsParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		tag, err := q.UpsertTag(ctx, db.UpsertTagParams{
+			TenantID: sub.TenantID,
+			Name:     name,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.AddSubscriptionTag(ctx, db.AddSubscriptionTagParams{
+			SubscriptionID: sub.ID,
+			TagID:          tag.ID,
+			TenantID:       sub.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+)
+
+var (
+	ErrInvalid = errors.New("invalid tag")
+	ErrExists  = errors.New("tag already exists, use merge")
+)
+
+const maxLength = 64
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+// List возвращает теги тенанта с количеством подписок
+func (s *Services) List(ctx context.Context) (*[]dto.Tag, error) {
+	tags := []dto.Tag{}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		list, err := q.TagsList(ctx, tenancy.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		for _, el := range list {
+			tags = append(tags, dto.Tag{
+				ID:            el.ID,
+				Name:          el.Name,
+				Subscriptions: el.Subscriptions,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tags, nil
+}
+
+// Rename переименовывает тег у всех подписок. Если тег с новым названием уже есть, нужно слияние.
+func (s *Services) Rename(ctx context.Context, rename dto.TagRename) error {
+	to, err := normalize(rename.To)
+	if err != nil {
+		return err
+	}
+
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		source, err := q.GetTagByName(ctx, db.GetTagByNameParams{
+			TenantID: tenancy.FromContext(ctx),
+			Name:     rename.From,
+		})
+		if err != nil {
+			return err
+		}
+
+		target, err := q.GetTagByName(ctx, db.GetTagByNameParams{
+			TenantID: tenancy.FromContext(ctx),
+			Name:     to,
+		})
+		if err == nil && target.ID != source.ID {
+			return ErrExists
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		_, err = q.RenameTag(ctx, db.RenameTagParams{
+			ID:       source.ID,
+			TenantID: source.TenantID,
+			NewName:  to,
+		})
+		return err
+	})
+}
+
+// Merge переносит подписки с тегов merge.From на тег merge.To и удаляет теги merge.From
+func (s *Services) Merge(ctx context.Context, merge dto.TagMerge) error {
+	to, err := normalize(merge.To)
+	if err != nil {
+		return err
+	}
+	if len(merge.From) == 0 {
+		return fmt.Errorf("%w: from is required", ErrInvalid)
+	}
+
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		target, err := q.UpsertTag(ctx, db.UpsertTagParams{
+			TenantID: tenancy.FromContext(ctx),
+			Name:     to,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range merge.From {
+			source, err := q.GetTagByName(ctx, db.GetTagByNameParams{
+				TenantID: target.TenantID,
+				Name:     name,
+			})
+			if err != nil {
+				return err
+			}
+			if source.ID == target.ID {
+				continue
+			}
+
+			err = q.MoveSubscriptionTags(ctx, db.MoveSubscriptionTagsParams{
+				TargetID: target.ID,
+				SourceID: source.ID,
+				TenantID: target.TenantID,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = q.DeleteTag(ctx, db.DeleteTagParams{
+				ID:       source.ID,
+				TenantID: target.TenantID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Normalize убирает пустые теги и повторы без учёта регистра
+func Normalize(names []string) ([]string, error) {
+	result := []string{}
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		name, err := normalize(name)
+		if errors.Is(err, ErrInvalid) && name == "" {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(name)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// Keys переводит теги в нижний регистр для фильтрации
+func Keys(names []string) []string {
+	keys := []string{}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			keys = append(keys, strings.ToLower(name))
+		}
+	}
+	return keys
+}
+
+func normalize(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	if utf8.RuneCountInString(name) > maxLength {
+		return name, fmt.Errorf("%w: %q is longer than %d characters", ErrInvalid, name, maxLength)
+	}
+	return name, nil
+}
@@ -0,0 +1,88 @@
+package tenants
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidID = errors.New("tenant id must match ^[a-z0-9][a-z0-9_-]{0,63}$")
+	ErrExists    = errors.New("tenant already exists")
+)
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+func (s *Services) List(ctx context.Context) (*[]dto.Tenant, error) {
+	list, err := s.queries.TenantsList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := []dto.Tenant{}
+
+	for _, el := range list {
+		tenants = append(tenants, dto.TenantFromSql(el))
+	}
+	return &tenants, nil
+}
+
+func (s *Services) Create(ctx context.Context, tenant dto.Tenant) (*dto.Tenant, error) {
+	if !idPattern.MatchString(tenant.ID) {
+		return nil, ErrInvalidID
+	}
+
+	// Тенант создаётся вместе с начальным каталогом сервисов, RLS каталога требует нового тенанта в app.tenant_id
+	var created db.Tenant
+	err := tenancy.InTx(tenancy.WithTenant(ctx, tenant.ID), s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		created, err = q.CreateTenant(ctx, db.CreateTenantParams{
+			ID:   tenant.ID,
+			Name: tenant.Name,
+		})
+		if err != nil {
+			return err
+		}
+		return q.SeedServices(ctx, tenant.ID)
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.TenantFromSql(created)
+	return &result, nil
+}
+
+func (s *Services) Exists(ctx context.Context, id string) (bool, error) {
+	if !idPattern.MatchString(id) {
+		return false, nil
+	}
+
+	_, err := s.queries.GetTenant(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,285 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+	"github.com/rs/zerolog/log"
+)
+
+// Состояния доставок в webhook_deliveries
+const (
+	deliveryPending   = "pending"
+	deliveryDelivered = "delivered"
+	deliveryFailed    = "failed"
+)
+
+const (
+	deliveryBatch = 100
+	// Сколько доставок пачки отправляется одновременно
+	deliveryWorkers = 10
+	// Запас времени закрепления сверх отправки пачки, см. lease
+	deliveryLease = time.Minute
+	// Предельная задержка перед повторной попыткой
+	maxRetryDelay = 24 * time.Hour
+)
+
+// Config - настройки отправки вебхуков
+type Config struct {
+	// Как часто проверять очередь доставок
+	Interval time.Duration
+	// Количество попыток, после которого доставка помечается failed
+	MaxAttempts int
+	// Задержка перед первой повторной попыткой, дальше удваивается
+	RetryDelay time.Duration
+	// Таймаут запроса к получателю
+	Timeout time.Duration
+}
+
+// RunDeliveries отправляет события получателям каждые config.Interval, пока не отменён ctx
+func (s *Services) RunDeliveries(ctx context.Context, config Config) {
+	client := &http.Client{Timeout: config.Timeout}
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Deliver(ctx, config, client); err != nil {
+			log.Error().Err(err).Msg("can't deliver webhooks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver отправляет все доставки всех тенантов, время попытки которых наступило.
+// Неудачная попытка повторяется с экспоненциальной задержкой, каждая попытка записывается в webhook_attempts.
+func (s *Services) Deliver(ctx context.Context, config Config, client *http.Client) error {
+	ctx = tenancy.WithTenant(ctx, tenancy.All)
+	lease := leaseDuration(config)
+
+	for {
+		var deliveries []db.WebhookDelivery
+		var targets []db.DeliveryTargetsRow
+		err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+			var err error
+			deliveries, err = q.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+				LeaseUntil: time.Now().Add(lease),
+				Batch:      deliveryBatch,
+			})
+			if err != nil || len(deliveries) == 0 {
+				return err
+			}
+
+			ids := make([]int64, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				ids = append(ids, delivery.ID)
+			}
+			targets, err = q.DeliveryTargets(ctx, ids)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		byID := map[int64]db.DeliveryTargetsRow{}
+		for _, target := range targets {
+			byID[target.ID] = target
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var recordErr error
+		workers := make(chan struct{}, deliveryWorkers)
+		for _, delivery := range deliveries {
+			target, ok := byID[delivery.ID]
+			if !ok {
+				continue
+			}
+
+			workers <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-workers
+					wg.Done()
+				}()
+
+				started := time.Now()
+				statusCode, sendErr := send(ctx, client, target)
+				duration := time.Since(started)
+
+				err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+					return recordAttempt(ctx, q, config, delivery, statusCode, sendErr, duration)
+				})
+				if err != nil {
+					mu.Lock()
+					recordErr = err
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		if recordErr != nil {
+			return recordErr
+		}
+
+		if len(deliveries) < deliveryBatch {
+			return nil
+		}
+	}
+}
+
+// PurgeEvents удаляет события всех тенантов старше olderThan вместе с доставками и попытками.
+// События, доставка которых ещё не завершена, не удаляются.
+func (s *Services) PurgeEvents(ctx context.Context, olderThan time.Time) (int64, error) {
+	var purged int64
+	err := tenancy.InTx(tenancy.WithTenant(ctx, tenancy.All), s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		purged, err = q.PurgeWebhookEvents(ctx, olderThan)
+		return err
+	})
+	return purged, err
+}
+
+// RunPurge раз в interval удаляет события старше retention, пока не отменён ctx
+func (s *Services) RunPurge(ctx context.Context, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeEvents(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Error().Err(err).Msg("can't purge webhook events")
+		} else if purged > 0 {
+			log.Info().Int64("count", purged).Msg("purged webhook events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordAttempt записывает попытку и результат доставки
+func recordAttempt(ctx context.Context, q *db.Queries, config Config, delivery db.WebhookDelivery, statusCode int, sendErr error, duration time.Duration) error {
+	code := sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0}
+	errText := sql.NullString{}
+	if sendErr != nil {
+		errText = sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+
+	err := q.CreateWebhookAttempt(ctx, db.CreateWebhookAttemptParams{
+		TenantID:   delivery.TenantID,
+		DeliveryID: delivery.ID,
+		StatusCode: code,
+		Error:      errText,
+		DurationMs: int32(duration.Milliseconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if sendErr == nil {
+		return q.MarkDeliveryDelivered(ctx, db.MarkDeliveryDeliveredParams{ID: delivery.ID, LastStatusCode: code})
+	}
+
+	log.Warn().Err(sendErr).Int64("delivery_id", delivery.ID).Int32("attempts", delivery.Attempts).Msg("can't deliver webhook")
+
+	status := deliveryPending
+	if int(delivery.Attempts) >= config.MaxAttempts {
+		status = deliveryFailed
+	}
+	return q.MarkDeliveryFailed(ctx, db.MarkDeliveryFailedParams{
+		ID:             delivery.ID,
+		Status:         status,
+		LastStatusCode: code,
+		LastError:      errText,
+		NextAttemptAt:  time.Now().Add(retryDelay(config.RetryDelay, delivery.Attempts)),
+	})
+}
+
+// leaseDuration возвращает время, на которое закрепляется пачка доставок: её отправка deliveryWorkers
+// запросами по config.Timeout и запас deliveryLease. Закрепление не должно истечь до отметки
+// о результате, иначе другая реплика отправит те же доставки. Если процесс упадёт, события
+// будут отправлены повторно после истечения закрепления.
+func leaseDuration(config Config) time.Duration {
+	rounds := (deliveryBatch + deliveryWorkers - 1) / deliveryWorkers
+	return time.Duration(rounds)*config.Timeout + deliveryLease
+}
+
+// retryDelay возвращает задержку перед следующей попыткой: base, удваиваемая после каждой
+// неудачной попытки, но не больше maxRetryDelay
+func retryDelay(base time.Duration, attempts int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// send отправляет событие получателю. Успешной считается доставка с ответом 2xx.
+func send(ctx context.Context, client *http.Client, target db.DeliveryTargetsRow) (int, error) {
+	body, err := json.Marshal(dto.Event{
+		ID:             target.EventID,
+		Type:           target.EventType,
+		SubscriptionID: target.SubscriptionID,
+		CreatedAt:      target.CreatedAt,
+		Data:           target.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", target.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(target.ID, 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(target.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает подпись HMAC-SHA256 (hex) строки "<timestamp>.<body>"
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 4, want: 4 * time.Minute},
+		{attempts: 12, want: 30 * time.Second << 11},
+		{attempts: 13, want: maxRetryDelay},
+		// Сдвиг на 64 и больше переполнил бы Duration
+		{attempts: 70, want: maxRetryDelay},
+		{attempts: 1 << 30, want: maxRetryDelay},
+	}
+
+	for _, c := range cases {
+		if got := retryDelay(30*time.Second, c.attempts); got != c.want {
+			t.Errorf("retryDelay(30s, %d) = %s, want %s", c.attempts, got, c.want)
+		}
+	}
+}
+
+func TestLeaseDuration(t *testing.T) {
+	timeout := 10 * time.Second
+	lease := leaseDuration(Config{Timeout: timeout})
+
+	rounds := (deliveryBatch + deliveryWorkers - 1) / deliveryWorkers
+	if lease <= time.Duration(rounds)*timeout {
+		t.Errorf("lease %s doesn't cover %d rounds of %s", lease, rounds, timeout)
+	}
+}
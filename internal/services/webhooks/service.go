@@ -0,0 +1,377 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+	"github.com/feproldo/effective-mobile/internal/dto"
+	"github.com/feproldo/effective-mobile/internal/tenancy"
+)
+
+// Типы событий подписок
+const (
+	EventCreated     = "subscription.created"
+	EventUpdated     = "subscription.updated"
+	EventDeleted     = "subscription.deleted"
+	EventRestored    = "subscription.restored"
+	EventPaused      = "subscription.paused"
+	EventResumed     = "subscription.resumed"
+	EventCancelled   = "subscription.cancelled"
+	EventReactivated = "subscription.reactivated"
+)
+
+var Events = []string{
+	EventCreated,
+	EventUpdated,
+	EventDeleted,
+	EventRestored,
+	EventPaused,
+	EventResumed,
+	EventCancelled,
+	EventReactivated,
+}
+
+var (
+	ErrInvalid  = errors.New("invalid webhook")
+	ErrConflict = errors.New("delivery is already pending")
+)
+
+type Services struct {
+	conn    *sql.DB
+	queries *db.Queries
+}
+
+func NewService(conn *sql.DB, queries *db.Queries) *Services {
+	return &Services{
+		conn:    conn,
+		queries: queries,
+	}
+}
+
+// Publish записывает событие в outbox, создаёт доставки подходящим получателям тенанта
+// и сообщает о событии всем репликам через NOTIFY subscription_events.
+// q должен быть привязан к транзакции, в которой выполняется само изменение.
+func Publish(ctx context.Context, q *db.Queries, eventType string, subscriptionID int32, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tenantID := tenancy.FromContext(ctx)
+	event, err := q.CreateWebhookEvent(ctx, db.CreateWebhookEventParams{
+		TenantID:       tenantID,
+		EventType:      eventType,
+		SubscriptionID: subscriptionID,
+		Payload:        payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.CreateWebhookDeliveries(ctx, db.CreateWebhookDeliveriesParams{
+		EventID:   event.ID,
+		TenantID:  tenantID,
+		EventType: eventType,
+	})
+	if err != nil {
+		return err
+	}
+
+	// NOTIFY доставляется слушателям только после фиксации транзакции
+	return q.NotifyWebhookEvent(ctx, strconv.FormatInt(event.ID, 10))
+}
+
+// PublishMany записывает события одного типа по нескольким подпискам так же, как Publish, но одной командой
+// на каждый шаг. data[i] - данные события подписки subscriptionIDs[i].
+func PublishMany(ctx context.Context, q *db.Queries, eventType string, subscriptionIDs []int32, data []any) error {
+	payloads := make([]string, 0, len(data))
+	for _, v := range data {
+		payload, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		payloads = append(payloads, string(payload))
+	}
+
+	tenantID := tenancy.FromContext(ctx)
+	ids, err := q.CreateWebhookEvents(ctx, db.CreateWebhookEventsParams{
+		TenantID:        tenantID,
+		EventType:       eventType,
+		SubscriptionIds: subscriptionIDs,
+		Payloads:        payloads,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.CreateWebhookDeliveriesForEvents(ctx, db.CreateWebhookDeliveriesForEventsParams{
+		EventIds:  ids,
+		TenantID:  tenantID,
+		EventType: eventType,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.NotifyWebhookEvents(ctx, ids)
+}
+
+func (s *Services) List(ctx context.Context) (*[]dto.Webhook, error) {
+	webhooks := []dto.Webhook{}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		list, err := q.WebhooksList(ctx, tenancy.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		for _, el := range list {
+			webhooks = append(webhooks, dto.WebhookFromSql(el))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhooks, nil
+}
+
+func (s *Services) Get(ctx context.Context, id int32) (*dto.Webhook, error) {
+	var webhook dto.Webhook
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		webhookSql, err := q.GetWebhook(ctx, db.GetWebhookParams{ID: id, TenantID: tenancy.FromContext(ctx)})
+		if err != nil {
+			return err
+		}
+
+		webhook = dto.WebhookFromSql(webhookSql)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// Create регистрирует получателя. Ключ подписи возвращается только в ответе на создание.
+func (s *Services) Create(ctx context.Context, webhook dto.Webhook) (*dto.Webhook, error) {
+	if err := validate(webhook); err != nil {
+		return nil, err
+	}
+
+	secret := webhook.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	var created dto.Webhook
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		webhookSql, err := q.CreateWebhook(ctx, db.CreateWebhookParams{
+			TenantID: tenancy.FromContext(ctx),
+			Url:      webhook.URL,
+			Secret:   secret,
+			Events:   events(webhook),
+			Active:   webhook.Active,
+		})
+		if err != nil {
+			return err
+		}
+
+		created = dto.WebhookFromSql(webhookSql)
+		created.Secret = webhookSql.Secret
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// Update меняет адрес, события и активность получателя. Ключ подписи не меняется.
+func (s *Services) Update(ctx context.Context, id int32, webhook dto.Webhook) (*dto.Webhook, error) {
+	if err := validate(webhook); err != nil {
+		return nil, err
+	}
+
+	var updated dto.Webhook
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		webhookSql, err := q.UpdateWebhook(ctx, db.UpdateWebhookParams{
+			ID:       id,
+			TenantID: tenancy.FromContext(ctx),
+			Url:      webhook.URL,
+			Events:   events(webhook),
+			Active:   webhook.Active,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated = dto.WebhookFromSql(webhookSql)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (s *Services) Delete(ctx context.Context, id int32) error {
+	return tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		rows, err := q.DeleteWebhook(ctx, db.DeleteWebhookParams{ID: id, TenantID: tenancy.FromContext(ctx)})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// Deliveries возвращает последние 100 доставок получателю, status - фильтр по состоянию
+func (s *Services) Deliveries(ctx context.Context, webhookID int32, status string) (*[]dto.WebhookDelivery, error) {
+	if status != "" && !slices.Contains([]string{deliveryPending, deliveryDelivered, deliveryFailed}, status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalid, status)
+	}
+
+	deliveries := []dto.WebhookDelivery{}
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		tenantID := tenancy.FromContext(ctx)
+		if _, err := q.GetWebhook(ctx, db.GetWebhookParams{ID: webhookID, TenantID: tenantID}); err != nil {
+			return err
+		}
+
+		list, err := q.WebhookDeliveries(ctx, db.WebhookDeliveriesParams{
+			WebhookID: webhookID,
+			TenantID:  tenantID,
+			Status:    status,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, el := range list {
+			deliveries = append(deliveries, dto.WebhookDeliveryFromSql(db.GetWebhookDeliveryRow(el)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &deliveries, nil
+}
+
+// Delivery возвращает доставку со всеми попытками
+func (s *Services) Delivery(ctx context.Context, webhookID int32, id int64) (*dto.WebhookDelivery, error) {
+	var delivery dto.WebhookDelivery
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		var err error
+		delivery, err = loadDelivery(ctx, q, webhookID, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// Replay ставит доставку в очередь заново с полным числом попыток
+func (s *Services) Replay(ctx context.Context, webhookID int32, id int64) (*dto.WebhookDelivery, error) {
+	var delivery dto.WebhookDelivery
+	err := tenancy.InTx(ctx, s.conn, s.queries, func(q *db.Queries) error {
+		rows, err := q.ReplayWebhookDelivery(ctx, db.ReplayWebhookDeliveryParams{
+			ID:        id,
+			WebhookID: webhookID,
+			TenantID:  tenancy.FromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		delivery, err = loadDelivery(ctx, q, webhookID, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func loadDelivery(ctx context.Context, q *db.Queries, webhookID int32, id int64) (dto.WebhookDelivery, error) {
+	tenantID := tenancy.FromContext(ctx)
+	deliverySql, err := q.GetWebhookDelivery(ctx, db.GetWebhookDeliveryParams{
+		ID:        id,
+		WebhookID: webhookID,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return dto.WebhookDelivery{}, err
+	}
+
+	attempts, err := q.WebhookAttempts(ctx, db.WebhookAttemptsParams{DeliveryID: id, TenantID: tenantID})
+	if err != nil {
+		return dto.WebhookDelivery{}, err
+	}
+
+	delivery := dto.WebhookDeliveryFromSql(deliverySql)
+	delivery.AttemptsLog = []dto.WebhookAttempt{}
+	for _, attempt := range attempts {
+		delivery.AttemptsLog = append(delivery.AttemptsLog, dto.WebhookAttemptFromSql(attempt))
+	}
+
+	return delivery, nil
+}
+
+func validate(webhook dto.Webhook) error {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) url", ErrInvalid)
+	}
+
+	for _, event := range webhook.Events {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalid, event)
+		}
+	}
+
+	return nil
+}
+
+func events(webhook dto.Webhook) []string {
+	if webhook.Events == nil {
+		return []string{}
+	}
+	return webhook.Events
+}
+
+func newSecret() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
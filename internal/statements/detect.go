@@ -0,0 +1,323 @@
+package statements
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+)
+
+// Похожесть названий, с которой списания считаются списаниями одного продавца
+const SimilarNames = 0.8
+
+// Слова, которые банки добавляют к названию продавца
+var noise = map[string]bool{
+	"www": true, "com": true, "ru": true, "net": true, "org": true, "io": true, "tv": true,
+	"pos": true, "card": true, "payment": true, "purchase": true, "bill": true, "billing": true,
+	"оплата": true, "покупка": true, "списание": true, "карта": true, "платеж": true, "платёж": true,
+}
+
+// Интервалы между списаниями в днях, которые подходят под периодичность
+var periods = []struct {
+	kind     string
+	min, max float64
+	// Сколько списаний нужно для полной уверенности
+	charges int
+}{
+	{billing.Monthly, 26, 35, 3},
+	{billing.Yearly, 350, 380, 2},
+}
+
+type DetectOptions struct {
+	// Допустимое отклонение суммы списания от средней, доля от 0 до 1
+	AmountTolerance float64
+	// Наименьшая уверенность, с которой списания считаются подпиской
+	MinConfidence float64
+}
+
+// Recurring - повторяющиеся списания одного продавца на примерно одну сумму
+type Recurring struct {
+	// Нормализованное название продавца
+	Key string
+	// Самое частое название продавца в выписке
+	Merchant string
+	Currency string
+	// Медиана сумм списаний
+	Amount float64
+	// billing.Monthly или billing.Yearly
+	Period  string
+	Charges []Transaction
+	// Доля интервалов, подходящих под периодичность, с поправкой на разброс сумм и число списаний
+	Confidence float64
+}
+
+func (r Recurring) First() time.Time {
+	return r.Charges[0].Date
+}
+
+func (r Recurring) Last() time.Time {
+	return r.Charges[len(r.Charges)-1].Date
+}
+
+// Next возвращает ожидаемую дату следующего списания. Как и в billing, списание 31 числа
+// переносится на последний день короткого месяца
+func (r Recurring) Next() time.Time {
+	if r.Period == billing.Yearly {
+		return billing.AddMonths(r.Last(), 12)
+	}
+	return billing.AddMonths(r.Last(), 1)
+}
+
+// Detect находит повторяющиеся списания: группирует списания по похожести названия продавца,
+// внутри продавца - по сумме с допуском, и проверяет, что интервалы между ними месячные или годовые.
+// Списания - операции с отрицательной суммой; если таких нет, списаниями считаются все операции.
+func Detect(transactions []Transaction, options DetectOptions) []Recurring {
+	charges := []Transaction{}
+	for _, transaction := range transactions {
+		if transaction.Amount < 0 {
+			transaction.Amount = -transaction.Amount
+			charges = append(charges, transaction)
+		}
+	}
+	if len(charges) == 0 {
+		for _, transaction := range transactions {
+			if transaction.Amount > 0 {
+				charges = append(charges, transaction)
+			}
+		}
+	}
+	sort.SliceStable(charges, func(i, j int) bool { return charges[i].Date.Before(charges[j].Date) })
+
+	type merchant struct {
+		key     string
+		charges []Transaction
+	}
+	var merchants []*merchant
+	for _, charge := range charges {
+		key := Normalize(charge.Description)
+
+		var best *merchant
+		bestScore := 0.0
+		for _, m := range merchants {
+			if score := Similarity(m.key, key); score >= SimilarNames && score > bestScore {
+				best, bestScore = m, score
+			}
+		}
+		if best == nil {
+			best = &merchant{key: key}
+			merchants = append(merchants, best)
+		}
+		best.charges = append(best.charges, charge)
+	}
+
+	var result []Recurring
+	for _, m := range merchants {
+		for _, group := range byAmount(m.charges, options.AmountTolerance) {
+			recurring, ok := periodic(group)
+			if !ok || recurring.Confidence < options.MinConfidence {
+				continue
+			}
+			recurring.Key = m.key
+			result = append(result, recurring)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Confidence != result[j].Confidence {
+			return result[i].Confidence > result[j].Confidence
+		}
+		return result[i].Amount > result[j].Amount
+	})
+	return result
+}
+
+// byAmount делит списания продавца на группы, в которых сумма отличается от средней не больше чем на tolerance
+func byAmount(charges []Transaction, tolerance float64) [][]Transaction {
+	type group struct {
+		sum     float64
+		charges []Transaction
+	}
+	var groups []*group
+	for _, charge := range charges {
+		var found *group
+		for _, g := range groups {
+			mean := g.sum / float64(len(g.charges))
+			if g.charges[0].Currency == charge.Currency && math.Abs(charge.Amount-mean) <= mean*tolerance {
+				found = g
+				break
+			}
+		}
+		if found == nil {
+			found = &group{}
+			groups = append(groups, found)
+		}
+		found.sum += charge.Amount
+		found.charges = append(found.charges, charge)
+	}
+
+	result := make([][]Transaction, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, g.charges)
+	}
+	return result
+}
+
+// periodic проверяет, что списания идут раз в месяц или раз в год
+func periodic(charges []Transaction) (Recurring, bool) {
+	if len(charges) < 2 {
+		return Recurring{}, false
+	}
+
+	intervals := make([]float64, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals = append(intervals, charges[i].Date.Sub(charges[i-1].Date).Hours()/24)
+	}
+
+	interval := median(intervals)
+	for _, period := range periods {
+		if interval < period.min || interval > period.max {
+			continue
+		}
+
+		fit := 0
+		for _, days := range intervals {
+			if days >= period.min && days <= period.max {
+				fit++
+			}
+		}
+
+		amounts := make([]float64, len(charges))
+		for i, charge := range charges {
+			amounts[i] = charge.Amount
+		}
+
+		confidence := float64(fit) / float64(len(intervals))
+		confidence *= 1 - math.Min(1, deviation(amounts)/mean(amounts))
+		confidence *= math.Min(1, float64(len(charges))/float64(period.charges))
+
+		return Recurring{
+			Merchant:   commonDescription(charges),
+			Currency:   charges[0].Currency,
+			Amount:     median(amounts),
+			Period:     period.kind,
+			Charges:    charges,
+			Confidence: math.Round(confidence*100) / 100,
+		}, true
+	}
+	return Recurring{}, false
+}
+
+// Normalize приводит название продавца к словам в нижнем регистре без цифр, знаков и служебных слов банка
+func Normalize(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	result := []string{}
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !noise[word] {
+			result = append(result, word)
+		}
+	}
+	if len(result) == 0 {
+		return strings.ToLower(strings.TrimSpace(name))
+	}
+	return strings.Join(result, " ")
+}
+
+// Similarity - похожесть нормализованных названий от 0 до 1: 0.9, если слова одного названия
+// входят в другое (yandex plus и yandex plus moscow), иначе доля совпадения по расстоянию Левенштейна
+func Similarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	short, long := strings.Fields(a), strings.Fields(b)
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	if len(short) > 0 && len([]rune(strings.Join(short, ""))) >= 3 {
+		contained := true
+		for _, word := range short {
+			if !slices.Contains(long, word) {
+				contained = false
+				break
+			}
+		}
+		if contained {
+			return 0.9
+		}
+	}
+
+	x, y := []rune(strings.ReplaceAll(a, " ", "")), []rune(strings.ReplaceAll(b, " ", ""))
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(x, y))/float64(max(len(x), len(y)))
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func commonDescription(charges []Transaction) string {
+	counts := map[string]int{}
+	best := charges[len(charges)-1].Description
+	for _, charge := range charges {
+		counts[charge.Description]++
+		if counts[charge.Description] > counts[best] {
+			best = charge.Description
+		}
+	}
+	return best
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// deviation - стандартное отклонение
+func deviation(values []float64) float64 {
+	m := mean(values)
+	sum := 0.0
+	for _, value := range values {
+		sum += (value - m) * (value - m)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
@@ -0,0 +1,162 @@
+package statements
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/billing"
+)
+
+func charge(date string, description string, amount float64) Transaction {
+	parsed, _ := time.Parse(time.DateOnly, date)
+	return Transaction{Date: parsed, Description: description, Amount: amount, Currency: "RUB"}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"NETFLIX.COM 866-579-7172", "netflix"},
+		{"Оплата Яндекс Плюс", "яндекс плюс"},
+		{"POS PURCHASE SPOTIFY AB", "spotify ab"},
+		{"123", "123"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.name); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		similar bool
+	}{
+		{"netflix", "netflix", true},
+		{"yandex plus", "yandex plus moscow", true},
+		{"spotify", "spotfy", true},
+		{"netflix", "spotify", false},
+		{"ivi", "okko", false},
+		{"", "netflix", false},
+	}
+
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); (got >= SimilarNames) != tt.similar {
+			t.Errorf("Similarity(%q, %q) = %v, want similar %v", tt.a, tt.b, got, tt.similar)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	options := DetectOptions{AmountTolerance: 0.1, MinConfidence: 0.5}
+
+	type want struct {
+		key        string
+		period     string
+		amount     float64
+		charges    int
+		confidence float64
+	}
+	tests := []struct {
+		name         string
+		transactions []Transaction
+		want         []want
+	}{
+		{
+			name: "monthly with varying merchant names",
+			transactions: []Transaction{
+				charge("2025-03-14", "NETFLIX.COM 866", -399),
+				charge("2025-04-14", "NETFLIX.COM 123", -399),
+				charge("2025-05-14", "Netflix.com", -399),
+				charge("2025-06-15", "NETFLIX.COM", -399),
+				charge("2025-04-02", "Пятёрочка", -1234.5),
+				charge("2025-05-20", "Пятёрочка", -300),
+				charge("2025-05-01", "Зарплата", 100000),
+			},
+			want: []want{{key: "netflix", period: billing.Monthly, amount: 399, charges: 4, confidence: 1}},
+		},
+		{
+			name: "yearly",
+			transactions: []Transaction{
+				charge("2024-01-15", "Adobe", -120),
+				charge("2025-01-15", "ADOBE INC", -120),
+			},
+			want: []want{{key: "adobe", period: billing.Yearly, amount: 120, charges: 2, confidence: 1}},
+		},
+		{
+			name: "amounts out of tolerance are separate groups",
+			transactions: []Transaction{
+				charge("2025-01-10", "Spotify", -169),
+				charge("2025-02-10", "Spotify", -169),
+				charge("2025-03-10", "Spotify", -169),
+				charge("2025-01-20", "Spotify", -299),
+				charge("2025-03-05", "Spotify", -299),
+			},
+			want: []want{{key: "spotify", period: billing.Monthly, amount: 169, charges: 3, confidence: 1}},
+		},
+		{
+			name: "two monthly charges are less confident",
+			transactions: []Transaction{
+				charge("2025-01-10", "Okko", -299),
+				charge("2025-02-10", "Okko", -299),
+			},
+			want: []want{{key: "okko", period: billing.Monthly, amount: 299, charges: 2, confidence: 0.67}},
+		},
+		{
+			name: "positive amounts when there are no negative ones",
+			transactions: []Transaction{
+				charge("2025-01-10", "Okko", 299),
+				charge("2025-02-10", "Okko", 299),
+				charge("2025-03-10", "Okko", 299),
+			},
+			want: []want{{key: "okko", period: billing.Monthly, amount: 299, charges: 3, confidence: 1}},
+		},
+		{
+			name: "irregular charges",
+			transactions: []Transaction{
+				charge("2025-01-10", "Taxi", -299),
+				charge("2025-01-12", "Taxi", -299),
+				charge("2025-03-01", "Taxi", -299),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got := Detect(tt.transactions, options)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Detect = %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i, recurring := range got {
+			w := tt.want[i]
+			if recurring.Key != w.key || recurring.Period != w.period || recurring.Amount != w.amount ||
+				len(recurring.Charges) != w.charges || math.Abs(recurring.Confidence-w.confidence) > 1e-9 {
+				t.Errorf("%s: recurring %d = %s %s %v %d %v, want %+v", tt.name, i,
+					recurring.Key, recurring.Period, recurring.Amount, len(recurring.Charges), recurring.Confidence, w)
+			}
+		}
+	}
+}
+
+func TestRecurringNext(t *testing.T) {
+	tests := []struct {
+		period string
+		last   string
+		want   string
+	}{
+		{billing.Monthly, "2025-01-31", "2025-02-28"},
+		{billing.Monthly, "2025-06-15", "2025-07-15"},
+		{billing.Yearly, "2025-01-15", "2026-01-15"},
+	}
+
+	for _, tt := range tests {
+		recurring := Recurring{Period: tt.period, Charges: []Transaction{charge(tt.last, "x", 1)}}
+		if got := recurring.Next().Format(time.DateOnly); got != tt.want {
+			t.Errorf("Next(%s, %s) = %s, want %s", tt.period, tt.last, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package statements
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+// Элемент OFX: в OFX 1.x (SGML) у элементов со значением может не быть закрывающего тега,
+// поэтому значение - текст до следующего тега
+var ofxElement = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+// parseOFX читает операции STMTTRN из OFX 1.x и 2.x
+func parseOFX(data []byte, options Options) ([]Transaction, []dto.ImportRowError, error) {
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, nil, fmt.Errorf("%w: OFX element not found", dto.ErrInvalid)
+	}
+
+	currency := options.Currency
+	var list []Transaction
+	var rowErrors []dto.ImportRowError
+
+	var fields map[string]string
+	line := 0
+	for _, match := range ofxElement.FindAllSubmatchIndex(data[start:], -1) {
+		closing := match[3] > match[2]
+		tag := strings.ToUpper(string(data[start+match[4] : start+match[5]]))
+		value := html.UnescapeString(strings.TrimSpace(string(data[start+match[6] : start+match[7]])))
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			fields = map[string]string{}
+			line = bytes.Count(data[:start+match[0]], []byte("\n")) + 1
+		case tag == "STMTTRN" && closing:
+			if fields == nil {
+				continue
+			}
+
+			description := fields["NAME"]
+			if description == "" {
+				description = fields["MEMO"]
+			}
+			transaction, err := newTransaction(line, fields["DTPOSTED"], description, fields["TRNAMT"], fields["CURSYM"], Options{
+				DateLayout: "20060102",
+				Currency:   currency,
+			})
+			if err != nil {
+				rowErrors = append(rowErrors, dto.ImportRowError{Row: line, Error: err.Error()})
+			} else {
+				list = append(list, transaction)
+			}
+			fields = nil
+		case tag == "CURDEF" && !closing && value != "":
+			currency = strings.ToUpper(value)
+		case fields != nil && !closing && value != "":
+			fields[tag] = value
+		}
+	}
+
+	if len(list) == 0 && len(rowErrors) == 0 {
+		return nil, nil, fmt.Errorf("%w: no transactions in OFX", dto.ErrInvalid)
+	}
+	return list, rowErrors, nil
+}
@@ -0,0 +1,66 @@
+package statements
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+// parseQIF читает операции QIF: строка начинается с кода поля (D - дата, T или U - сумма,
+// P - получатель, M - комментарий), ^ завершает операцию
+func parseQIF(data []byte, options Options) ([]Transaction, []dto.ImportRowError, error) {
+	var list []Transaction
+	var rowErrors []dto.ImportRowError
+
+	fields := map[byte]string{}
+	start := 0
+	flush := func() {
+		if len(fields) == 0 {
+			return
+		}
+
+		description := fields['P']
+		if description == "" {
+			description = fields['M']
+		}
+		amount := fields['T']
+		if amount == "" {
+			amount = fields['U']
+		}
+
+		transaction, err := newTransaction(start, qifDate(fields['D']), description, amount, "", options)
+		if err != nil {
+			rowErrors = append(rowErrors, dto.ImportRowError{Row: start, Error: err.Error()})
+		} else {
+			list = append(list, transaction)
+		}
+		fields = map[byte]string{}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+		case text[0] == '!':
+			flush()
+		case text[0] == '^':
+			flush()
+		default:
+			if len(fields) == 0 {
+				start = line
+			}
+			fields[text[0]] = strings.TrimSpace(text[1:])
+		}
+	}
+	flush()
+
+	return list, rowErrors, scanner.Err()
+}
+
+// qifDate приводит дату QIF вида 7/ 1'25 к 7/1/25
+func qifDate(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "'", "/"), " ", "")
+}
@@ -0,0 +1,306 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+// Форматы выписок
+const (
+	FormatCSV = "csv"
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+// Поля операции, которые читаются из CSV
+var Columns = []string{"date", "description", "amount", "currency"}
+
+// Названия колонок CSV по умолчанию, в порядке предпочтения
+var columnNames = map[string][]string{
+	"date":        {"date", "transaction date", "posted date", "booking date", "дата операции", "дата", "дата платежа"},
+	"description": {"description", "payee", "merchant", "name", "details", "описание", "описание операции", "назначение платежа", "контрагент"},
+	"amount":      {"amount", "sum", "сумма операции", "сумма", "сумма платежа"},
+	"currency":    {"currency", "валюта операции", "валюта"},
+}
+
+// Форматы дат, которые пробуются, если формат не задан
+var dateLayouts = []string{"2006-01-02", "02.01.2006", "02.01.06", "01/02/2006", "1/2/2006", "1/2/06", "2006/01/02", "20060102"}
+
+// Transaction - операция по счёту. Списания отрицательные
+type Transaction struct {
+	// Строка файла
+	Line        int
+	Date        time.Time
+	Description string
+	Amount      float64
+	Currency    string
+}
+
+// Statement - операции выписки и строки, которые не удалось разобрать
+type Statement struct {
+	Format       string
+	Transactions []Transaction
+	Errors       []dto.ImportRowError
+}
+
+type Options struct {
+	// csv, ofx или qif. Пустой - определяется по содержимому
+	Format string
+	// Поле операции -> название колонки CSV
+	Mapping map[string]string
+	// Разделитель CSV. 0 - ';', если в заголовке их больше, чем запятых, иначе ','
+	Delimiter rune
+	// Формат дат Go. Пустой - пробуются распространённые форматы
+	DateLayout string
+	// Валюта операций, если в выписке её нет
+	Currency string
+}
+
+// Parse читает операции выписки. Строки, которые не удалось разобрать, возвращаются как ошибки строк.
+func Parse(r io.Reader, options Options) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	format := options.Format
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	statement := Statement{Format: format}
+	switch format {
+	case FormatCSV:
+		statement.Transactions, statement.Errors, err = parseCSV(data, options)
+	case FormatOFX:
+		statement.Transactions, statement.Errors, err = parseOFX(data, options)
+	case FormatQIF:
+		statement.Transactions, statement.Errors, err = parseQIF(data, options)
+	default:
+		return nil, fmt.Errorf("%w: format must be one of csv, ofx, qif", dto.ErrInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.Errors == nil {
+		statement.Errors = []dto.ImportRowError{}
+	}
+	return &statement, nil
+}
+
+// DetectFormat определяет формат выписки по содержимому
+func DetectFormat(data []byte) string {
+	head := strings.ToUpper(strings.TrimSpace(string(data[:min(len(data), 1024)])))
+	switch {
+	case strings.HasPrefix(head, "OFXHEADER"), strings.Contains(head, "<OFX>"):
+		return FormatOFX
+	case strings.HasPrefix(head, "!TYPE"), strings.HasPrefix(head, "!ACCOUNT"):
+		return FormatQIF
+	}
+	return FormatCSV
+}
+
+func parseCSV(data []byte, options Options) ([]Transaction, []dto.ImportRowError, error) {
+	for field := range options.Mapping {
+		if !slices.Contains(Columns, field) {
+			return nil, nil, fmt.Errorf("%w: unknown field %q in mapping", dto.ErrInvalid, field)
+		}
+	}
+
+	delimiter := options.Delimiter
+	if delimiter == 0 {
+		header, _, _ := bytes.Cut(data, []byte("\n"))
+		delimiter = ','
+		if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+			delimiter = ';'
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("%w: empty statement", dto.ErrInvalid)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", dto.ErrInvalid, err)
+	}
+
+	columns := map[string]int{}
+	for _, field := range Columns {
+		names := columnNames[field]
+		name, mapped := options.Mapping[field]
+		if mapped {
+			names = []string{name}
+		}
+
+		for _, name := range names {
+			if i := columnIndex(header, name); i >= 0 {
+				columns[field] = i
+				break
+			}
+		}
+		if _, ok := columns[field]; !ok && (mapped || field != "currency") {
+			return nil, nil, fmt.Errorf("%w: column for %s not found", dto.ErrInvalid, field)
+		}
+	}
+
+	var list []Transaction
+	var rowErrors []dto.ImportRowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, dto.ImportRowError{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		line, _ := reader.FieldPos(0)
+		transaction, err := newTransaction(line, get("date"), get("description"), get("amount"), get("currency"), options)
+		if err != nil {
+			rowErrors = append(rowErrors, dto.ImportRowError{Row: line, Error: err.Error()})
+			continue
+		}
+		list = append(list, transaction)
+	}
+
+	return list, rowErrors, nil
+}
+
+func newTransaction(line int, date string, description string, amount string, currency string, options Options) (Transaction, error) {
+	transaction := Transaction{
+		Line:        line,
+		Description: strings.Join(strings.Fields(description), " "),
+		Currency:    strings.ToUpper(currency),
+	}
+	if transaction.Currency == "" {
+		transaction.Currency = options.Currency
+	}
+	if transaction.Description == "" {
+		return transaction, errors.New("description is empty")
+	}
+
+	var err error
+	if transaction.Date, err = parseDate(date, options.DateLayout); err != nil {
+		return transaction, err
+	}
+	if transaction.Amount, err = parseAmount(amount); err != nil {
+		return transaction, err
+	}
+
+	return transaction, nil
+}
+
+// parseDate разбирает дату операции. Время операции после даты отбрасывается
+func parseDate(value string, layout string) (time.Time, error) {
+	layouts := dateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+
+	candidates := []string{value}
+	if day, _, ok := strings.Cut(value, " "); ok {
+		candidates = append(candidates, day)
+	}
+	if len(value) > 8 && value[0] >= '0' && value[0] <= '9' {
+		// Даты OFX: 20250701120000.000[+3:MSK]
+		candidates = append(candidates, value[:8])
+	}
+
+	for _, candidate := range candidates {
+		for _, layout := range layouts {
+			if date, err := time.Parse(layout, candidate); err == nil {
+				return date, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseAmount разбирает сумму с разделителями разрядов, запятой или точкой в дробной части,
+// символами валюты и минусом или скобками у списаний
+func parseAmount(value string) (float64, error) {
+	negative := strings.HasPrefix(strings.TrimSpace(value), "(") && strings.HasSuffix(strings.TrimSpace(value), ")")
+
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',':
+			b.WriteRune(r)
+		case r == '-', r == '−', r == '–':
+			negative = true
+		case unicode.IsSpace(r), r == '\'', r == '(', r == ')', r == '+':
+		case unicode.IsLetter(r), unicode.Is(unicode.Sc, r):
+		default:
+			return 0, fmt.Errorf("invalid amount %q", value)
+		}
+	}
+
+	number := strings.Trim(b.String(), ".,")
+	dot, comma := strings.LastIndex(number, "."), strings.LastIndex(number, ",")
+	switch {
+	case dot >= 0 && comma >= 0:
+		// Последний из разделителей отделяет дробную часть
+		if comma > dot {
+			number = strings.ReplaceAll(number, ".", "")
+			number = strings.Replace(number, ",", ".", 1)
+		} else {
+			number = strings.ReplaceAll(number, ",", "")
+		}
+	case comma >= 0:
+		// Одна запятая с 1-2 цифрами после неё - дробная часть, иначе разделитель разрядов
+		if strings.Count(number, ",") == 1 && len(number)-comma <= 3 {
+			number = strings.Replace(number, ",", ".", 1)
+		} else {
+			number = strings.ReplaceAll(number, ",", "")
+		}
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func columnIndex(header []string, name string) int {
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,155 @@
+package statements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feproldo/effective-mobile/internal/dto"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "-399.00", want: -399},
+		{value: "-399,00", want: -399},
+		{value: "1 234,50", want: 1234.5},
+		{value: "1,234.50", want: 1234.5},
+		{value: "1.234,50", want: 1234.5},
+		{value: "1,234", want: 1234},
+		{value: "(12.99)", want: -12.99},
+		{value: "−9,99 ₽", want: -9.99},
+		{value: "$ 10", want: 10},
+		{value: "abc", wantErr: true},
+		{value: "12#", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.value)
+		if (err != nil) != tt.wantErr || !tt.wantErr && got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, %v, want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		layout  string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2025-07-01", want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "01.07.2025 12:30", want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "07/01/2025", want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "20250701120000.000[+3:MSK]", want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "01/07/2025", layout: "02/01/2006", want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2025-07-01", layout: "02/01/2006", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDate(tt.value, tt.layout)
+		if (err != nil) != tt.wantErr || !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q, %q) = %s, %v, want %s, error %v", tt.value, tt.layout, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		options     Options
+		format      string
+		want        []Transaction
+		errors      int
+		wantInvalid bool
+	}{
+		{
+			name:   "csv with russian columns and semicolons",
+			data:   "\ufeffДата;Описание;Сумма\n14.03.2025;NETFLIX.COM;-399,00\n15.03.2025;;-1\nbad;Spotify;-1\n",
+			format: FormatCSV,
+			want: []Transaction{
+				{Line: 2, Date: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), Description: "NETFLIX.COM", Amount: -399, Currency: "RUB"},
+			},
+			errors: 2,
+		},
+		{
+			name:    "csv with mapping",
+			data:    "When,Who,How much,Cur\n2025-03-14,Spotify AB,-9.99,usd\n",
+			options: Options{Mapping: map[string]string{"date": "When", "description": "Who", "amount": "How much", "currency": "Cur"}},
+			format:  FormatCSV,
+			want: []Transaction{
+				{Line: 2, Date: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), Description: "Spotify AB", Amount: -9.99, Currency: "USD"},
+			},
+		},
+		{
+			name:        "csv without amount column",
+			data:        "date,description\n2025-03-14,Spotify\n",
+			wantInvalid: true,
+		},
+		{
+			name:   "ofx sgml",
+			data:   "OFXHEADER:100\n<OFX>\n<CURDEF>USD\n<STMTTRN>\n<TRNAMT>-9.99\n<DTPOSTED>20250101120000\n<NAME>SPOTIFY &amp; CO\n</STMTTRN>\n</OFX>\n",
+			format: FormatOFX,
+			want: []Transaction{
+				{Line: 4, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Description: "SPOTIFY & CO", Amount: -9.99, Currency: "USD"},
+			},
+		},
+		{
+			name:   "qif",
+			data:   "!Type:Bank\nD01/15/2025\nT-120.00\nPAdobe\n^\nD13/45/2025\nT-1\nPBad\n^\n",
+			format: FormatQIF,
+			want: []Transaction{
+				{Line: 2, Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Description: "Adobe", Amount: -120, Currency: "RUB"},
+			},
+			errors: 1,
+		},
+		{
+			name:        "unknown format",
+			data:        "date,description,amount\n",
+			options:     Options{Format: "xls"},
+			wantInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.options.Currency == "" {
+			tt.options.Currency = "RUB"
+		}
+		statement, err := Parse(strings.NewReader(tt.data), tt.options)
+		if tt.wantInvalid {
+			if !errors.Is(err, dto.ErrInvalid) {
+				t.Errorf("%s: error = %v, want ErrInvalid", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		if statement.Format != tt.format {
+			t.Errorf("%s: format %q, want %q", tt.name, statement.Format, tt.format)
+		}
+		if len(statement.Errors) != tt.errors {
+			t.Errorf("%s: errors %v, want %d", tt.name, statement.Errors, tt.errors)
+		}
+		if len(statement.Transactions) != len(tt.want) {
+			t.Errorf("%s: transactions %+v, want %+v", tt.name, statement.Transactions, tt.want)
+			continue
+		}
+		for i, transaction := range statement.Transactions {
+			want := tt.want[i]
+			if transaction.Line != want.Line || !transaction.Date.Equal(want.Date) || transaction.Description != want.Description ||
+				transaction.Amount != want.Amount || transaction.Currency != want.Currency {
+				t.Errorf("%s: transaction %d = %+v, want %+v", tt.name, i, transaction, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package tenancy
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/feproldo/effective-mobile/internal/db/generated"
+)
+
+const (
+	Default = "default"
+	// All снимает ограничение политик RLS. Только для фоновых задач и админских отчётов.
+	All = "*"
+)
+
+type contextKey struct{}
+
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+func FromContext(ctx context.Context) string {
+	tenantID, ok := ctx.Value(contextKey{}).(string)
+	if !ok || tenantID == "" {
+		return Default
+	}
+	return tenantID
+}
+
+// InTx выполняет fn в транзакции, в которой app.tenant_id выставлен в тенанта из контекста
+func InTx(ctx context.Context, conn *sql.DB, queries *db.Queries, fn func(q *db.Queries) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('app.tenant_id', $1, true)", FromContext(ctx)); err != nil {
+		return err
+	}
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}